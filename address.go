@@ -0,0 +1,179 @@
+package vies
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TraderAddress is the address of a trader split into fields, as guessed
+// by CheckResult.ParsedAddress.
+type TraderAddress struct {
+	// Street holds what comes before the postcode and city, such as the
+	// street, number and building, on one line.
+	Street   string `json:"street,omitempty"`
+	Postcode string `json:"postcode,omitempty"`
+	City     string `json:"city,omitempty"`
+	// CountryCode is the country of the VAT number.
+	CountryCode CountryCode `json:"countryCode,omitempty"`
+}
+
+// postcodeFormats are the patterns of the postcodes of each member state,
+// with the country prefix some addresses carry (AT-1010, LV-1010, L-1111).
+var postcodeFormats = map[string]*regexp.Regexp{
+	"AT": postcodeFormat(`\d{4}`, "AT", "A"),
+	"BE": postcodeFormat(`\d{4}`, "BE", "B"),
+	"BG": postcodeFormat(`\d{4}`, "BG"),
+	"CY": postcodeFormat(`\d{4}`, "CY"),
+	"CZ": postcodeFormat(`\d{3} ?\d{2}`, "CZ"),
+	"DE": postcodeFormat(`\d{5}`, "DE", "D"),
+	"DK": postcodeFormat(`\d{4}`, "DK"),
+	"EE": postcodeFormat(`\d{5}`, "EE"),
+	"EL": postcodeFormat(`\d{3} ?\d{2}`, "GR"),
+	"ES": postcodeFormat(`\d{5}`, "ES", "E"),
+	"FI": postcodeFormat(`\d{5}`, "FI"),
+	"FR": postcodeFormat(`\d{5}`, "FR", "F"),
+	"HR": postcodeFormat(`\d{5}`, "HR"),
+	"HU": postcodeFormat(`\d{4}`, "HU", "H"),
+	"IE": postcodeFormat(`(?:[AC-FHKNPRTV-Y]\d{2}|D6W) ?[0-9AC-FHKNPRTV-Y]{4}`),
+	"IT": postcodeFormat(`\d{5}`, "IT", "I"),
+	"LT": postcodeFormat(`\d{5}`, "LT"),
+	"LU": postcodeFormat(`\d{4}`, "LU", "L"),
+	"LV": postcodeFormat(`\d{4}`, "LV"),
+	"MT": postcodeFormat(`[A-Z]{3} ?\d{4}`),
+	"NL": postcodeFormat(`\d{4} ?[A-Z]{2}`, "NL"),
+	"PL": postcodeFormat(`\d{2}-\d{3}`, "PL"),
+	"PT": postcodeFormat(`\d{4}-\d{3}`, "PT"),
+	"RO": postcodeFormat(`\d{6}`, "RO"),
+	"SE": postcodeFormat(`\d{3} ?\d{2}`, "SE", "S"),
+	"SI": postcodeFormat(`\d{4}`, "SI"),
+	"SK": postcodeFormat(`\d{3} ?\d{2}`, "SK"),
+	"XI": postcodeFormat(`BT\d{1,2} ?\d[A-Z]{2}`),
+}
+
+func postcodeFormat(pattern string, prefixes ...string) *regexp.Regexp {
+	prefix := ""
+	if len(prefixes) > 0 {
+		prefix = `(?:(?:` + strings.Join(prefixes, "|") + `)-)?`
+	}
+	return regexp.MustCompile(`(?i)(?:^|[\s,])` + prefix + `(` + pattern + `)(?:$|[\s,])`)
+}
+
+// Estonian addresses list the county and the district before the city,
+// street and postcode.
+var estonianAreas = regexp.MustCompile(`(?i)\s(maakond|linnaosa|vald)$`)
+
+// Italian cities are followed by the code of their province, such as
+// ROMA RM or ROMA (RM).
+var italianProvince = regexp.MustCompile(`\s+\(?[A-Z]{2}\)?$`)
+
+// ParsedAddress splits the address VIES returned into street, postcode and
+// city. VIES returns the address as free text laid out by each member
+// state, so the split is a best effort: the postcode is found with the
+// format of the member state and the city is the text around it, fields
+// that can't be told apart are left in Street. Member states which don't
+// disclose addresses give an empty TraderAddress.
+func (result *CheckResult) ParsedAddress() TraderAddress {
+
+	address := TraderAddress{CountryCode: result.CountryCode}
+	country := string(result.CountryCode)
+
+	segments := addressSegments(result.Address)
+	if country == "EE" {
+		segments = estonianSegments(segments)
+	}
+	if len(segments) == 0 {
+		return address
+	}
+
+	i, postcode, city := findPostcode(country, segments)
+	if i < 0 {
+		// the last line is the city when there are several
+		if len(segments) > 1 {
+			address.City = segments[len(segments)-1]
+			segments = segments[:len(segments)-1]
+		}
+		address.Street = strings.Join(segments, ", ")
+		return address
+	}
+
+	address.Postcode = postcode
+	rest := append(segments[:i:i], segments[i+1:]...)
+	if city == "" && i > 0 {
+		// the postcode stands alone, after the city
+		city = segments[i-1]
+		rest = append(segments[:i-1:i-1], segments[i+1:]...)
+	}
+	if country == "IT" {
+		city = italianProvince.ReplaceAllString(city, "")
+	}
+	address.City = city
+	address.Street = strings.Join(rest, ", ")
+	return address
+}
+
+// addressSegments splits an address into its lines, or into its comma
+// separated parts when it is on one line.
+func addressSegments(address string) []string {
+
+	lines := strings.Split(strings.ReplaceAll(address, "\r", ""), "\n")
+	if len(strings.Fields(address)) > 0 && len(nonEmpty(lines)) == 1 {
+		lines = strings.Split(address, ",")
+	}
+	segments := nonEmpty(lines)
+	if len(segments) == 1 && strings.Trim(segments[0], "- ") == "" {
+		// "---" for addresses which are not disclosed
+		return nil
+	}
+	return segments
+}
+
+func nonEmpty(lines []string) []string {
+	var segments []string
+	for _, line := range lines {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			segments = append(segments, line)
+		}
+	}
+	return segments
+}
+
+// estonianSegments reorders "county, city, district, street, postcode"
+// into "street, city, postcode".
+func estonianSegments(segments []string) []string {
+
+	var kept []string
+	for _, segment := range segments {
+		if !estonianAreas.MatchString(segment) {
+			kept = append(kept, segment)
+		}
+	}
+	if len(kept) == 3 && len(kept) < len(segments) {
+		kept[0], kept[1] = kept[1], kept[0]
+	}
+	return kept
+}
+
+// findPostcode returns the index of the last segment holding a postcode,
+// the postcode and the text around it, or -1.
+func findPostcode(country string, segments []string) (int, string, string) {
+
+	format, ok := postcodeFormats[country]
+	if !ok {
+		return -1, "", ""
+	}
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := segments[i]
+		loc := format.FindStringSubmatchIndex(segment)
+		if loc == nil {
+			continue
+		}
+		postcode := strings.ToUpper(segment[loc[2]:loc[3]])
+		before := strings.Trim(segment[:loc[0]], " ,-")
+		after := strings.Trim(segment[loc[1]:], " ,-")
+		if after != "" {
+			return i, postcode, after
+		}
+		return i, postcode, before
+	}
+	return -1, "", ""
+}
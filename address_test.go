@@ -0,0 +1,37 @@
+package vies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsedAddress(t *testing.T) {
+
+	cases := []struct {
+		country string
+		address string
+		parsed  TraderAddress
+	}{
+		{country: "FR", address: "12 RUE DE LA PAIX\n75002 PARIS", parsed: TraderAddress{Street: "12 RUE DE LA PAIX", Postcode: "75002", City: "PARIS"}},
+		{country: "NL", address: "KEIZERSGRACHT 00123\n1015CJ AMSTERDAM\n", parsed: TraderAddress{Street: "KEIZERSGRACHT 00123", Postcode: "1015CJ", City: "AMSTERDAM"}},
+		{country: "PL", address: "ul. Marszałkowska 1, 00-001 Warszawa", parsed: TraderAddress{Street: "ul. Marszałkowska 1", Postcode: "00-001", City: "Warszawa"}},
+		{country: "HU", address: "1051 Budapest, Fő utca 1.", parsed: TraderAddress{Street: "Fő utca 1.", Postcode: "1051", City: "Budapest"}},
+		{country: "AT", address: "Hauptstraße 1/2/3\nAT-1010 Wien", parsed: TraderAddress{Street: "Hauptstraße 1/2/3", Postcode: "1010", City: "Wien"}},
+		{country: "CZ", address: "Václavské náměstí 832/19\nPRAHA 1 - NOVÉ MĚSTO\n110 00  PRAHA 1", parsed: TraderAddress{Street: "Václavské náměstí 832/19, PRAHA 1 - NOVÉ MĚSTO", Postcode: "110 00", City: "PRAHA 1"}},
+		{country: "IT", address: "VIA ROMA 1 \n00184 ROMA RM\n", parsed: TraderAddress{Street: "VIA ROMA 1", Postcode: "00184", City: "ROMA"}},
+		{country: "IE", address: "1 MAIN STREET\nDUBLIN 2\nD02 X285", parsed: TraderAddress{Street: "1 MAIN STREET", Postcode: "D02 X285", City: "DUBLIN 2"}},
+		{country: "EE", address: "Harju maakond, Tallinn, Kesklinna linnaosa, Narva mnt 5, 10117", parsed: TraderAddress{Street: "Narva mnt 5", Postcode: "10117", City: "Tallinn"}},
+		{country: "BE", address: "Rue de la Loi 16\nBruxelles", parsed: TraderAddress{Street: "Rue de la Loi 16", City: "Bruxelles"}},
+		{country: "DE", address: "---", parsed: TraderAddress{}},
+		{country: "ES", address: "", parsed: TraderAddress{}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.country, func(t *testing.T) {
+			result := &CheckResult{CountryCode: CountryCode(tt.country), Address: tt.address}
+			tt.parsed.CountryCode = CountryCode(tt.country)
+			assert.Equal(t, tt.parsed, result.ParsedAddress())
+		})
+	}
+}
@@ -0,0 +1,395 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditRecord is the evidence of a call to Check or CheckNumber.
+type AuditRecord struct {
+	Time time.Time `json:"time"`
+	// Input is the VAT number as given, CountryCode and VatNumber as
+	// checked once cleaned, or as cleaned from Input when the check
+	// failed, empty when the input has no country code.
+	Input       string      `json:"input"`
+	CountryCode CountryCode `json:"countryCode,omitempty"`
+	VatNumber   string      `json:"vatNumber,omitempty"`
+	// Requester is the VAT number the check was made on behalf of.
+	Requester string        `json:"requester,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	// Cached is set when the result came from the cache rather than VIES.
+	Cached bool `json:"cached,omitempty"`
+	// RequestIdentifier is the consultation number of Result.
+	RequestIdentifier string       `json:"requestIdentifier,omitempty"`
+	Result            *CheckResult `json:"result,omitempty"`
+	Error             string       `json:"error,omitempty"`
+}
+
+// AuditSinkInterface retains the evidence of every check, see
+// ClientConfig.Audit.
+type AuditSinkInterface interface {
+	Record(ctx context.Context, record *AuditRecord) error
+}
+
+type auditKey struct{}
+
+// audited runs check and records it to the audit sink.
+func (client *Client) audited(ctx context.Context, input string, opts []CheckOption, check func(ctx context.Context) (*CheckResult, error)) (*CheckResult, error) {
+
+	if client.audit == nil {
+		return check(ctx)
+	}
+
+	record := &AuditRecord{Time: client.clock.Now().UTC(), Input: input}
+	start := client.clock.Now()
+	result, err := check(context.WithValue(ctx, auditKey{}, record))
+	record.Duration = client.clock.Now().Sub(start)
+
+	record.Requester = newCheckOptions(opts).requester
+	if record.Requester == "" {
+		record.Requester = client.settings.Load().requester
+	}
+	if result != nil {
+		record.CountryCode = result.CountryCode
+		record.VatNumber = result.VatNumber
+		record.RequestIdentifier = result.RequestIdentifier
+		record.Result = result
+	} else if key := vatKey(input); client.isValidVat(key) == nil {
+		// failed checks are filed under their input, for Erase to find
+		// them along with the others
+		record.CountryCode = CountryCode(key[:2])
+		record.VatNumber = key[2:]
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if auditErr := client.audit.Record(ctx, record); auditErr != nil && client.logger != nil {
+		client.logger.LogAttrs(ctx, slog.LevelWarn, "vies audit failed", slog.String("error", auditErr.Error()))
+	}
+	return result, err
+}
+
+// auditCacheHit marks the audit record of ctx as answered by the cache.
+func auditCacheHit(ctx context.Context) {
+	if record, ok := ctx.Value(auditKey{}).(*AuditRecord); ok {
+		record.Cached = true
+	}
+}
+
+// FileAuditSink appends audit records to a file as JSON lines.
+type FileAuditSink struct {
+	path   string
+	file   *os.File
+	cipher *Cipher
+	mu     sync.Mutex
+}
+
+// NewFileAuditSink opens path for appending, creating it and its directory
+// when missing.
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	return NewEncryptedFileAuditSink(path, nil)
+}
+
+// NewEncryptedFileAuditSink returns a FileAuditSink which encrypts every
+// record with cipher, each line holding a record encrypted and base64
+// encoded. ReadAuditRecords reads them back.
+func NewEncryptedFileAuditSink(path string, cipher *Cipher) (*FileAuditSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileAuditSink{path: path, file: file, cipher: cipher}, nil
+}
+
+// ReadAuditRecords reads the records of a FileAuditSink file, cipher being
+// nil unless it is encrypted.
+func ReadAuditRecords(path string, cipher *Cipher) ([]*AuditRecord, error) {
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileAuditSink{cipher: cipher}
+	var records []*AuditRecord
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		record, err := s.decode(line)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (s *FileAuditSink) Record(_ context.Context, record *AuditRecord) error {
+
+	line, err := s.encode(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+func (s *FileAuditSink) Close() error {
+	return s.file.Close()
+}
+
+// Prune removes the records made before before.
+func (s *FileAuditSink) Prune(_ context.Context, before time.Time) (int, error) {
+	return s.rewrite(func(record *AuditRecord) (bool, bool) {
+		return record.Time.Before(before), false
+	})
+}
+
+// Erase anonymizes the records of vat: only their time, country code,
+// requester, duration and consultation number are kept.
+func (s *FileAuditSink) Erase(_ context.Context, vat string) (int, error) {
+	key := vatKey(vat)
+	return s.rewrite(func(record *AuditRecord) (bool, bool) {
+		if record.VatNumber == "" || vatKey(string(record.CountryCode)+record.VatNumber) != key {
+			return false, false
+		}
+		record.anonymize()
+		return false, true
+	})
+}
+
+// rewrite rewrites the file without the records edit removes and with
+// those it changes, returning how many. Lines which are not records are
+// kept as they are.
+func (s *FileAuditSink) rewrite(edit func(record *AuditRecord) (remove, changed bool)) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	var out []byte
+	for _, line := range bytes.SplitAfter(content, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			out = append(out, line...)
+			continue
+		}
+		record, err := s.decode(bytes.TrimSpace(line))
+		if err != nil {
+			out = append(out, line...)
+			continue
+		}
+		remove, changed := edit(record)
+		switch {
+		case remove:
+			n++
+		case changed:
+			n++
+			edited, err := s.encode(record)
+			if err != nil {
+				return 0, err
+			}
+			out = append(append(out, edited...), '\n')
+		default:
+			out = append(out, line...)
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	if err := writeFileAtomic(filepath.Dir(s.path), s.path, out); err != nil {
+		return 0, err
+	}
+	file, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return n, err
+	}
+	_ = s.file.Close()
+	s.file = file
+	return n, nil
+}
+
+// encode returns record as a line, without its line feed.
+func (s *FileAuditSink) encode(record *AuditRecord) ([]byte, error) {
+	line, err := json.Marshal(record)
+	if err != nil || s.cipher == nil {
+		return line, err
+	}
+	encrypted, err := s.cipher.Encrypt(line)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.AppendEncode(nil, encrypted), nil
+}
+
+func (s *FileAuditSink) decode(line []byte) (*AuditRecord, error) {
+	if s.cipher != nil {
+		encrypted, err := base64.StdEncoding.AppendDecode(nil, line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrDecryption, err)
+		}
+		if line, err = s.cipher.Decrypt(encrypted); err != nil {
+			return nil, err
+		}
+	}
+	var record AuditRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// anonymize clears the fields of the record which identify the trader.
+func (record *AuditRecord) anonymize() {
+	record.Input = ""
+	record.VatNumber = ""
+	record.Result = nil
+	record.Error = ""
+}
+
+const defaultAuditTable = "vies_audit"
+
+type SQLAuditSinkConfig struct {
+	// Table holding the audit records, defaults to vies_audit.
+	Table string
+	// Placeholder returns the placeholder of the n-th query argument,
+	// starting at 1. Defaults to "?", use DollarPlaceholder for PostgreSQL.
+	Placeholder func(n int) string
+	// Cipher, when set, encrypts the input, result and error, and
+	// replaces the VAT number with its keyed hash, see Cipher.
+	Cipher *Cipher
+}
+
+// SQLAuditSink keeps audit records in a database table, see CreateTable
+// for its schema. The result is stored as JSON.
+type SQLAuditSink struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+	cipher      *Cipher
+}
+
+func NewSQLAuditSink(db *sql.DB, config *SQLAuditSinkConfig) *SQLAuditSink {
+
+	table := defaultAuditTable
+	placeholder := func(int) string { return "?" }
+	var cipher *Cipher
+
+	if config != nil {
+		if config.Table != "" {
+			table = config.Table
+		}
+		if config.Placeholder != nil {
+			placeholder = config.Placeholder
+		}
+		cipher = config.Cipher
+	}
+
+	return &SQLAuditSink{
+		db:          db,
+		table:       table,
+		placeholder: placeholder,
+		cipher:      cipher,
+	}
+}
+
+// CreateTable creates the audit table unless it exists.
+func (s *SQLAuditSink) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+s.table+` (
+	checked_at TIMESTAMP NOT NULL,
+	input TEXT NOT NULL,
+	country_code CHAR(2) NOT NULL,
+	vat_number VARCHAR(32) NOT NULL,
+	requester VARCHAR(32) NOT NULL,
+	duration_ms BIGINT NOT NULL,
+	cached BOOLEAN NOT NULL,
+	valid BOOLEAN NOT NULL,
+	request_identifier VARCHAR(64) NOT NULL,
+	result TEXT NOT NULL,
+	error TEXT NOT NULL
+)`)
+	return err
+}
+
+// Prune removes the records made before before.
+func (s *SQLAuditSink) Prune(ctx context.Context, before time.Time) (int, error) {
+	return execCount(s.db.ExecContext(ctx, "DELETE FROM "+s.table+" WHERE checked_at < "+s.placeholder(1), before.UTC()))
+}
+
+// Erase anonymizes the records of vat, see FileAuditSink.Erase.
+func (s *SQLAuditSink) Erase(ctx context.Context, vat string) (int, error) {
+	key := vatKey(vat)
+	if len(key) < 3 {
+		return 0, nil
+	}
+	set := "UPDATE " + s.table + " SET input = '', vat_number = '', result = '', error = '' WHERE country_code = " + s.placeholder(1)
+	if s.cipher != nil {
+		return execCount(s.db.ExecContext(ctx, set+" AND vat_number = "+s.placeholder(2), key[:2], s.cipher.hash(key)))
+	}
+	return execCount(s.db.ExecContext(ctx, set+" AND UPPER(vat_number) = "+s.placeholder(2), key[:2], key[2:]))
+}
+
+func (s *SQLAuditSink) Record(ctx context.Context, record *AuditRecord) error {
+
+	result := ""
+	valid := false
+	if record.Result != nil {
+		content, err := json.Marshal(record.Result)
+		if err != nil {
+			return err
+		}
+		result = string(content)
+		valid = record.Result.Valid
+	}
+
+	input, vatNumber, errorText := record.Input, record.VatNumber, record.Error
+	if s.cipher != nil {
+		var err error
+		if vatNumber != "" {
+			vatNumber = s.cipher.hash(vatKey(string(record.CountryCode) + vatNumber))
+		}
+		if input, err = s.cipher.encryptString(input); err != nil {
+			return err
+		}
+		if result, err = s.cipher.encryptString(result); err != nil {
+			return err
+		}
+		if errorText, err = s.cipher.encryptString(errorText); err != nil {
+			return err
+		}
+	}
+
+	placeholders := make([]string, 11)
+	for i := range placeholders {
+		placeholders[i] = s.placeholder(i + 1)
+	}
+
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO "+s.table+" (checked_at, input, country_code, vat_number, requester, duration_ms, cached, valid, request_identifier, result, error) VALUES ("+strings.Join(placeholders, ", ")+")",
+		record.Time, input, string(record.CountryCode), vatNumber, record.Requester, record.Duration.Milliseconds(),
+		record.Cached, valid, record.RequestIdentifier, result, errorText,
+	)
+	return err
+}
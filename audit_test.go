@@ -0,0 +1,152 @@
+package vies
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditRecords []*AuditRecord
+
+func (r *auditRecords) Record(_ context.Context, record *AuditRecord) error {
+	*r = append(*r, record)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true,"requestIdentifier":"WAPIAAAAYx"}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	var records auditRecords
+	v, err := New(WithHttpClient(client), WithRequester("DE123456789"), WithCache(NewMemoryCache(time.Hour)), WithAuditSink(&records))
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), " ee123 ")
+	assert.NoError(t, err)
+	_, err = v.CheckNumber(context.Background(), "EE", "123")
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "1")
+	assert.ErrorIs(t, err, ErrInvalidVat)
+
+	assert.Len(t, records, 3)
+	assert.Equal(t, " ee123 ", records[0].Input)
+	assert.Equal(t, CountryCode("EE"), records[0].CountryCode)
+	assert.Equal(t, "123", records[0].VatNumber)
+	assert.Equal(t, "DE123456789", records[0].Requester)
+	assert.Equal(t, "WAPIAAAAYx", records[0].RequestIdentifier)
+	assert.False(t, records[0].Cached)
+	assert.True(t, records[0].Result.Valid)
+	assert.Empty(t, records[0].Error)
+
+	assert.Equal(t, "EE123", records[1].Input)
+	assert.True(t, records[1].Cached)
+
+	assert.Nil(t, records[2].Result)
+	assert.Equal(t, "invalid VAT provided 1", records[2].Error)
+}
+
+func TestFileAuditSink(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "audit", "checks.jsonl")
+	sink, err := NewFileAuditSink(path)
+	assert.NoError(t, err)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.NoError(t, sink.Record(context.Background(), &AuditRecord{Time: at, Input: "DE1", Error: "down"}))
+	assert.NoError(t, sink.Record(context.Background(), &AuditRecord{Time: at, Input: "DE2", Result: &CheckResult{Valid: true}}))
+	assert.NoError(t, sink.Close())
+
+	file, err := os.Open(path)
+	assert.NoError(t, err)
+	defer file.Close()
+
+	var inputs []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AuditRecord
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, at, record.Time)
+		inputs = append(inputs, record.Input)
+	}
+	assert.Equal(t, []string{"DE1", "DE2"}, inputs)
+}
+
+func TestAuditEraseFailedCheck(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"errorWrappers":[{"error":"MS_UNAVAILABLE","message":"down"}]}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileAuditSink(path)
+	assert.NoError(t, err)
+	defer sink.Close()
+
+	var records auditRecords
+	for _, audit := range []AuditSinkInterface{sink, &records} {
+		v, err := New(WithHttpClient(client), WithEndpoint("https://example.com/"), WithAuditSink(audit))
+		assert.NoError(t, err)
+		_, err = v.Check(context.Background(), "de 123.456.789")
+		assert.Error(t, err)
+	}
+
+	// failed checks are filed under their input, as the SQL sink stores them
+	assert.Equal(t, CountryCode("DE"), records[0].CountryCode)
+	assert.Equal(t, "123456789", records[0].VatNumber)
+
+	n, err := sink.Erase(context.Background(), "DE123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "123")
+	assert.Contains(t, string(content), `"countryCode":"DE"`)
+}
+
+func TestSQLAuditSink(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	sink := NewSQLAuditSink(db, &SQLAuditSinkConfig{Table: "checks", Placeholder: DollarPlaceholder})
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS checks (")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO checks (checked_at, input, country_code, vat_number, requester, duration_ms, cached, valid, request_identifier, result, error) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)")).
+		WithArgs(at, "de1", "DE", "1", "", int64(250), false, true, "WAPI", sqlmock.AnyArg(), "").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, sink.CreateTable(context.Background()))
+	assert.NoError(t, sink.Record(context.Background(), &AuditRecord{
+		Time:              at,
+		Input:             "de1",
+		CountryCode:       "DE",
+		VatNumber:         "1",
+		Duration:          250 * time.Millisecond,
+		RequestIdentifier: "WAPI",
+		Result:            &CheckResult{CountryCode: "DE", VatNumber: "1", Valid: true, RequestIdentifier: "WAPI"},
+	}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,186 @@
+// Package brreg checks Norwegian organisation numbers against the open
+// API of the Brønnøysund Register Centre and returns vies.CheckResult,
+// valid when the organisation is registered in the VAT register (MVA).
+package brreg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alytsin/go-vies"
+)
+
+const (
+	apiEndpointUrl = "https://data.brreg.no/enhetsregisteret/api/"
+	apiEntityPath  = "enheter"
+
+	countryCode = "NO"
+	vatSuffix   = "MVA"
+
+	// Registry marks the results of this package in vies.CheckResult.
+	Registry = "BRREG"
+)
+
+type Config struct {
+	HttpClient  vies.HttpClientInterface
+	EndpointUrl string
+}
+
+type Client struct {
+	endpoint   *url.URL
+	httpClient vies.HttpClientInterface
+}
+
+type entity struct {
+	OrganisationNumber string  `json:"organisasjonsnummer"`
+	Name               string  `json:"navn"`
+	VatRegistered      bool    `json:"registrertIMvaregisteret"`
+	BusinessAddress    address `json:"forretningsadresse"`
+	Deleted            string  `json:"slettedato"`
+}
+
+type address struct {
+	Lines      []string `json:"adresse"`
+	PostalCode string   `json:"postnummer"`
+	City       string   `json:"poststed"`
+}
+
+type errorResponse struct {
+	Status int    `json:"status"`
+	Error  string `json:"error"`
+	Path   string `json:"path"`
+}
+
+func NewClient(config *Config) (*Client, error) {
+
+	var client vies.HttpClientInterface
+
+	endpoint := apiEndpointUrl
+	client = vies.NewHttpClient()
+
+	if config != nil {
+		if config.EndpointUrl != "" {
+			endpoint = config.EndpointUrl
+		}
+		if config.HttpClient != nil {
+			client = config.HttpClient
+		}
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		endpoint:   u,
+		httpClient: client,
+	}, nil
+}
+
+// Check looks up an organisation number written as 923609016 or
+// NO 923 609 016 MVA. Unknown and deleted organisations are returned as
+// invalid results.
+func (client *Client) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+
+	number, err := normalize(vat)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.endpoint.JoinPath(apiEntityPath, number).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	rsp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &vies.CheckResult{
+		CountryCode: countryCode,
+		VatNumber:   number,
+		Vat:         countryCode + number,
+		Registry:    Registry,
+	}
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return result, nil
+	default:
+		var e errorResponse
+		if err := json.Unmarshal(body, &e); err != nil || e.Error == "" {
+			return nil, fmt.Errorf("unexpected response status %d", rsp.StatusCode)
+		}
+		return nil, &vies.ApiError{Err: fmt.Sprint(e.Status), Message: e.Error}
+	}
+
+	var ent entity
+	if err := json.Unmarshal(body, &ent); err != nil {
+		return nil, err
+	}
+
+	result.Valid = ent.VatRegistered && ent.Deleted == ""
+	result.Name = ent.Name
+	result.Address = ent.BusinessAddress.String()
+	return result, nil
+}
+
+// normalize returns the nine digits of an organisation number and verifies
+// its modulo 11 check digit.
+func normalize(vat string) (string, error) {
+
+	s := strings.ToUpper(strings.NewReplacer(" ", "", ".", "", "-", "").Replace(vat))
+	s = strings.TrimPrefix(s, countryCode)
+	s = strings.TrimSuffix(s, vatSuffix)
+
+	if len(s) != 9 || !validCheckDigit(s) {
+		return "", fmt.Errorf("%w %s", vies.ErrInvalidVat, vies.PrivateVat(vat))
+	}
+	return s, nil
+}
+
+func validCheckDigit(number string) bool {
+
+	weights := []int{3, 2, 7, 6, 5, 4, 3, 2}
+	sum := 0
+	for i, c := range []byte(number) {
+		if c < '0' || c > '9' {
+			return false
+		}
+		if i < len(weights) {
+			sum += int(c-'0') * weights[i]
+		}
+	}
+
+	check := 11 - sum%11
+	switch check {
+	case 11:
+		check = 0
+	case 10:
+		return false
+	}
+	return number[8] == byte('0'+check)
+}
+
+func (a address) String() string {
+	lines := append([]string{}, a.Lines...)
+	if city := strings.TrimSpace(a.PostalCode + " " + a.City); city != "" {
+		lines = append(lines, city)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,97 @@
+package brreg
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		vat      string
+		code     int
+		response string
+		result   *vies.CheckResult
+		err      string
+	}{
+		{
+			name: "registered",
+			vat:  "NO 923 609 016 MVA",
+			code: http.StatusOK,
+			response: `{
+				"organisasjonsnummer": "923609016",
+				"navn": "EQUINOR ASA",
+				"registrertIMvaregisteret": true,
+				"forretningsadresse": {"land": "Norge", "landkode": "NO", "postnummer": "4035", "poststed": "STAVANGER", "adresse": ["Forusbeen 50"]}
+			}`,
+			result: &vies.CheckResult{
+				CountryCode: "NO",
+				VatNumber:   "923609016",
+				Vat:         "NO923609016",
+				Valid:       true,
+				Name:        "EQUINOR ASA",
+				Address:     "Forusbeen 50\n4035 STAVANGER",
+				Registry:    "BRREG",
+			},
+		},
+		{
+			name:     "not registered for VAT",
+			vat:      "923609016",
+			code:     http.StatusOK,
+			response: `{"organisasjonsnummer": "923609016", "navn": "EQUINOR ASA", "registrertIMvaregisteret": false}`,
+			result:   &vies.CheckResult{CountryCode: "NO", VatNumber: "923609016", Vat: "NO923609016", Name: "EQUINOR ASA", Registry: "BRREG"},
+		},
+		{
+			name:   "deleted",
+			vat:    "923609016",
+			code:   http.StatusGone,
+			result: &vies.CheckResult{CountryCode: "NO", VatNumber: "923609016", Vat: "NO923609016", Registry: "BRREG"},
+		},
+		{
+			name:     "api error",
+			vat:      "923609016",
+			code:     http.StatusInternalServerError,
+			response: `{"timestamp":"2024-01-01T00:00:00","status":500,"error":"Internal Server Error","path":"/enhetsregisteret/api/enheter/923609016"}`,
+			err:      "500: Internal Server Error",
+		},
+		{
+			name: "check digit",
+			vat:  "923609017",
+			err:  "invalid VAT provided 923609017",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/enheter/923609016", r.URL.Path)
+				w.WriteHeader(tt.code)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client, err := NewClient(&Config{EndpointUrl: server.URL})
+			assert.NoError(t, err)
+
+			result, err := client.Check(context.Background(), tt.vat)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+			assert.Equal(t, tt.result, result)
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient(nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, client.httpClient.(*http.Client).Timeout)
+}
@@ -0,0 +1,115 @@
+package vies
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type BulkConfig struct {
+	// Parallel is the number of checks running concurrently, defaults to 1.
+	Parallel int
+	// RateLimit is the maximum number of checks started per second, zero
+	// means no limit.
+	RateLimit float64
+	// RateLimiter, when set, is waited on before each check, so that a
+	// limit can be shared between several calls.
+	RateLimiter RateLimiterInterface
+}
+
+type BulkResult struct {
+	Vat    string
+	Result *CheckResult
+	Err    error
+}
+
+// BulkResults are the results of CheckAll, in the order of the input.
+type BulkResults []BulkResult
+
+// ResultSet returns the results of the successful checks.
+func (results BulkResults) ResultSet() ResultSet {
+	var set ResultSet
+	for _, r := range results {
+		if r.Err == nil && r.Result != nil {
+			set = append(set, r.Result)
+		}
+	}
+	return set
+}
+
+// Failed returns the checks which failed.
+func (results BulkResults) Failed() BulkResults {
+	var failed BulkResults
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// CheckAll checks every VAT number of the list concurrently and returns
+// the results in the order of the input.
+func (client *Client) CheckAll(ctx context.Context, vats []string, config *BulkConfig) BulkResults {
+
+	parallel := 1
+	var interval time.Duration
+	var limiter RateLimiterInterface
+
+	if config != nil {
+		if config.Parallel > 0 {
+			parallel = config.Parallel
+		}
+		if config.RateLimit > 0 {
+			interval = time.Duration(float64(time.Second) / config.RateLimit)
+		}
+		limiter = config.RateLimiter
+	}
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	results := make(BulkResults, len(vats))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range parallel {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := client.Check(ctx, vats[i])
+				results[i] = BulkResult{Vat: vats[i], Result: result, Err: err}
+			}
+		}()
+	}
+
+	for i := range vats {
+		if i > 0 && tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+			}
+		}
+		err := ctx.Err()
+		if err == nil && limiter != nil {
+			err = limiter.Wait(ctx)
+		}
+		if err != nil {
+			// the checks left are not sent
+			for j := i; j < len(vats); j++ {
+				results[j] = BulkResult{Vat: vats[j], Err: err}
+			}
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,116 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAll(t *testing.T) {
+
+	newClient := func(t *testing.T, calls *atomic.Int32) *Client {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			calls.Add(1)
+			var body checkRequest
+			assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+			out, _ := json.Marshal(CheckResult{CountryCode: body.CountryCode, VatNumber: body.VatNumber, Valid: body.VatNumber != "000"})
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(out)),
+				Header:     make(http.Header),
+			}
+		})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
+		assert.NoError(t, err)
+		return v
+	}
+
+	t.Run("results keep input order", func(t *testing.T) {
+		var calls atomic.Int32
+		v := newClient(t, &calls)
+
+		results := v.CheckAll(context.Background(), []string{"EE123", "E", "EE000", "EE456"}, &BulkConfig{Parallel: 3})
+		assert.Len(t, results, 4)
+		assert.Equal(t, int32(3), calls.Load())
+
+		assert.Equal(t, "EE123", results[0].Vat)
+		assert.True(t, results[0].Result.Valid)
+		assert.Equal(t, "E", results[1].Vat)
+		assert.EqualError(t, results[1].Err, "invalid VAT provided E")
+		assert.False(t, results[2].Result.Valid)
+		assert.Equal(t, "EE456", results[3].Result.Vat)
+
+		assert.Len(t, results.ResultSet(), 3)
+		assert.Len(t, results.ResultSet().Invalid(), 1)
+		assert.Len(t, results.Failed(), 1)
+		assert.Equal(t, "E", results.Failed()[0].Vat)
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		var calls atomic.Int32
+		v := newClient(t, &calls)
+
+		start := time.Now()
+		results := v.CheckAll(context.Background(), []string{"EE1", "EE2", "EE3"}, &BulkConfig{Parallel: 3, RateLimit: 50})
+		assert.Len(t, results, 3)
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("shared rate limiter", func(t *testing.T) {
+		var calls atomic.Int32
+		v := newClient(t, &calls)
+		limiter := NewRateLimiter(50, 1)
+
+		start := time.Now()
+		v.CheckAll(context.Background(), []string{"EE1", "EE2"}, &BulkConfig{Parallel: 2, RateLimiter: limiter})
+		v.CheckAll(context.Background(), []string{"EE3"}, &BulkConfig{RateLimiter: limiter})
+		assert.Equal(t, int32(3), calls.Load())
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		var calls atomic.Int32
+		v := newClient(t, &calls)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results := v.CheckAll(ctx, []string{"EE1", "EE2"}, nil)
+		assert.Equal(t, int32(0), calls.Load())
+		for _, r := range results {
+			assert.ErrorIs(t, r.Err, context.Canceled)
+		}
+	})
+
+	t.Run("failing rate limiter", func(t *testing.T) {
+		var calls atomic.Int32
+		v := newClient(t, &calls)
+
+		waits := 0
+		limiter := rateLimiterFunc(func(ctx context.Context) error {
+			if waits++; waits > 1 {
+				return context.DeadlineExceeded
+			}
+			return nil
+		})
+		results := v.CheckAll(context.Background(), []string{"EE1", "EE2", "EE3"}, &BulkConfig{RateLimiter: limiter})
+		assert.Equal(t, int32(1), calls.Load())
+		assert.NoError(t, results[0].Err)
+		assert.ErrorIs(t, results[1].Err, context.DeadlineExceeded)
+		assert.ErrorIs(t, results[2].Err, context.DeadlineExceeded)
+		assert.Equal(t, "EE3", results[2].Vat)
+	})
+}
+
+type rateLimiterFunc func(ctx context.Context) error
+
+func (f rateLimiterFunc) Wait(ctx context.Context) error {
+	return f(ctx)
+}
@@ -0,0 +1,334 @@
+package vies
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const fileCacheExtension = ".json"
+
+// CacheInterface stores check results keyed by the normalized VAT number,
+// followed by @ and the requester for checks made on behalf of one, see
+// ClientConfig.Requester. Only successful checks are cached.
+type CacheInterface interface {
+	Get(key string) (*CheckResult, bool)
+	Set(key string, result *CheckResult)
+}
+
+type cacheEntry struct {
+	Key     string       `json:"key,omitempty"`
+	Stored  time.Time    `json:"stored"`
+	Expires time.Time    `json:"expires"`
+	Result  *CheckResult `json:"result"`
+}
+
+// resultCacheKey is the cache key of the result of vat checked on behalf
+// of requester, if any.
+func resultCacheKey(vat, requester string) string {
+	if requester == "" {
+		return vat
+	}
+	return vat + "@" + requester
+}
+
+// MemoryCache keeps results in process memory.
+type MemoryCache struct {
+	ttl     time.Duration
+	clock   ClockInterface
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		ttl:     ttl,
+		clock:   SystemClock{},
+		entries: make(map[string]cacheEntry),
+	}
+}
+
+func (c *MemoryCache) Get(key string) (*CheckResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if c.clock.Now().After(entry.Expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	result := *entry.Result
+	return &result, true
+}
+
+func (c *MemoryCache) Set(key string, result *CheckResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r := *result
+	now := c.clock.Now()
+	c.entries[key] = cacheEntry{Stored: now, Expires: now.Add(c.ttl), Result: &r}
+}
+
+// SetTTL changes the TTL of the results stored from now on.
+func (c *MemoryCache) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// SetClock replaces the system clock telling when results expire.
+func (c *MemoryCache) SetClock(clock ClockInterface) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clockOrSystem(clock)
+}
+
+// Prune removes the results stored before before.
+func (c *MemoryCache) Prune(_ context.Context, before time.Time) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for key, entry := range c.entries {
+		if entry.Stored.Before(before) {
+			delete(c.entries, key)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Erase removes the results of vat, whichever requester they were checked
+// for.
+func (c *MemoryCache) Erase(_ context.Context, vat string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := vatKey(vat)
+	n := 0
+	for k := range c.entries {
+		if k == key || strings.HasPrefix(k, key+"@") {
+			delete(c.entries, k)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// FileCache keeps results as JSON files in a directory so they survive
+// process restarts. File names are hashes of the keys.
+type FileCache struct {
+	dir    string
+	ttl    atomic.Int64
+	clock  atomic.Pointer[ClockInterface]
+	cipher *Cipher
+}
+
+func NewFileCache(dir string, ttl time.Duration) (*FileCache, error) {
+	return NewEncryptedFileCache(dir, ttl, nil)
+}
+
+// NewEncryptedFileCache returns a FileCache which encrypts its files with
+// cipher and names them after keyed hashes of the keys, see Cipher.
+func NewEncryptedFileCache(dir string, ttl time.Duration, cipher *Cipher) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	c := &FileCache{dir: dir, cipher: cipher}
+	c.ttl.Store(int64(ttl))
+	c.SetClock(nil)
+	return c, nil
+}
+
+// SetTTL changes the TTL of the results stored from now on.
+func (c *FileCache) SetTTL(ttl time.Duration) {
+	c.ttl.Store(int64(ttl))
+}
+
+// SetClock replaces the system clock telling when results expire.
+func (c *FileCache) SetClock(clock ClockInterface) {
+	clock = clockOrSystem(clock)
+	c.clock.Store(&clock)
+}
+
+func (c *FileCache) now() time.Time {
+	return (*c.clock.Load()).Now()
+}
+
+func (c *FileCache) Get(key string) (*CheckResult, bool) {
+
+	content, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	entry, err := c.decode(content)
+	if err != nil || entry.Result == nil {
+		return nil, false
+	}
+	if c.now().After(entry.Expires) {
+		_ = os.Remove(c.path(key))
+		return nil, false
+	}
+	return entry.Result, true
+}
+
+func (c *FileCache) Set(key string, result *CheckResult) {
+
+	now := c.now()
+	content, err := json.Marshal(cacheEntry{Key: key, Stored: now, Expires: now.Add(time.Duration(c.ttl.Load())), Result: result})
+	if err != nil {
+		return
+	}
+	if c.cipher != nil {
+		if content, err = c.cipher.Encrypt(content); err != nil {
+			return
+		}
+	}
+
+	_ = writeFileAtomic(c.dir, c.path(key), content)
+}
+
+// writeFileAtomic writes to a temporary file first so concurrent readers
+// never see a partially written file.
+func writeFileAtomic(dir, path string, content []byte) error {
+
+	tmp, err := os.CreateTemp(dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// Purge removes all entries from the cache directory.
+func (c *FileCache) Purge() error {
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileCacheExtension) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.dir, e.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Prune removes the results stored before before, and those of previous
+// versions which didn't record when.
+func (c *FileCache) Prune(_ context.Context, before time.Time) (int, error) {
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	var errs []error
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileCacheExtension) {
+			continue
+		}
+		path := filepath.Join(c.dir, e.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if entry, err := c.decode(content); err == nil && !entry.Stored.Before(before) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+// Erase removes the results of vat, whichever requester they were checked
+// for. Results stored by previous versions, which didn't record their key,
+// are only found when checked without requester.
+func (c *FileCache) Erase(_ context.Context, vat string) (int, error) {
+
+	key := vatKey(vat)
+	n := 0
+	var errs []error
+	if err := os.Remove(c.path(key)); err == nil {
+		n++
+	} else if !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, err)
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return n, errors.Join(append(errs, err)...)
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), fileCacheExtension) {
+			continue
+		}
+		path := filepath.Join(c.dir, e.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if entry, err := c.decode(content); err != nil || !strings.HasPrefix(entry.Key, key+"@") {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+func (c *FileCache) decode(content []byte) (cacheEntry, error) {
+	var entry cacheEntry
+	if c.cipher != nil {
+		var err error
+		if content, err = c.cipher.Decrypt(content); err != nil {
+			return entry, err
+		}
+	}
+	err := json.Unmarshal(content, &entry)
+	return entry, err
+}
+
+func (c *FileCache) path(key string) string {
+	if c.cipher != nil {
+		return filepath.Join(c.dir, c.cipher.hash(key)+fileCacheExtension)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+fileCacheExtension)
+}
@@ -0,0 +1,87 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache(t *testing.T) {
+	c := NewMemoryCache(time.Minute)
+
+	_, ok := c.Get("EE123")
+	assert.False(t, ok)
+
+	c.Set("EE123", &CheckResult{Vat: "EE123", Valid: true})
+	result, ok := c.Get("EE123")
+	assert.True(t, ok)
+	assert.Equal(t, &CheckResult{Vat: "EE123", Valid: true}, result)
+
+	expired := NewMemoryCache(-time.Second)
+	expired.Set("EE123", &CheckResult{Vat: "EE123"})
+	_, ok = expired.Get("EE123")
+	assert.False(t, ok)
+}
+
+func TestFileCache(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := NewFileCache(dir, time.Minute)
+	assert.NoError(t, err)
+
+	_, ok := c.Get("EE123")
+	assert.False(t, ok)
+
+	c.Set("EE123", &CheckResult{Vat: "EE123", Valid: true, Name: "Acme"})
+	result, ok := c.Get("EE123")
+	assert.True(t, ok)
+	assert.Equal(t, &CheckResult{Vat: "EE123", Valid: true, Name: "Acme"}, result)
+
+	reopened, err := NewFileCache(dir, time.Minute)
+	assert.NoError(t, err)
+	_, ok = reopened.Get("EE123")
+	assert.True(t, ok)
+
+	assert.NoError(t, c.Purge())
+	_, ok = c.Get("EE123")
+	assert.False(t, ok)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	expired, err := NewFileCache(dir, -time.Second)
+	assert.NoError(t, err)
+	expired.Set("EE123", &CheckResult{Vat: "EE123"})
+	_, ok = expired.Get("EE123")
+	assert.False(t, ok)
+}
+
+func TestCheckCache(t *testing.T) {
+	calls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := NewClient(&ClientConfig{HttpClient: client, Cache: NewMemoryCache(time.Minute)})
+	assert.NoError(t, err)
+
+	first, err := v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	second, err := v.Check(context.Background(), "ee123")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, first, second)
+}
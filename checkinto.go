@@ -0,0 +1,28 @@
+package vies
+
+import (
+	"context"
+)
+
+// CheckInto runs Check and decodes the response of VIES into a T, to
+// capture fields CheckResult doesn't have:
+//
+//	type result struct {
+//		vies.CheckResult
+//		TraderCompanyType string `json:"traderCompanyType"`
+//	}
+//	r, err := vies.CheckInto[result](ctx, client, "DE123456789")
+//
+// The input is cleaned and the check audited as by Check, but results are
+// neither read from nor stored in the cache.
+func CheckInto[T any](ctx context.Context, client *Client, vat string, opts ...CheckOption) (*T, error) {
+
+	var out T
+	_, err := client.audited(ctx, vat, opts, func(ctx context.Context) (*CheckResult, error) {
+		return client.checkVat(ctx, vat, opts, &out)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,80 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckInto(t *testing.T) {
+
+	calls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"DE","vatNumber":"123","valid":true,"traderCompanyType":"GmbH"}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	cache := NewMemoryCache(time.Minute)
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Cache: cache})
+	assert.NoError(t, err)
+
+	type result struct {
+		CheckResult
+		TraderCompanyType string `json:"traderCompanyType"`
+	}
+
+	for range 2 {
+		r, err := CheckInto[result](context.Background(), v, "de123")
+		assert.NoError(t, err)
+		assert.True(t, r.Valid)
+		assert.Equal(t, "GmbH", r.TraderCompanyType)
+	}
+	assert.Equal(t, 2, calls)
+	_, ok := cache.Get("DE123")
+	assert.False(t, ok)
+
+	_, err = CheckInto[result](context.Background(), v, "D")
+	assert.EqualError(t, err, "invalid VAT provided D")
+
+	_, err = CheckInto[struct{ Valid string }](context.Background(), v, "DE123")
+	assert.ErrorContains(t, err, "cannot unmarshal bool")
+}
+
+func TestCheckIntoAudited(t *testing.T) {
+
+	var bodies []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"DE","vatNumber":"123456789","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	var records auditRecords
+	v, err := New(WithHttpClient(client), WithEndpoint("https://example.com/"), WithAuditSink(&records))
+	assert.NoError(t, err)
+
+	r, err := CheckInto[CheckResult](context.Background(), v, " de123456789\u200b")
+	assert.NoError(t, err)
+	assert.True(t, r.Valid)
+	assert.Len(t, bodies, 1)
+	assert.Contains(t, bodies[0], `"vatNumber":"123456789"`)
+
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, " de123456789\u200b", records[0].Input)
+		assert.Equal(t, CountryCode("DE"), records[0].CountryCode)
+		assert.True(t, records[0].Result.Valid)
+	}
+}
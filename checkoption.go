@@ -0,0 +1,66 @@
+package vies
+
+import (
+	"strings"
+	"time"
+)
+
+// CheckOption changes a single Check or Valid call, without building a
+// second client.
+type CheckOption func(options *checkOptions)
+
+type checkOptions struct {
+	skipCache    bool
+	forceRefresh bool
+	requester    string
+	timeout      time.Duration
+	name         string
+	address      string
+}
+
+func newCheckOptions(opts []CheckOption) checkOptions {
+	var options checkOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// SkipCache neither reads nor stores the result in the cache.
+func SkipCache() CheckOption {
+	return func(options *checkOptions) {
+		options.skipCache = true
+	}
+}
+
+// ForceRefresh ignores a cached result and replaces it by the fresh one.
+func ForceRefresh() CheckOption {
+	return func(options *checkOptions) {
+		options.forceRefresh = true
+	}
+}
+
+// WithRequesterOverride makes the check on behalf of another requester than
+// ClientConfig.Requester.
+func WithRequesterOverride(requester string) CheckOption {
+	return func(options *checkOptions) {
+		options.requester = strings.ToUpper(requester)
+	}
+}
+
+// CheckTimeout limits the call to d, retries and the wait of the rate
+// limiter included.
+func CheckTimeout(d time.Duration) CheckOption {
+	return func(options *checkOptions) {
+		options.timeout = d
+	}
+}
+
+// ExpectTrader gives the name and address the trader is known by, compared
+// by ValidDetailed with those VIES returns. Either may be empty to compare
+// only the other.
+func ExpectTrader(name, address string) CheckOption {
+	return func(options *checkOptions) {
+		options.name, options.address = name, address
+	}
+}
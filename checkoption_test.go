@@ -0,0 +1,85 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOptions(t *testing.T) {
+
+	var requests []checkRequest
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		var body checkRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		requests = append(requests, body)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	cache := NewMemoryCache(time.Minute)
+	v, err := NewClient(&ClientConfig{HttpClient: httpClient, EndpointUrl: "https://example.com/", Cache: cache, Requester: "DE111"})
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123", SkipCache())
+	assert.NoError(t, err)
+	_, ok := cache.Get("EE123")
+	assert.False(t, ok)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+
+	valid, err := v.Valid(context.Background(), "EE123", ForceRefresh(), WithRequesterOverride("fr222"))
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Len(t, requests, 3)
+	assert.Equal(t, CountryCode("DE"), requests[0].RequesterMemberStateCode)
+	assert.Equal(t, CountryCode("FR"), requests[2].RequesterMemberStateCode)
+	assert.Equal(t, "222", requests[2].RequesterNumber)
+
+	// the result of another requester is neither read nor replaced
+	result, err := v.Check(context.Background(), "EE123", WithRequesterOverride("fr333"))
+	assert.NoError(t, err)
+	assert.Len(t, requests, 4)
+	assert.Equal(t, "333", requests[3].RequesterNumber)
+	cached, ok := cache.Get("EE123@DE111")
+	assert.True(t, ok)
+	assert.NotSame(t, result, cached)
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "EE123", WithRequesterOverride("fr333"))
+	assert.NoError(t, err)
+	assert.Len(t, requests, 4)
+
+	_, err = v.Check(context.Background(), "EE123", SkipCache(), WithRequesterOverride("F"))
+	assert.EqualError(t, err, "invalid VAT provided F")
+}
+
+type blockingClient struct{}
+
+func (blockingClient) Do(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestCheckTimeout(t *testing.T) {
+
+	v, err := NewClient(&ClientConfig{HttpClient: blockingClient{}, EndpointUrl: "https://example.com/"})
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123", CheckTimeout(10*time.Millisecond))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,45 @@
+package vies
+
+import (
+	"context"
+	"time"
+)
+
+// ClockInterface tells the time to the client, caches, scheduler and
+// retention, and waits for the retries, polls, rate limiters and webhooks,
+// so that tests can control time instead of waiting, see viestest.Clock.
+type ClockInterface interface {
+	Now() time.Time
+	// After sends the current time on the returned channel once d has
+	// elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the clock of the time package, the default one.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// clockOrSystem returns clock, or SystemClock when it is nil.
+func clockOrSystem(clock ClockInterface) ClockInterface {
+	if clock == nil {
+		return SystemClock{}
+	}
+	return clock
+}
+
+// sleepUntil waits for t on clock, or for the context to be done.
+func sleepUntil(ctx context.Context, clock ClockInterface, t time.Time) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(t.Sub(clock.Now())):
+		return nil
+	}
+}
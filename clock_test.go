@@ -0,0 +1,63 @@
+package vies
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testClock is a clock whose timers fire at once, moving it forward by
+// their delay. It records the delays it was asked to wait.
+type testClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > 0 {
+		c.waits = append(c.waits, d)
+		c.now = c.now.Add(d)
+	}
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *testClock) Waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.waits
+}
+
+func TestSleepUntil(t *testing.T) {
+
+	clock := newTestClock()
+	assert.NoError(t, sleepUntil(context.Background(), clock, clock.Now().Add(time.Minute)))
+	assert.Equal(t, []time.Duration{time.Minute}, clock.Waits())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, sleepUntil(ctx, SystemClock{}, time.Now().Add(time.Hour)), context.Canceled)
+}
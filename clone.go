@@ -0,0 +1,70 @@
+package vies
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Clone returns a client built by NewClient from the configuration of
+// client, as last reloaded, with options applied on top, e.g. a tenant's
+// requester and rate limit:
+//
+//	tenant, err := client.Clone(
+//		vies.WithRequester(tenantVat),
+//		vies.WithRateLimiter(vies.NewRateLimiter(1, 1)),
+//	)
+//
+// The clone shares the HTTP client, the cache and the rate limiter of client
+// unless options replace them. WithTimeout applies when the HTTP client is an *http.Client,
+// whose transport stays shared. Interceptors and headers given by WithHeader
+// are added to the inherited ones, other options replace the inherited
+// settings. Options turning a behaviour on, such as WithExtraFields, can't
+// turn it off in the clone. The clone has a status cache of its own and
+// reloading either client doesn't affect the other.
+func (client *Client) Clone(opts ...Option) (*Client, error) {
+
+	s := client.settings.Load()
+	config := *s.config
+	config.Headers = config.Headers.Clone()
+	config.Interceptors = slices.Clip(config.Interceptors)
+
+	// lists given to the clone replace the inherited ones
+	allowed, blocked, responseHeaders := config.AllowedCountries, config.BlockedCountries, config.ResponseHeaders
+	config.AllowedCountries, config.BlockedCountries, config.ResponseHeaders = nil, nil, nil
+	config.HttpClient, config.Cache, config.Timeout = nil, nil, 0
+	config.RateLimiter = nil
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	if config.AllowedCountries == nil {
+		config.AllowedCountries = allowed
+	}
+	if config.BlockedCountries == nil {
+		config.BlockedCountries = blocked
+	}
+	if config.ResponseHeaders == nil {
+		config.ResponseHeaders = responseHeaders
+	}
+	if config.HttpClient == nil {
+		config.HttpClient = client.httpClient
+		if httpClient, ok := client.httpClient.(*http.Client); ok && config.Timeout > 0 {
+			copied := *httpClient
+			copied.Timeout = config.Timeout
+			config.HttpClient = &copied
+		}
+	}
+	if config.Cache == nil {
+		config.Cache = client.cache
+	}
+	// the limiter of client is shared unless replaced, whose rate then
+	// doesn't apply
+	if config.RateLimiter == nil {
+		config.RateLimiter = s.rateLimiter
+	} else {
+		config.RateLimit = 0
+	}
+
+	return NewClient(&config)
+}
@@ -0,0 +1,156 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+
+	var requests []checkRequest
+	var headers []http.Header
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		var body checkRequest
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		requests = append(requests, body)
+		headers = append(headers, req.Header.Clone())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	cache := NewMemoryCache(time.Minute)
+	v, err := New(WithHttpClient(httpClient), WithEndpoint("https://example.com/"), WithCache(cache),
+		WithRequester("DE111"), WithHeader("X-Api-Key", "secret"))
+	assert.NoError(t, err)
+
+	limiter := NewRateLimiter(5, 1)
+	tenant, err := v.Clone(WithRequester("fr222"), WithRateLimiter(limiter), WithHeader("X-Tenant", "acme"), WithTimeout(time.Second))
+	assert.NoError(t, err)
+	assert.Same(t, cache, tenant.cache)
+	assert.Same(t, limiter, tenant.settings.Load().rateLimiter)
+	assert.Nil(t, v.settings.Load().rateLimiter)
+	assert.Equal(t, time.Second, tenant.httpClient.(*http.Client).Timeout)
+	assert.Zero(t, httpClient.Timeout)
+
+	_, err = tenant.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "EE456")
+	assert.NoError(t, err)
+
+	assert.Equal(t, CountryCode("FR"), requests[0].RequesterMemberStateCode)
+	assert.Equal(t, CountryCode("DE"), requests[1].RequesterMemberStateCode)
+	assert.Equal(t, "secret", headers[0].Get("X-Api-Key"))
+	assert.Equal(t, "acme", headers[0].Get("X-Tenant"))
+	assert.Empty(t, headers[1].Get("X-Tenant"))
+
+	// the cache is shared, its results being kept per requester
+	_, err = tenant.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.Len(t, requests, 3)
+	assert.Equal(t, CountryCode("DE"), requests[2].RequesterMemberStateCode)
+
+	_, err = v.Clone(WithRequester("F"))
+	assert.EqualError(t, err, "invalid VAT provided F")
+}
+
+func TestCloneOptions(t *testing.T) {
+
+	var calls int
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Body:       io.NopCloser(bytes.NewBufferString(`{}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := New(WithHttpClient(httpClient), WithEndpoint("https://example.com/"), WithRetry(2, time.Millisecond))
+	assert.NoError(t, err)
+
+	t.Run("retry replaces the inherited one", func(t *testing.T) {
+		calls = 0
+		tenant, err := v.Clone(WithRetry(3, time.Millisecond))
+		assert.NoError(t, err)
+
+		_, err = tenant.Check(context.Background(), "EE123")
+		assert.Error(t, err)
+		assert.Equal(t, 3, calls)
+
+		calls = 0
+		_, err = v.Check(context.Background(), "EE123")
+		assert.Error(t, err)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("options are applied", func(t *testing.T) {
+		clock := newTestClock()
+		tenant, err := v.Clone(WithExtraFields(), WithStrictDecoding(), WithRawResponse(),
+			WithResponseHeaders("X-Trace"), WithMaxResponseSize(1024), WithClock(clock),
+			WithFakeResponses(map[string]CheckResult{"EE123": {CountryCode: "EE", VatNumber: "123", Valid: true}}))
+		assert.NoError(t, err)
+		assert.True(t, tenant.captureExtra)
+		assert.True(t, tenant.strict)
+		assert.True(t, tenant.keepRaw)
+		assert.Equal(t, []string{"X-Trace"}, tenant.responseHeaders)
+		assert.Equal(t, int64(1024), tenant.maxResponseSize)
+		assert.Same(t, clock, tenant.clock)
+		assert.False(t, v.captureExtra)
+		assert.Equal(t, SystemClock{}, v.clock)
+
+		calls = 0
+		result, err := tenant.Check(context.Background(), "EE123")
+		assert.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Zero(t, calls)
+	})
+}
+
+func TestCloneRebuilds(t *testing.T) {
+
+	var hosts []string
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		hosts = append(hosts, req.URL.Host)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"vow":{"available":true},"countries":[{"countryCode":"EE","availability":"Available"}]}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := NewClient(&ClientConfig{HttpClient: httpClient, EndpointUrl: "https://a.example.com/", StatusCacheTTL: time.Minute})
+	assert.NoError(t, err)
+	_, err = v.Status(context.Background())
+	assert.NoError(t, err)
+
+	// the status cache of the parent isn't reused for another endpoint
+	other, err := v.Clone(WithEndpoint("https://b.example.com/"))
+	assert.NoError(t, err)
+	assert.NotSame(t, v.status, other.status)
+	_, err = other.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, hosts)
+
+	// clones start from the reloaded settings
+	assert.NoError(t, v.Reload(&Config{Endpoint: "https://c.example.com/", Requester: "DE111", AllowedCountries: []string{"EE"}}))
+	tenant, err := v.Clone(WithAllowedCountries("FR"))
+	assert.NoError(t, err)
+	s := tenant.settings.Load()
+	assert.Equal(t, "c.example.com", s.endpoint.Host)
+	assert.Equal(t, "DE111", s.requester)
+	assert.Equal(t, map[string]bool{"FR": true}, s.allowedCountries)
+	assert.Same(t, httpClient, tenant.httpClient)
+}
@@ -0,0 +1,97 @@
+// Command vies-exporter polls the VIES status and exposes the availability
+// of VIES and of every member state as Prometheus gauges.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/alytsin/go-vies/viesmetrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	exitOk    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+
+	fs := flag.NewFlagSet("vies-exporter", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	endpoint := fs.String("endpoint", "", "VIES REST API endpoint URL")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout of a single request")
+	interval := fs.Duration("interval", time.Minute, "how often the status is polled")
+	listen := fs.String("listen", ":9732", "address the exporter listens on")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected arguments %q\n", fs.Args())
+		return exitUsage
+	}
+
+	metrics := viesmetrics.NewPrometheus()
+	client, err := vies.NewClient(&vies.ClientConfig{
+		HttpClient:  &http.Client{Timeout: *timeout},
+		EndpointUrl: *endpoint,
+		Metrics:     metrics,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher := vies.NewWatcher(client, &vies.WatcherConfig{Interval: *interval})
+	go func() { _ = watcher.Run(ctx) }()
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           handler(watcher, metrics),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(stdout, "listening on %s\n", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOk
+}
+
+// handler serves the availability gauges, and the metrics of the requests
+// made to poll them, at /metrics.
+func handler(watcher *vies.Watcher, metrics *viesmetrics.Prometheus) http.Handler {
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(viesmetrics.NewAvailability(watcher), metrics)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	return mux
+}
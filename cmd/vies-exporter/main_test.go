@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/alytsin/go-vies/viesmetrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunUsage(t *testing.T) {
+
+	cases := []struct {
+		name   string
+		args   []string
+		stderr string
+	}{
+		{
+			name:   "unknown flag",
+			args:   []string{"-bogus"},
+			stderr: "flag provided but not defined: -bogus",
+		},
+		{
+			name:   "arguments",
+			args:   []string{"status"},
+			stderr: `unexpected arguments ["status"]`,
+		},
+		{
+			name:   "endpoint",
+			args:   []string{"-endpoint", "://bogus"},
+			stderr: "missing protocol scheme",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, exitUsage, run(tt.args, &stdout, &stderr))
+			assert.Contains(t, stderr.String(), tt.stderr)
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"}]}`))
+	}))
+	defer upstream.Close()
+
+	metrics := viesmetrics.NewPrometheus()
+	client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: upstream.URL, Metrics: metrics})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watcher := vies.NewWatcher(client, &vies.WatcherConfig{Interval: time.Hour})
+	go func() { _ = watcher.Run(ctx) }()
+	assert.Eventually(t, func() bool { return watcher.Last() != nil }, time.Second, time.Millisecond)
+
+	server := httptest.NewServer(handler(watcher, metrics))
+	defer server.Close()
+
+	rsp, err := http.Get(server.URL + "/metrics")
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	body, err := io.ReadAll(rsp.Body)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, rsp.StatusCode)
+	assert.Contains(t, string(body), `vies_country_available{country="DE"} 1`)
+	assert.Contains(t, string(body), `vies_requests_total{country="",operation="status",outcome="success",status="200"} 1`)
+}
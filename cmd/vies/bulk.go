@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/alytsin/go-vies"
+)
+
+func runBulk(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+
+	fs, opts := newFlagSet("bulk", outputCsv, stderr)
+	file := fs.String("file", "", "CSV file with VAT numbers in the first column, stdin when empty")
+	parallel := fs.Int("parallel", 4, "number of concurrent checks")
+	rateLimit := fs.Float64("rate-limit", 0, "maximum checks per second, 0 for no limit")
+
+	if !opts.parse(fs, args, &stdout, &stderr) {
+		return exitInput
+	}
+
+	input := stdin
+	if *file != "" {
+		f, err := os.Open(*file)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitInput
+		}
+		defer f.Close()
+		input = f
+	}
+
+	vats, err := readVats(input)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInput
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInput
+	}
+
+	results := client.CheckAll(context.Background(), vats, &vies.BulkConfig{
+		Parallel:  *parallel,
+		RateLimit: *rateLimit,
+	})
+
+	if err := writeResults(stdout, opts.output, results, false); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitTransport
+	}
+	return resultsCode(results)
+}
+
+// readVats reads VAT numbers from the first column of CSV input, skipping
+// empty lines and a "vat" header.
+func readVats(r io.Reader) ([]string, error) {
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var vats []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		vat := strings.TrimSpace(record[0])
+		if vat == "" || (len(vats) == 0 && strings.EqualFold(vat, "vat")) {
+			continue
+		}
+		vats = append(vats, vat)
+	}
+
+	if len(vats) == 0 {
+		return nil, errors.New("no VAT numbers provided")
+	}
+	return vats, nil
+}
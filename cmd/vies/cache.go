@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/alytsin/go-vies"
+)
+
+func runCache(args []string, stdout, stderr io.Writer) int {
+
+	if len(args) == 0 || args[0] != "purge" {
+		fmt.Fprintln(stderr, "usage: vies cache purge --cache-dir <dir>")
+		return exitInput
+	}
+
+	fs := flag.NewFlagSet("cache purge", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("cache-dir", "", "directory caching results between runs")
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitInput
+	}
+	if *dir == "" {
+		fmt.Fprintln(stderr, "no cache directory provided")
+		return exitInput
+	}
+
+	cache, err := vies.NewFileCache(*dir, 0)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInput
+	}
+	if err := cache.Purge(); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitTransport
+	}
+
+	fmt.Fprintf(stdout, "purged %s\n", *dir)
+	return exitValid
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/alytsin/go-vies"
+)
+
+const usage = `Usage: vies <command> [flags] [arguments]
+
+Commands:
+  check <vat>...   check VAT numbers and print the VIES response
+  valid <vat>...   print whether VAT numbers are valid
+  status           print VIES and member state availability
+  bulk             check VAT numbers read from stdin or a CSV file
+  cache purge      remove all results from the cache directory
+  serve            run an HTTP gateway exposing /check/{vat}, /status, /healthz and /metrics
+
+Exit codes:
+  0  all VAT numbers are valid
+  1  at least one VAT number is invalid
+  2  VIES could not be reached or returned an error
+  3  invalid input or usage
+
+Run "vies <command> -h" for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitInput
+	}
+
+	switch args[0] {
+	case "check":
+		return runCheck(args[1:], stdout, stderr)
+	case "valid":
+		return runValid(args[1:], stdout, stderr)
+	case "status":
+		return runStatus(args[1:], stdout, stderr)
+	case "bulk":
+		return runBulk(args[1:], stdin, stdout, stderr)
+	case "cache":
+		return runCache(args[1:], stdout, stderr)
+	case "serve":
+		return runServe(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitValid
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+	return exitInput
+}
+
+type options struct {
+	command   string
+	endpoint  string
+	timeout   time.Duration
+	requester string
+	output    string
+	quiet     bool
+	cacheDir  string
+	cacheTtl  time.Duration
+}
+
+func newFlagSet(name, output string, stderr io.Writer) (*flag.FlagSet, *options) {
+	opts := &options{command: name}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.endpoint, "endpoint", "", "VIES REST API endpoint URL")
+	fs.DurationVar(&opts.timeout, "timeout", 30*time.Second, "timeout of a single request")
+	fs.StringVar(&opts.requester, "requester", "", "requester VAT number used to obtain a consultation number")
+	fs.StringVar(&opts.output, "output", output, "output format: json, table or csv")
+	fs.BoolVar(&opts.quiet, "quiet", false, "print nothing, report the outcome with the exit code only")
+	fs.StringVar(&opts.cacheDir, "cache-dir", "", "directory caching results between runs, disabled when empty")
+	fs.DurationVar(&opts.cacheTtl, "cache-ttl", 24*time.Hour, "how long cached results are reused")
+	return fs, opts
+}
+
+// parse parses the command line and, in quiet mode, replaces the writers
+// of the command with io.Discard.
+func (o *options) parse(fs *flag.FlagSet, args []string, stdout, stderr *io.Writer) bool {
+	if err := fs.Parse(args); err != nil {
+		return false
+	}
+	if o.command == "bulk" && o.output == outputJsonLines {
+		o.output = outputJson
+	}
+	if !validOutput(o.output) {
+		fmt.Fprintf(*stderr, "unsupported output format %q\n", o.output)
+		return false
+	}
+	if o.quiet {
+		*stdout = io.Discard
+		*stderr = io.Discard
+	}
+	return true
+}
+
+func (o *options) client() (*vies.Client, error) {
+	config, err := o.config()
+	if err != nil {
+		return nil, err
+	}
+	return vies.NewClient(config)
+}
+
+func (o *options) config() (*vies.ClientConfig, error) {
+
+	config := &vies.ClientConfig{
+		HttpClient:  &http.Client{Timeout: o.timeout},
+		EndpointUrl: o.endpoint,
+		Requester:   o.requester,
+	}
+
+	if o.cacheDir != "" {
+		cache, err := vies.NewFileCache(o.cacheDir, o.cacheTtl)
+		if err != nil {
+			return nil, err
+		}
+		config.Cache = cache
+	}
+
+	return config, nil
+}
+
+func runCheck(args []string, stdout, stderr io.Writer) int {
+	return checkArgs("check", outputJson, false, args, stdout, stderr)
+}
+
+func runValid(args []string, stdout, stderr io.Writer) int {
+	return checkArgs("valid", outputTable, true, args, stdout, stderr)
+}
+
+func checkArgs(name, output string, brief bool, args []string, stdout, stderr io.Writer) int {
+
+	fs, opts := newFlagSet(name, output, stderr)
+	if !opts.parse(fs, args, &stdout, &stderr) {
+		return exitInput
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "no VAT numbers provided")
+		return exitInput
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInput
+	}
+
+	results := make([]vies.BulkResult, 0, fs.NArg())
+	for _, vat := range fs.Args() {
+		result, err := client.Check(context.Background(), vat)
+		results = append(results, vies.BulkResult{Vat: vat, Result: result, Err: err})
+	}
+
+	if err := writeResults(stdout, opts.output, results, brief); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitTransport
+	}
+	return resultsCode(results)
+}
+
+func runStatus(args []string, stdout, stderr io.Writer) int {
+
+	fs, opts := newFlagSet("status", outputTable, stderr)
+	if !opts.parse(fs, args, &stdout, &stderr) {
+		return exitInput
+	}
+
+	client, err := opts.client()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInput
+	}
+
+	status, err := client.Status(context.Background())
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitTransport
+	}
+
+	if err := writeStatus(stdout, opts.output, status); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitTransport
+	}
+
+	if !status.Vow.Available {
+		return exitInvalid
+	}
+	return exitValid
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/check-vat-number":
+			body, _ := io.ReadAll(r.Body)
+			if bytes.Contains(body, []byte(`"vatNumber":"000"`)) {
+				_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"000","valid":false}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"123","valid":true,"name":"Acme"}`))
+		case "/check-status":
+			_, _ = w.Write([]byte(`{"vow":{"available":true},"countries":[{"countryCode":"EE","availability":"Available"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRun(t *testing.T) {
+	server := newTestServer(t)
+
+	cases := []struct {
+		name   string
+		args   []string
+		stdin  string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "no command",
+			args:   nil,
+			code:   exitInput,
+			stderr: usage,
+		},
+		{
+			name:   "unknown command",
+			args:   []string{"bogus"},
+			code:   exitInput,
+			stderr: "unknown command \"bogus\"\n\n" + usage,
+		},
+		{
+			name:   "valid",
+			args:   []string{"valid", "--endpoint", server.URL, "EE123", "EE000"},
+			code:   exitInvalid,
+			stdout: "VAT    VALID  ERROR\nEE123  true   \nEE000  false  \n",
+		},
+		{
+			name:   "valid csv",
+			args:   []string{"valid", "--endpoint", server.URL, "--output", "csv", "EE123"},
+			stdout: "vat,valid,error\nEE123,true,\n",
+		},
+		{
+			name:   "valid json",
+			args:   []string{"valid", "--endpoint", server.URL, "--output", "json", "EE123"},
+			stdout: "{\"vat\":\"EE123\",\"valid\":true}\n",
+		},
+		{
+			name: "valid quiet",
+			args: []string{"valid", "--endpoint", server.URL, "--quiet", "EE000", "E"},
+			code: exitInput,
+		},
+		{
+			name:   "valid without arguments",
+			args:   []string{"valid", "--endpoint", server.URL},
+			code:   exitInput,
+			stderr: "no VAT numbers provided\n",
+		},
+		{
+			name:   "check",
+			args:   []string{"check", "--endpoint", server.URL, "EE123"},
+			stdout: "{\"vat\":\"EE123\",\"result\":{\"countryCode\":\"EE\",\"address\":\"\",\"vatNumber\":\"123\",\"vat\":\"EE123\",\"valid\":true,\"name\":\"Acme\"}}\n",
+		},
+		{
+			name:   "check table",
+			args:   []string{"check", "--endpoint", server.URL, "--output", "table", "EE123"},
+			stdout: "VAT    VALID  NAME  ADDRESS  ERROR\nEE123  true   Acme           \n",
+		},
+		{
+			name:   "check invalid input",
+			args:   []string{"check", "--endpoint", server.URL, "E"},
+			code:   exitInput,
+			stdout: "{\"vat\":\"E\",\"error\":\"invalid VAT provided E\"}\n",
+		},
+		{
+			name:   "check transport error",
+			args:   []string{"check", "--endpoint", server.URL + "/missing/", "--output", "csv", "EE123"},
+			code:   exitTransport,
+			stdout: "vat,country_code,vat_number,valid,name,address,error\nEE123,,,,,,unexpected end of JSON input\n",
+		},
+		{
+			name:   "invalid requester",
+			args:   []string{"check", "--endpoint", server.URL, "--requester", "D", "EE123"},
+			code:   exitInput,
+			stderr: "invalid VAT provided D\n",
+		},
+		{
+			name:   "unsupported output",
+			args:   []string{"check", "--output", "xml", "EE123"},
+			code:   exitInput,
+			stderr: "unsupported output format \"xml\"\n",
+		},
+		{
+			name:   "status",
+			args:   []string{"status", "--endpoint", server.URL},
+			stdout: "VoW  true\nEE   Available\n",
+		},
+		{
+			name:   "status csv",
+			args:   []string{"status", "--endpoint", server.URL, "--output", "csv"},
+			stdout: "country_code,availability\nEE,Available\n",
+		},
+		{
+			name:   "bulk csv",
+			args:   []string{"bulk", "--endpoint", server.URL},
+			stdin:  "vat\nEE123\n\nEE000,ignored\n",
+			code:   exitInvalid,
+			stdout: "vat,country_code,vat_number,valid,name,address,error\nEE123,EE,123,true,Acme,,\nEE000,EE,000,false,,,\n",
+		},
+		{
+			name:   "bulk json with error",
+			args:   []string{"bulk", "--endpoint", server.URL, "--output", "json", "--parallel", "1"},
+			stdin:  "E\nEE123\n",
+			code:   exitInput,
+			stdout: "{\"vat\":\"E\",\"error\":\"invalid VAT provided E\"}\n{\"vat\":\"EE123\",\"result\":{\"countryCode\":\"EE\",\"address\":\"\",\"vatNumber\":\"123\",\"vat\":\"EE123\",\"valid\":true,\"name\":\"Acme\"}}\n",
+		},
+		{
+			name:   "bulk jsonl",
+			args:   []string{"bulk", "--endpoint", server.URL, "--output", "jsonl"},
+			stdin:  "EE123\n",
+			stdout: "{\"vat\":\"EE123\",\"result\":{\"countryCode\":\"EE\",\"address\":\"\",\"vatNumber\":\"123\",\"vat\":\"EE123\",\"valid\":true,\"name\":\"Acme\"}}\n",
+		},
+		{
+			name:   "jsonl is bulk only",
+			args:   []string{"check", "--endpoint", server.URL, "--output", "jsonl", "EE123"},
+			code:   exitInput,
+			stderr: "unsupported output format \"jsonl\"\n",
+		},
+		{
+			name:   "bulk empty input",
+			args:   []string{"bulk", "--endpoint", server.URL},
+			code:   exitInput,
+			stderr: "no VAT numbers provided\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.stdout, stdout.String())
+			assert.Equal(t, tt.stderr, stderr.String())
+		})
+	}
+}
+
+func TestRunCache(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"123","valid":true}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	args := []string{"valid", "--endpoint", server.URL, "--cache-dir", dir, "--quiet", "EE123"}
+
+	assert.Equal(t, exitValid, run(args, nil, io.Discard, io.Discard))
+	assert.Equal(t, exitValid, run(args, nil, io.Discard, io.Discard))
+	assert.Equal(t, 1, calls)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitValid, run([]string{"cache", "purge", "--cache-dir", dir}, nil, &stdout, &stderr))
+	assert.Equal(t, "purged "+dir+"\n", stdout.String())
+	assert.Empty(t, stderr.String())
+
+	assert.Equal(t, exitValid, run(args, nil, io.Discard, io.Discard))
+	assert.Equal(t, 2, calls)
+
+	stderr.Reset()
+	assert.Equal(t, exitInput, run([]string{"cache", "purge"}, nil, io.Discard, &stderr))
+	assert.Equal(t, "no cache directory provided\n", stderr.String())
+
+	stderr.Reset()
+	assert.Equal(t, exitInput, run([]string{"cache"}, nil, io.Discard, &stderr))
+	assert.Equal(t, "usage: vies cache purge --cache-dir <dir>\n", stderr.String())
+}
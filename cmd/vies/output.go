@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alytsin/go-vies"
+)
+
+const (
+	exitValid     = 0
+	exitInvalid   = 1
+	exitTransport = 2
+	exitInput     = 3
+)
+
+const (
+	outputJson  = "json"
+	outputTable = "table"
+	outputCsv   = "csv"
+	// outputJsonLines is the format name of the first release of vies bulk,
+	// kept as an alias of outputJson.
+	outputJsonLines = "jsonl"
+)
+
+func validOutput(format string) bool {
+	switch format {
+	case outputJson, outputTable, outputCsv:
+		return true
+	}
+	return false
+}
+
+// errorCode maps an error returned by the client to the exit code of the
+// command: malformed input is reported separately from transport failures.
+func errorCode(err error) int {
+	var apiErr *vies.ApiError
+	if errors.Is(err, vies.ErrInvalidVat) || (errors.As(err, &apiErr) && apiErr.Err == "INVALID_INPUT") {
+		return exitInput
+	}
+	return exitTransport
+}
+
+// resultsCode returns the most severe exit code of all results.
+func resultsCode(results []vies.BulkResult) int {
+	code := exitValid
+	for _, r := range results {
+		c := exitValid
+		if r.Err != nil {
+			c = errorCode(r.Err)
+		} else if !r.Result.Valid {
+			c = exitInvalid
+		}
+		code = max(code, c)
+	}
+	return code
+}
+
+type resultLine struct {
+	Vat    string            `json:"vat"`
+	Valid  *bool             `json:"valid,omitempty"`
+	Result *vies.CheckResult `json:"result,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// writeResults prints check results in the given format. Brief output
+// contains only the validity of each VAT number.
+func writeResults(w io.Writer, format string, results []vies.BulkResult, brief bool) error {
+	switch format {
+	case outputJson:
+		return writeResultsJson(w, results, brief)
+	case outputCsv:
+		return writeResultsCsv(w, results, brief)
+	default:
+		return writeResultsTable(w, results, brief)
+	}
+}
+
+func writeResultsJson(w io.Writer, results []vies.BulkResult, brief bool) error {
+
+	encoder := json.NewEncoder(w)
+	for _, r := range results {
+		line := resultLine{Vat: r.Vat}
+		if r.Err != nil {
+			line.Error = r.Err.Error()
+		} else if brief {
+			line.Valid = &r.Result.Valid
+		} else {
+			line.Result = r.Result
+		}
+		if err := encoder.Encode(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeResultsCsv(w io.Writer, results []vies.BulkResult, brief bool) error {
+
+	writer := csv.NewWriter(w)
+	if brief {
+		_ = writer.Write([]string{"vat", "valid", "error"})
+	} else {
+		_ = writer.Write([]string{"vat", "country_code", "vat_number", "valid", "name", "address", "error"})
+	}
+
+	for _, r := range results {
+		switch {
+		case r.Err != nil && brief:
+			_ = writer.Write([]string{r.Vat, "", r.Err.Error()})
+		case r.Err != nil:
+			_ = writer.Write([]string{r.Vat, "", "", "", "", "", r.Err.Error()})
+		case brief:
+			_ = writer.Write([]string{r.Vat, strconv.FormatBool(r.Result.Valid), ""})
+		default:
+			_ = writer.Write([]string{
+				r.Vat,
+				string(r.Result.CountryCode),
+				r.Result.VatNumber,
+				strconv.FormatBool(r.Result.Valid),
+				r.Result.Name,
+				r.Result.Address,
+				"",
+			})
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeResultsTable(w io.Writer, results []vies.BulkResult, brief bool) error {
+
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if brief {
+		fmt.Fprintln(writer, "VAT\tVALID\tERROR")
+	} else {
+		fmt.Fprintln(writer, "VAT\tVALID\tNAME\tADDRESS\tERROR")
+	}
+
+	for _, r := range results {
+		switch {
+		case r.Err != nil && brief:
+			fmt.Fprintf(writer, "%s\t-\t%s\n", r.Vat, r.Err)
+		case r.Err != nil:
+			fmt.Fprintf(writer, "%s\t-\t\t\t%s\n", r.Vat, r.Err)
+		case brief:
+			fmt.Fprintf(writer, "%s\t%t\t\n", r.Vat, r.Result.Valid)
+		default:
+			fmt.Fprintf(writer, "%s\t%t\t%s\t%s\t\n", r.Vat, r.Result.Valid, oneLine(r.Result.Name), oneLine(r.Result.Address))
+		}
+	}
+
+	return writer.Flush()
+}
+
+func writeStatus(w io.Writer, format string, status *vies.Status) error {
+	switch format {
+	case outputJson:
+		return json.NewEncoder(w).Encode(status)
+	case outputCsv:
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{"country_code", "availability"})
+		for _, country := range status.Countries {
+			_ = writer.Write([]string{string(country.CountryCode), string(country.Availability)})
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(writer, "VoW\t%t\n", status.Vow.Available)
+		for _, country := range status.Countries {
+			fmt.Fprintf(writer, "%s\t%s\n", country.CountryCode, country.Availability)
+		}
+		return writer.Flush()
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
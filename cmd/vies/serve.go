@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/alytsin/go-vies/viesmetrics"
+	"github.com/alytsin/go-vies/viesserver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func runServe(args []string, stdout, stderr io.Writer) int {
+
+	fs, opts := newFlagSet("serve", outputJson, stderr)
+	listen := fs.String("listen", ":8080", "address the server listens on")
+	rateLimit := fs.Float64("rate-limit", 5, "maximum requests per second sent to VIES, 0 for no limit")
+	burst := fs.Int("burst", 10, "maximum burst of requests sent to VIES")
+	eventsInterval := fs.Duration("events-interval", time.Minute, "how often availability is polled for /events, 0 disables")
+	if !opts.parse(fs, args, &stdout, &stderr) {
+		return exitInput
+	}
+
+	config, err := opts.config()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInput
+	}
+	if config.Cache == nil {
+		config.Cache = vies.NewMemoryCache(opts.cacheTtl)
+	}
+	metrics := viesmetrics.NewPrometheus()
+	config.Metrics = metrics
+	if *rateLimit > 0 {
+		config.RateLimiter = vies.NewRateLimiter(*rateLimit, *burst)
+	}
+
+	client, err := vies.NewClient(config)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitInput
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	handler := viesserver.New(client)
+	if *eventsInterval > 0 {
+		handler.WithEvents(ctx, *eventsInterval)
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(metrics)
+
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(stdout, "listening on %s\n", *listen)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, err)
+		return exitTransport
+	}
+	return exitValid
+}
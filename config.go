@@ -0,0 +1,186 @@
+package vies
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration written as "30s" or "1h30m" in JSON, YAML
+// and environment variables.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Config is the serializable part of ClientConfig, meant to wire clients
+// the same way across services. It is loaded with LoadConfig or
+// ConfigFromEnv and turned into a client with NewClientFromConfig.
+type Config struct {
+	Endpoint     string   `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	EoriEndpoint string   `json:"eoriEndpoint,omitempty" yaml:"eoriEndpoint,omitempty"`
+	Timeout      Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// RetryAttempts enables retries when above one, see RetryConfig.
+	RetryAttempts int      `json:"retryAttempts,omitempty" yaml:"retryAttempts,omitempty"`
+	RetryBackoff  Duration `json:"retryBackoff,omitempty" yaml:"retryBackoff,omitempty"`
+	// CacheTTL enables caching, in CacheDir when set and in memory
+	// otherwise.
+	CacheTTL Duration `json:"cacheTtl,omitempty" yaml:"cacheTtl,omitempty"`
+	CacheDir string   `json:"cacheDir,omitempty" yaml:"cacheDir,omitempty"`
+	// RateLimit is the number of requests per second sent to VIES,
+	// unlimited when zero.
+	RateLimit        float64  `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
+	Requester        string   `json:"requester,omitempty" yaml:"requester,omitempty"`
+	AllowedCountries []string `json:"allowedCountries,omitempty" yaml:"allowedCountries,omitempty"`
+	BlockedCountries []string `json:"blockedCountries,omitempty" yaml:"blockedCountries,omitempty"`
+	UserAgent        string   `json:"userAgent,omitempty" yaml:"userAgent,omitempty"`
+}
+
+// LoadConfig reads a configuration from a JSON file, or a YAML one when
+// the extension is .yaml or .yml.
+func LoadConfig(path string) (*Config, error) {
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &config)
+	default:
+		err = json.Unmarshal(content, &config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// ConfigFromEnv reads a configuration from the environment variables named
+// after the fields with prefix, VIES by default:
+//
+//	VIES_ENDPOINT            VIES_CACHE_TTL
+//	VIES_EORI_ENDPOINT       VIES_CACHE_DIR
+//	VIES_TIMEOUT             VIES_RATE_LIMIT
+//	VIES_RETRY_ATTEMPTS      VIES_REQUESTER
+//	VIES_RETRY_BACKOFF       VIES_ALLOWED_COUNTRIES (comma separated)
+//	VIES_USER_AGENT          VIES_BLOCKED_COUNTRIES (comma separated)
+func ConfigFromEnv(prefix string) (*Config, error) {
+
+	if prefix == "" {
+		prefix = "VIES"
+	}
+	env := func(name string) (string, bool) {
+		v, ok := os.LookupEnv(prefix + "_" + name)
+		return strings.TrimSpace(v), ok && strings.TrimSpace(v) != ""
+	}
+
+	var config Config
+	var err error
+
+	config.Endpoint, _ = env("ENDPOINT")
+	config.EoriEndpoint, _ = env("EORI_ENDPOINT")
+	config.CacheDir, _ = env("CACHE_DIR")
+	config.Requester, _ = env("REQUESTER")
+	config.UserAgent, _ = env("USER_AGENT")
+
+	durations := map[string]*Duration{
+		"TIMEOUT":       &config.Timeout,
+		"RETRY_BACKOFF": &config.RetryBackoff,
+		"CACHE_TTL":     &config.CacheTTL,
+	}
+	for name, d := range durations {
+		if v, ok := env(name); ok {
+			if err := d.UnmarshalText([]byte(v)); err != nil {
+				return nil, fmt.Errorf("%s_%s: %w", prefix, name, err)
+			}
+		}
+	}
+	if v, ok := env("RETRY_ATTEMPTS"); ok {
+		if config.RetryAttempts, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("%s_RETRY_ATTEMPTS: %w", prefix, err)
+		}
+	}
+	if v, ok := env("RATE_LIMIT"); ok {
+		if config.RateLimit, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("%s_RATE_LIMIT: %w", prefix, err)
+		}
+	}
+	lists := map[string]*[]string{
+		"ALLOWED_COUNTRIES": &config.AllowedCountries,
+		"BLOCKED_COUNTRIES": &config.BlockedCountries,
+	}
+	for name, list := range lists {
+		if v, ok := env(name); ok {
+			for _, code := range strings.Split(v, ",") {
+				if code = strings.TrimSpace(code); code != "" {
+					*list = append(*list, code)
+				}
+			}
+		}
+	}
+
+	return &config, nil
+}
+
+// ClientConfig returns the ClientConfig described by config, to be
+// completed with the fields that can't be serialized.
+func (config *Config) ClientConfig() (*ClientConfig, error) {
+
+	c := &ClientConfig{
+		EndpointUrl:      config.Endpoint,
+		EoriEndpointUrl:  config.EoriEndpoint,
+		Timeout:          time.Duration(config.Timeout),
+		Requester:        config.Requester,
+		AllowedCountries: config.AllowedCountries,
+		BlockedCountries: config.BlockedCountries,
+		UserAgent:        config.UserAgent,
+		RateLimit:        config.RateLimit,
+	}
+	if config.RetryAttempts > 1 {
+		c.Retry = &RetryConfig{MaxAttempts: config.RetryAttempts, Backoff: time.Duration(config.RetryBackoff)}
+	}
+	if config.CacheTTL > 0 {
+		if config.CacheDir == "" {
+			c.Cache = NewMemoryCache(time.Duration(config.CacheTTL))
+		} else {
+			cache, err := NewFileCache(config.CacheDir, time.Duration(config.CacheTTL))
+			if err != nil {
+				return nil, err
+			}
+			c.Cache = cache
+		}
+	}
+	return c, nil
+}
+
+// NewClientFromConfig returns a client configured by config:
+//
+//	config, err := vies.ConfigFromEnv("")
+//	...
+//	client, err := vies.NewClientFromConfig(config)
+func NewClientFromConfig(config *Config) (*Client, error) {
+	c, err := config.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c)
+}
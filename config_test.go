@@ -0,0 +1,118 @@
+package vies
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+
+	expected := &Config{
+		Endpoint:         "https://example.com/",
+		Timeout:          Duration(10 * time.Second),
+		RetryAttempts:    3,
+		RetryBackoff:     Duration(time.Second),
+		CacheTTL:         Duration(24 * time.Hour),
+		RateLimit:        2.5,
+		Requester:        "DE123",
+		AllowedCountries: []string{"DE", "FR"},
+	}
+
+	tests := map[string]string{
+		"vies.json": `{"endpoint":"https://example.com/","timeout":"10s","retryAttempts":3,"retryBackoff":"1s",
+			"cacheTtl":"24h","rateLimit":2.5,"requester":"DE123","allowedCountries":["DE","FR"]}`,
+		"vies.yaml": `
+endpoint: https://example.com/
+timeout: 10s
+retryAttempts: 3
+retryBackoff: 1s
+cacheTtl: 24h
+rateLimit: 2.5
+requester: DE123
+allowedCountries: [DE, FR]
+`,
+	}
+
+	dir := t.TempDir()
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name)
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+			config, err := LoadConfig(path)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, config)
+		})
+	}
+
+	t.Run("invalid duration", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.json")
+		assert.NoError(t, os.WriteFile(path, []byte(`{"timeout":"soon"}`), 0o600))
+		_, err := LoadConfig(path)
+		assert.ErrorContains(t, err, "invalid.json")
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		out, err := json.Marshal(&Config{Timeout: Duration(90 * time.Second)})
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"timeout":"1m30s"}`, string(out))
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+
+	t.Setenv("BILLING_ENDPOINT", "https://example.com/")
+	t.Setenv("BILLING_TIMEOUT", "5s")
+	t.Setenv("BILLING_RETRY_ATTEMPTS", "4")
+	t.Setenv("BILLING_CACHE_TTL", "1h")
+	t.Setenv("BILLING_CACHE_DIR", t.TempDir())
+	t.Setenv("BILLING_RATE_LIMIT", "3")
+	t.Setenv("BILLING_ALLOWED_COUNTRIES", "de, fr,")
+	t.Setenv("BILLING_BLOCKED_COUNTRIES", "XI")
+
+	config, err := ConfigFromEnv("BILLING")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/", config.Endpoint)
+	assert.Equal(t, Duration(5*time.Second), config.Timeout)
+	assert.Equal(t, 4, config.RetryAttempts)
+	assert.Equal(t, Duration(time.Hour), config.CacheTTL)
+	assert.Equal(t, 3.0, config.RateLimit)
+	assert.Equal(t, []string{"de", "fr"}, config.AllowedCountries)
+	assert.Equal(t, []string{"XI"}, config.BlockedCountries)
+
+	c, err := config.ClientConfig()
+	assert.NoError(t, err)
+	assert.IsType(t, &FileCache{}, c.Cache)
+	assert.Equal(t, 4, c.Retry.MaxAttempts)
+	assert.Equal(t, 3.0, c.RateLimit)
+
+	t.Setenv("BILLING_RATE_LIMIT", "fast")
+	_, err = ConfigFromEnv("BILLING")
+	assert.EqualError(t, err, `BILLING_RATE_LIMIT: strconv.ParseFloat: parsing "fast": invalid syntax`)
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+
+	v, err := NewClientFromConfig(&Config{AllowedCountries: []string{"de"}, CacheTTL: Duration(time.Minute)})
+	assert.NoError(t, err)
+	assert.IsType(t, &MemoryCache{}, v.cache)
+
+	_, err = v.Check(context.Background(), "FR123")
+	assert.True(t, errors.Is(err, ErrCountryNotAllowed))
+	assert.EqualError(t, err, "member state not allowed FR")
+
+	// the limiter built from the rate is kept by a reload at the same rate
+	v, err = NewClientFromConfig(&Config{RateLimit: 2})
+	assert.NoError(t, err)
+	limiter := v.settings.Load().rateLimiter
+	assert.NotNil(t, limiter)
+	assert.NoError(t, v.Reload(&Config{RateLimit: 2}))
+	assert.Same(t, limiter, v.settings.Load().rateLimiter)
+}
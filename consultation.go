@@ -0,0 +1,148 @@
+package vies
+
+import (
+	"encoding/base64"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+)
+
+// ConsultationProof is a printable record of a check made on behalf of a
+// requester, for audit files: WriteHTML renders it and the viespdf package
+// renders it as PDF.
+type ConsultationProof struct {
+	Result *CheckResult
+	// Requester is the VAT number the check was made on behalf of, and
+	// RequesterName its name, both optional.
+	Requester     string
+	RequesterName string
+	// GeneratedAt is when the document was made, now when zero.
+	GeneratedAt time.Time
+	// Clock tells the time when GeneratedAt is zero, the system clock when
+	// nil.
+	Clock ClockInterface
+}
+
+// ProofSection is a titled group of fields of a ConsultationProof.
+type ProofSection struct {
+	Title  string
+	Fields []ProofField
+}
+
+// ProofField is a labelled value of a ConsultationProof, Value may span
+// several lines.
+type ProofField struct {
+	Label string
+	Value string
+}
+
+// NewConsultationProof returns the proof of result, checked on behalf of
+// requester.
+func NewConsultationProof(result *CheckResult, requester string) *ConsultationProof {
+	return &ConsultationProof{Result: result, Requester: requester}
+}
+
+// Title is the title of the document.
+func (proof *ConsultationProof) Title() string {
+	return "VIES VAT number validation"
+}
+
+// Sections returns the content of the document, in the order in which tax
+// advisors read it: the consultation, the requester, then the trader and
+// the result.
+func (proof *ConsultationProof) Sections() []ProofSection {
+
+	result := proof.Result
+	generatedAt := proof.GeneratedAt
+	if generatedAt.IsZero() {
+		generatedAt = clockOrSystem(proof.Clock).Now()
+	}
+
+	consultation := []ProofField{
+		{"Consultation number", orNone(result.RequestIdentifier)},
+		{"Request date", orNone(result.RequestDate)},
+	}
+	if result.CheckedAt != nil {
+		consultation = append(consultation, ProofField{"Checked at", formatProofTime(*result.CheckedAt)})
+	}
+	consultation = append(consultation, ProofField{"Document generated at", formatProofTime(generatedAt)})
+
+	requester := []ProofField{{"VAT number", orNone(proof.Requester)}}
+	if proof.RequesterName != "" {
+		requester = append(requester, ProofField{"Name", proof.RequesterName})
+	}
+
+	outcome := "Invalid: the VAT number is not active for intra-Community transactions"
+	if result.Valid {
+		outcome = "Valid: the VAT number is active for intra-Community transactions"
+	}
+	vat := result.Vat
+	if vat == "" {
+		vat = string(result.CountryCode) + result.VatNumber
+	}
+
+	sections := []ProofSection{
+		{Title: "Consultation", Fields: consultation},
+		{Title: "Requester", Fields: requester},
+		{Title: "Trader", Fields: []ProofField{
+			{"Member state", string(result.CountryCode)},
+			{"VAT number", vat},
+			{"Name", orNone(result.Name)},
+			{"Address", orNone(strings.TrimSpace(result.Address))},
+		}},
+		{Title: "Result", Fields: []ProofField{{"Status", outcome}}},
+	}
+
+	if result.Proof != nil {
+		sections = append(sections, ProofSection{Title: "Signature", Fields: []ProofField{
+			{"Algorithm", result.Proof.Algorithm},
+			{"Signed at", formatProofTime(result.Proof.SignedAt)},
+			{"Signature", base64.StdEncoding.EncodeToString(result.Proof.Signature)},
+		}})
+	}
+	return sections
+}
+
+var consultationTemplate = template.Must(template.New("consultation").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 11pt; margin: 2cm; color: #000; }
+h1 { font-size: 16pt; border-bottom: 1px solid #000; padding-bottom: 4pt; }
+h2 { font-size: 12pt; margin-top: 16pt; }
+table { border-collapse: collapse; width: 100%; }
+th { text-align: left; font-weight: normal; color: #444; width: 35%; vertical-align: top; padding: 2pt 8pt 2pt 0; }
+td { padding: 2pt 0; white-space: pre-line; word-break: break-all; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Sections}}<h2>{{.Title}}</h2>
+<table>
+{{range .Fields}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+// WriteHTML writes the proof as a standalone HTML page, ready to print.
+func (proof *ConsultationProof) WriteHTML(w io.Writer) error {
+	return consultationTemplate.Execute(w, struct {
+		Title    string
+		Sections []ProofSection
+	}{proof.Title(), proof.Sections()})
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func formatProofTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05 UTC")
+}
@@ -0,0 +1,64 @@
+package vies
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsultationProof(t *testing.T) {
+
+	checkedAt := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	result := &CheckResult{
+		CountryCode:       "FR",
+		VatNumber:         "40303265045",
+		Vat:               "FR40303265045",
+		Valid:             true,
+		Name:              "SA <ODIGEO>",
+		Address:           "1 RUE DE LA PAIX\n75002 PARIS",
+		RequestDate:       "2024-05-01+02:00",
+		RequestIdentifier: "WAPIAAAAYxyz",
+		CheckedAt:         &checkedAt,
+	}
+	assert.NoError(t, SignResult(result, HmacSigner([]byte("secret")), time.Now()))
+
+	proof := NewConsultationProof(result, "DE123456789")
+	proof.GeneratedAt = checkedAt.Add(time.Minute)
+
+	sections := proof.Sections()
+	assert.Equal(t, []string{"Consultation", "Requester", "Trader", "Result", "Signature"}, sectionTitles(sections))
+	assert.Equal(t, ProofField{"Consultation number", "WAPIAAAAYxyz"}, sections[0].Fields[0])
+	assert.Equal(t, ProofField{"Checked at", "2024-05-01 10:30:00 UTC"}, sections[0].Fields[2])
+	assert.Equal(t, ProofField{"VAT number", "DE123456789"}, sections[1].Fields[0])
+
+	generated := NewConsultationProof(result, "")
+	generated.Clock = newTestClock()
+	assert.Equal(t, ProofField{"Document generated at", "2024-03-01 12:00:00 UTC"}, generated.Sections()[0].Fields[3])
+
+	var b bytes.Buffer
+	assert.NoError(t, proof.WriteHTML(&b))
+	html := b.String()
+	assert.Contains(t, html, "<td>WAPIAAAAYxyz</td>")
+	assert.Contains(t, html, "<td>FR40303265045</td>")
+	assert.Contains(t, html, "SA &lt;ODIGEO&gt;")
+	assert.Contains(t, html, "Valid: the VAT number is active")
+	assert.Contains(t, html, "HMAC-SHA256")
+
+	// checks without a requester have no consultation number
+	proof = NewConsultationProof(&CheckResult{CountryCode: "FR", VatNumber: "123"}, "")
+	sections = proof.Sections()
+	assert.Equal(t, []string{"Consultation", "Requester", "Trader", "Result"}, sectionTitles(sections))
+	assert.Equal(t, ProofField{"Consultation number", "-"}, sections[0].Fields[0])
+	assert.Equal(t, ProofField{"VAT number", "FR123"}, sections[2].Fields[1])
+	assert.Contains(t, sections[3].Fields[0].Value, "Invalid")
+}
+
+func sectionTitles(sections []ProofSection) []string {
+	titles := make([]string, len(sections))
+	for i, section := range sections {
+		titles[i] = section.Title
+	}
+	return titles
+}
@@ -0,0 +1,48 @@
+package vies
+
+import (
+	"context"
+	"fmt"
+)
+
+const defaultCorrelationHeader = "X-Correlation-ID"
+
+type correlationKey struct{}
+
+// WithCorrelationID returns a context carrying the ID of the request that
+// triggered the calls made with it.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationIDFromContext returns the ID stored by WithCorrelationID. It is
+// the default ClientConfig.CorrelationID extractor.
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// CorrelationError annotates an error with the correlation ID of the call
+// that failed.
+type CorrelationError struct {
+	CorrelationID string
+	Err           error
+}
+
+func (e *CorrelationError) Error() string {
+	return fmt.Sprintf("%v (correlation id %s)", e.Err, e.CorrelationID)
+}
+
+func (e *CorrelationError) Unwrap() error {
+	return e.Err
+}
+
+func (client *Client) correlate(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if id := client.correlationID(ctx); id != "" {
+		return &CorrelationError{CorrelationID: id, Err: err}
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationID(t *testing.T) {
+	var header http.Header
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		header = req.Header
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"errorWrappers":[{"error":"INVALID_INPUT","message":"msg"}]}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	t.Run("default extractor", func(t *testing.T) {
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+		assert.NoError(t, err)
+
+		_, err = v.Check(WithCorrelationID(context.Background(), "req-1"), "EE123")
+		assert.Equal(t, "req-1", header.Get("X-Correlation-ID"))
+		assert.EqualError(t, err, "INVALID_INPUT: msg (correlation id req-1)")
+
+		var correlationErr *CorrelationError
+		assert.ErrorAs(t, err, &correlationErr)
+		assert.Equal(t, "req-1", correlationErr.CorrelationID)
+
+		var apiErr *ApiError
+		assert.ErrorAs(t, err, &apiErr)
+	})
+
+	t.Run("custom extractor and header", func(t *testing.T) {
+		type key struct{}
+		v, err := NewClient(&ClientConfig{
+			HttpClient:  client,
+			EndpointUrl: "https://example.com/",
+			CorrelationID: func(ctx context.Context) string {
+				id, _ := ctx.Value(key{}).(string)
+				return id
+			},
+			CorrelationHeader: "X-Request-ID",
+		})
+		assert.NoError(t, err)
+
+		_, err = v.Status(context.WithValue(context.Background(), key{}, "req-2"))
+		assert.Equal(t, "req-2", header.Get("X-Request-ID"))
+		assert.Empty(t, header.Get("X-Correlation-ID"))
+		assert.EqualError(t, err, "INVALID_INPUT: msg (correlation id req-2)")
+	})
+
+	t.Run("without id", func(t *testing.T) {
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+		assert.NoError(t, err)
+
+		_, err = v.Status(context.Background())
+		assert.Empty(t, header.Get("X-Correlation-ID"))
+		assert.EqualError(t, err, "INVALID_INPUT: msg")
+	})
+}
@@ -0,0 +1,36 @@
+package vies
+
+import (
+	"strings"
+
+	"github.com/alytsin/go-vies/viescountries"
+)
+
+// CountryCode is the country prefix of a VAT number as used by VIES, such
+// as EE, EL for Greece or XI for Northern Ireland. It is decoded upper
+// cased.
+type CountryCode string
+
+// Valid reports whether the code is made of two upper-case letters.
+func (c CountryCode) Valid() bool {
+	return len(c) == 2 && isLetters(string(c))
+}
+
+// IsVIESMember reports whether the code is the VIES code of a member
+// state.
+func (c CountryCode) IsVIESMember() bool {
+	return c.Valid() && viescountries.IsMember(string(c))
+}
+
+func (c CountryCode) String() string {
+	return string(c)
+}
+
+func (c CountryCode) MarshalText() ([]byte, error) {
+	return []byte(c), nil
+}
+
+func (c *CountryCode) UnmarshalText(text []byte) error {
+	*c = CountryCode(strings.ToUpper(strings.TrimSpace(string(text))))
+	return nil
+}
@@ -0,0 +1,40 @@
+package vies
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountryCode(t *testing.T) {
+
+	cases := []struct {
+		code   CountryCode
+		valid  bool
+		member bool
+	}{
+		{code: "EE", valid: true, member: true},
+		{code: "XI", valid: true, member: true},
+		{code: "GB", valid: true},
+		{code: "ee"},
+		{code: "E"},
+		{code: "E1"},
+		{code: ""},
+	}
+
+	for _, tt := range cases {
+		t.Run(string(tt.code), func(t *testing.T) {
+			assert.Equal(t, tt.valid, tt.code.Valid())
+			assert.Equal(t, tt.member, tt.code.IsVIESMember())
+		})
+	}
+
+	var result CheckResult
+	assert.NoError(t, json.Unmarshal([]byte(`{"countryCode":"de ","vatNumber":"123"}`), &result))
+	assert.Equal(t, CountryCode("DE"), result.CountryCode)
+
+	b, err := json.Marshal(checkRequest{CountryCode: "DE", VatNumber: "123"})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"countryCode":"DE","vatNumber":"123"}`, string(b))
+}
@@ -0,0 +1,93 @@
+package vies
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var (
+	debugSecretHeaders = regexp.MustCompile(`(?im)^((?:Authorization|Proxy-Authorization|Cookie|Set-Cookie|X-Api-Key):\s*).*$`)
+	debugVatFields     = regexp.MustCompile(`("(?:vatNumber|requesterNumber)"\s*:\s*")([^"]*)(")`)
+	debugCountryFields = regexp.MustCompile(`"(countryCode|requesterMemberStateCode)"\s*:\s*"([A-Za-z]{2})"`)
+	debugTraderFields  = regexp.MustCompile(`("(?:name|address|trader[A-Za-z]*)"\s*:\s*")((?:[^"\\]|\\.)*)(")`)
+)
+
+// debugInterceptor writes every request and response to w as dumped by
+// httputil. Credential headers are always masked, VAT numbers when redact
+// is set, and in privacy mode VAT numbers are pseudonymized and trader
+// names and addresses masked.
+func debugInterceptor(w io.Writer, redact bool) Interceptor {
+	var mu sync.Mutex
+
+	write := func(dump []byte) {
+		dump = debugSecretHeaders.ReplaceAll(dump, []byte("${1}***"))
+		switch {
+		case PrivacyMode():
+			countries := debugCountries(dump)
+			dump = debugVatFields.ReplaceAllFunc(dump, func(m []byte) []byte {
+				parts := debugVatFields.FindSubmatch(m)
+				country := countries["countryCode"]
+				if bytes.Contains(parts[1], []byte("requesterNumber")) {
+					country = countries["requesterMemberStateCode"]
+				}
+				return bytes.Join([][]byte{parts[1], []byte(PseudonymizeVat(country + string(parts[2]))), parts[3]}, nil)
+			})
+			dump = debugTraderFields.ReplaceAll(dump, []byte("${1}***${3}"))
+		case redact:
+			dump = debugVatFields.ReplaceAllFunc(dump, func(m []byte) []byte {
+				parts := debugVatFields.FindSubmatch(m)
+				return bytes.Join([][]byte{parts[1], []byte(maskNumber(string(parts[2]))), parts[3]}, nil)
+			})
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = w.Write(dump)
+		_, _ = w.Write([]byte("\n\n"))
+	}
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+				write(dump)
+			}
+
+			rsp, err := next(req)
+			if err != nil {
+				write([]byte("error: " + err.Error()))
+				return rsp, err
+			}
+
+			if dump, err := httputil.DumpResponse(rsp, true); err == nil {
+				write(dump)
+			}
+			return rsp, nil
+		}
+	}
+}
+
+// maskNumber keeps the last two characters of a VAT number without the
+// country code.
+func maskNumber(number string) string {
+	if len(number) <= 2 {
+		return strings.Repeat("*", len(number))
+	}
+	return strings.Repeat("*", len(number)-2) + number[len(number)-2:]
+}
+
+// debugCountries returns the country codes of dump by field, those of the
+// VAT and requester numbers it holds.
+func debugCountries(dump []byte) map[string]string {
+	countries := make(map[string]string)
+	for _, parts := range debugCountryFields.FindAllSubmatch(dump, -1) {
+		if _, ok := countries[string(parts[1])]; !ok {
+			countries[string(parts[1])] = strings.ToUpper(string(parts[2]))
+		}
+	}
+	return countries
+}
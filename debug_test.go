@@ -0,0 +1,68 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebug(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"countryCode":"EE","vatNumber":"100354546"}`, string(body))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"100354546","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	apiKey := func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Api-Key", "secret")
+			return next(req)
+		}
+	}
+
+	t.Run("redacted", func(t *testing.T) {
+		var out bytes.Buffer
+		v, err := NewClient(&ClientConfig{
+			HttpClient:   client,
+			EndpointUrl:  "https://example.com/",
+			Interceptors: []Interceptor{apiKey},
+			Debug:        &out,
+			RedactVat:    true,
+		})
+		assert.NoError(t, err)
+
+		result, err := v.Check(context.Background(), "EE100354546")
+		assert.NoError(t, err)
+		assert.Equal(t, "100354546", result.VatNumber)
+
+		dump := out.String()
+		assert.Contains(t, dump, "POST /check-vat-number HTTP/1.1")
+		assert.Contains(t, dump, "X-Api-Key: ***")
+		assert.Contains(t, dump, `{"countryCode":"EE","vatNumber":"*******46"}`)
+		assert.Contains(t, dump, "HTTP/1.1 200 OK")
+		assert.Contains(t, dump, `{"countryCode":"EE","vatNumber":"*******46","valid":true}`)
+		assert.NotContains(t, dump, "secret")
+		assert.NotContains(t, dump, "100354546")
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		var out bytes.Buffer
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Debug: &out})
+		assert.NoError(t, err)
+
+		_, err = v.Check(context.Background(), "EE100354546")
+		assert.NoError(t, err)
+		assert.Contains(t, out.String(), `"vatNumber":"100354546"`)
+	})
+}
@@ -0,0 +1,118 @@
+package vies
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// multiDecoder decodes the same document into several values.
+type multiDecoder []any
+
+// lenient is a value of multiDecoder never decoded strictly.
+type lenient struct {
+	v any
+}
+
+// resultDecoder returns the values a response is decoded into, result
+// itself and its Extra and Raw fields when they are enabled.
+func (client *Client) resultDecoder(result any, extra *map[string]json.RawMessage, raw *json.RawMessage) *multiDecoder {
+	out := multiDecoder{result}
+	if client.captureExtra {
+		out = append(out, &extraFields{known: result, into: extra})
+	}
+	if client.keepRaw {
+		out = append(out, raw)
+	}
+	return &out
+}
+
+// decode unmarshals a response body into out as it is read, rejecting
+// unknown fields with StrictDecoding. The body is buffered only when it is
+// decoded into several values.
+func (client *Client) decode(r io.Reader, out any) error {
+
+	switch v := out.(type) {
+	case *multiDecoder:
+		if len(*v) == 1 {
+			return client.decode(r, (*v)[0])
+		}
+		var raw json.RawMessage
+		if err := client.decode(r, lenient{&raw}); err != nil {
+			return err
+		}
+		for _, target := range *v {
+			if err := client.decode(bytes.NewReader(raw), target); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	strict := client.strict
+	if v, ok := out.(lenient); ok {
+		out, strict = v.v, false
+	}
+
+	body := &bodyReader{r: r}
+	dec := json.NewDecoder(body)
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+
+	err := dec.Decode(out)
+	if err == nil {
+		if _, tokenErr := dec.Token(); tokenErr != io.EOF {
+			err = errors.New("data after the JSON document")
+		}
+	}
+	switch {
+	case err == nil:
+		return nil
+	case body.err != nil:
+		return body.err
+	case err == io.EOF:
+		err = io.ErrUnexpectedEOF
+	}
+	if strict {
+		return fmt.Errorf("%w: %w", ErrUnexpectedResponse, err)
+	}
+	return err
+}
+
+// bodyReader keeps the error of the underlying reader, to tell it apart
+// from decoding errors.
+type bodyReader struct {
+	r   io.Reader
+	err error
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err != nil && err != io.EOF {
+		b.err = err
+	}
+	return n, err
+}
+
+// limitReader fails with ErrResponseTooLarge once more than limit bytes
+// are read.
+type limitReader struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, l.limit)
+	}
+	return n, err
+}
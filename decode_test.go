@@ -0,0 +1,97 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrictDecoding(t *testing.T) {
+
+	newClient := func(t *testing.T, body string, opts ...Option) *Client {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(body)),
+				Header:     make(http.Header),
+			}
+		})
+		v, err := New(append([]Option{WithHttpClient(client), WithEndpoint("https://example.com/")}, opts...)...)
+		assert.NoError(t, err)
+		return v
+	}
+
+	tests := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{"matching", `{"countryCode":"DE","vatNumber":"123","valid":true}`, ""},
+		{"unknown field", `{"countryCode":"DE","vatNumber":"123","valid":true,"traderName":"Acme"}`, `unexpected VIES response: json: unknown field "traderName"`},
+		{"wrong type", `{"countryCode":"DE","vatNumber":"123","valid":"true"}`, "unexpected VIES response: json: cannot unmarshal string into Go struct field CheckResult.valid of type bool"},
+		{"trailing data", `{"countryCode":"DE","vatNumber":"123","valid":true} {}`, "unexpected VIES response: data after the JSON document"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newClient(t, tt.body, WithStrictDecoding()).Check(context.Background(), "DE123")
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, ErrUnexpectedResponse))
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+
+	t.Run("lenient by default", func(t *testing.T) {
+		result, err := newClient(t, tests[1].body).Check(context.Background(), "DE123")
+		assert.NoError(t, err)
+		assert.True(t, result.Valid)
+	})
+
+	t.Run("CheckInto checks the custom type", func(t *testing.T) {
+		type result struct {
+			CheckResult
+			TraderName string `json:"traderName"`
+		}
+		v := newClient(t, tests[1].body, WithStrictDecoding())
+		r, err := CheckInto[result](context.Background(), v, "DE123")
+		assert.NoError(t, err)
+		assert.Equal(t, "Acme", r.TraderName)
+
+		_, err = CheckInto[CheckResult](context.Background(), v, "DE123")
+		assert.True(t, errors.Is(err, ErrUnexpectedResponse))
+	})
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestDecode(t *testing.T) {
+
+	v, err := NewClient(&ClientConfig{StrictDecoding: true})
+	assert.NoError(t, err)
+
+	var result CheckResult
+	assert.NoError(t, v.decode(bytes.NewBufferString(`{"valid":true}`+"\n"), &result))
+	assert.True(t, result.Valid)
+
+	err = v.decode(bytes.NewBufferString(``), &result)
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+	assert.True(t, errors.Is(err, ErrUnexpectedResponse))
+
+	assert.EqualError(t, v.decode(failingReader{}, &result), "connection reset")
+
+	limited := &limitReader{r: bytes.NewBufferString(`{"valid":true}`), limit: 5, remaining: 5}
+	err = v.decode(limited, &multiDecoder{&result, &result.Raw})
+	assert.EqualError(t, err, "response too large: more than 5 bytes")
+}
@@ -0,0 +1,95 @@
+package vies
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrDecryption is returned for data a Cipher can't decrypt, because it was
+// altered or encrypted with another key.
+var ErrDecryption = errors.New("decryption failed")
+
+// Cipher encrypts the VAT numbers and results the persistent stores write,
+// with AES-GCM: NewEncryptedFileCache, NewEncryptedFileAuditSink,
+// SQLAuditSinkConfig.Cipher and SQLHistoryConfig.Cipher. The columns and
+// file names the stores look VAT numbers up by hold an HMAC-SHA256 instead.
+type Cipher struct {
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+// NewCipher returns a Cipher with key, of 16, 24 or 32 bytes for AES-128,
+// AES-192 or AES-256. Data encrypted with a key can only be read with the
+// same key.
+func NewCipher(key []byte) (*Cipher, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("vies lookup"))
+	return &Cipher{aead: aead, hashKey: mac.Sum(nil)}, nil
+}
+
+// Encrypt returns plaintext encrypted with a random nonce, which prefixes
+// the result.
+func (c *Cipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt returns the plaintext of data returned by Encrypt.
+func (c *Cipher) Decrypt(data []byte) ([]byte, error) {
+	n := c.aead.NonceSize()
+	if len(data) < n+c.aead.Overhead() {
+		return nil, fmt.Errorf("%w: data too short", ErrDecryption)
+	}
+	plaintext, err := c.aead.Open(nil, data[:n], data[n:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryption, err)
+	}
+	return plaintext, nil
+}
+
+// encryptString returns s encrypted and base64 encoded, for text columns.
+func (c *Cipher) encryptString(s string) (string, error) {
+	data, err := c.Encrypt([]byte(s))
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func (c *Cipher) decryptString(s string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDecryption, err)
+	}
+	plaintext, err := c.Decrypt(data)
+	return string(plaintext), err
+}
+
+// hash returns the keyed hash of s, 32 hexadecimal characters which stand
+// for s in lookups. Unlike a plain hash, it can't be reversed by hashing
+// every possible VAT number without the key.
+func (c *Cipher) hash(s string) string {
+	mac := hmac.New(sha256.New, c.hashKey)
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
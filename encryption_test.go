@@ -0,0 +1,161 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCipher(t *testing.T, key string) *Cipher {
+	c, err := NewCipher([]byte(key))
+	assert.NoError(t, err)
+	return c
+}
+
+func TestCipher(t *testing.T) {
+
+	c := testCipher(t, "0123456789abcdef0123456789abcdef")
+	data, err := c.Encrypt([]byte("DE123456789"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "DE123456789")
+
+	plaintext, err := c.Decrypt(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "DE123456789", string(plaintext))
+
+	// the nonce is random, the hash is not
+	again, err := c.Encrypt([]byte("DE123456789"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, data, again)
+	assert.Equal(t, c.hash("DE123456789"), c.hash("DE123456789"))
+	assert.Len(t, c.hash("DE123456789"), 32)
+
+	other := testCipher(t, "fedcba9876543210fedcba9876543210")
+	_, err = other.Decrypt(data)
+	assert.ErrorIs(t, err, ErrDecryption)
+	assert.NotEqual(t, c.hash("DE123456789"), other.hash("DE123456789"))
+
+	_, err = c.Decrypt([]byte("short"))
+	assert.ErrorIs(t, err, ErrDecryption)
+	_, err = NewCipher([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestEncryptedFileCache(t *testing.T) {
+
+	dir := t.TempDir()
+	c := testCipher(t, "0123456789abcdef")
+	cache, err := NewEncryptedFileCache(dir, time.Hour, c)
+	assert.NoError(t, err)
+
+	cache.Set("DE123456789", &CheckResult{CountryCode: "DE", VatNumber: "123456789", Name: "ACME", Valid: true})
+	result, ok := cache.Get("DE123456789")
+	assert.True(t, ok)
+	assert.Equal(t, "ACME", result.Name)
+
+	files, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 1)
+	assert.Equal(t, c.hash("DE123456789")+fileCacheExtension, files[0].Name())
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "123456789")
+	assert.NotContains(t, string(content), "ACME")
+
+	// another key reads nothing
+	other, err := NewEncryptedFileCache(dir, time.Hour, testCipher(t, "fedcba9876543210"))
+	assert.NoError(t, err)
+	_, ok = other.Get("DE123456789")
+	assert.False(t, ok)
+
+	n, err := cache.Prune(context.Background(), time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	n, err = cache.Erase(context.Background(), "DE123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestEncryptedFileAuditSink(t *testing.T) {
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	c := testCipher(t, "0123456789abcdef")
+	sink, err := NewEncryptedFileAuditSink(path, c)
+	assert.NoError(t, err)
+	defer sink.Close()
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.NoError(t, sink.Record(ctx, &AuditRecord{Time: at, Input: "DE123456789", CountryCode: "DE", VatNumber: "123456789"}))
+	assert.NoError(t, sink.Record(ctx, &AuditRecord{Time: at, Input: "FR1", CountryCode: "FR", VatNumber: "1"}))
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "123456789")
+	assert.Equal(t, 2, bytes.Count(content, []byte("\n")))
+
+	n, err := sink.Erase(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	records, err := ReadAuditRecords(path, c)
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Empty(t, records[0].Input)
+	assert.Equal(t, CountryCode("DE"), records[0].CountryCode)
+	assert.Equal(t, "FR1", records[1].Input)
+
+	_, err = ReadAuditRecords(path, testCipher(t, "fedcba9876543210"))
+	assert.ErrorIs(t, err, ErrDecryption)
+}
+
+func TestEncryptedSQLHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	c := testCipher(t, "0123456789abcdef")
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	result := &CheckResult{CountryCode: "DE", VatNumber: "123456789", Valid: true, Name: "ACME"}
+	content, err := json.Marshal(result)
+	assert.NoError(t, err)
+	encrypted, err := c.encryptString(string(content))
+	assert.NoError(t, err)
+
+	history := NewSQLHistory(db, &SQLHistoryConfig{Cipher: c})
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO vies_history")).
+		WithArgs(c.hash("DE123456789"), "DE", day, true, sqlmock.AnyArg(), "", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vat, checked_at, result FROM vies_history WHERE vat = ?")).
+		WithArgs(c.hash("DE123456789")).
+		WillReturnRows(sqlmock.NewRows([]string{"vat", "checked_at", "result"}).AddRow(c.hash("DE123456789"), day, encrypted))
+
+	assert.NoError(t, history.Add(ctx, day, result))
+	last, err := history.Last(ctx, "de 123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, &HistoryEntry{Vat: "DE123456789", CheckedAt: day, Result: result}, last)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	audit := NewSQLAuditSink(db, &SQLAuditSinkConfig{Cipher: c})
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO vies_audit")).
+		WithArgs(day, sqlmock.AnyArg(), "DE", c.hash("DE123456789"), "", int64(0), false, true, "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE vies_audit SET input = '', vat_number = '', result = '', error = '' WHERE country_code = ? AND vat_number = ?")).
+		WithArgs("DE", c.hash("DE123456789")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, audit.Record(ctx, &AuditRecord{Time: day, Input: "DE123456789", CountryCode: "DE", VatNumber: "123456789", Result: result}))
+	n, err := audit.Erase(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
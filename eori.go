@@ -0,0 +1,142 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	eoriStatusValid = "0"
+
+	eoriRequest = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:ev="http://eori.ws.eos.dds.s/">
+<soap:Body><ev:validateEORI><ev:eori>%s</ev:eori></ev:validateEORI></soap:Body>
+</soap:Envelope>`
+)
+
+// EoriResult is the answer of the EU EORI validation service for a customs
+// registration number.
+type EoriResult struct {
+	Eori        string `json:"eori"`
+	Valid       bool   `json:"valid"`
+	Status      string `json:"status"`
+	Name        string `json:"name"`
+	Address     string `json:"address"`
+	RequestDate string `json:"requestDate"`
+}
+
+type eoriEnvelope struct {
+	Body struct {
+		Fault *struct {
+			Code   string `xml:"faultcode"`
+			String string `xml:"faultstring"`
+		} `xml:"Fault"`
+		Return *struct {
+			RequestDate string `xml:"requestDate"`
+			Results     []struct {
+				Eori        string `xml:"eori"`
+				Status      string `xml:"status"`
+				StatusDescr string `xml:"statusDescr"`
+				Name        string `xml:"name"`
+				Street      string `xml:"street"`
+				PostalCode  string `xml:"postalCode"`
+				City        string `xml:"city"`
+				Country     string `xml:"country"`
+			} `xml:"result"`
+		} `xml:"validateEORIResponse>return"`
+	} `xml:"Body"`
+}
+
+// CheckEori validates an EORI number with the EU EORI validation service.
+// The request goes through the same rate limiter, interceptors, metrics,
+// logs and traces as VIES requests.
+func (client *Client) CheckEori(ctx context.Context, eori string) (*EoriResult, error) {
+
+	eori = strings.ToUpper(strings.ReplaceAll(eori, " ", ""))
+	if err := client.isValidVat(eori); err != nil {
+		return nil, err
+	}
+
+	ctx, span := client.startSpan(ctx, "vies.CheckEori", map[string]any{attributeCountryCode: eori[0:2]})
+	ctx, obs := observe(ctx)
+	start := time.Now()
+	result, err := client.doEori(ctx, eori)
+
+	outcome := outcomeInvalid
+	switch {
+	case err != nil:
+		outcome = outcomeError
+	case result.Valid:
+		outcome = outcomeValid
+	}
+	duration := time.Since(start)
+	client.observeRequest(operationEori, eori[0:2], outcome, obs, duration, err)
+	client.logRequest(ctx, operationEori, eori, outcome, obs, duration, err)
+	client.endSpan(span, obs, err, nil)
+
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (client *Client) doEori(ctx context.Context, eori string) (*EoriResult, error) {
+
+	var body bytes.Buffer
+	if err := xml.EscapeText(&body, []byte(eori)); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.settings.Load().eoriEndpoint.String(), strings.NewReader(fmt.Sprintf(eoriRequest, body.String())))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+
+	rsp, err := client.do(req)
+	if err != nil {
+		return nil, client.correlate(ctx, err)
+	}
+	defer rsp.Body.Close()
+
+	content, err := client.readBody(rsp)
+	if err != nil {
+		return nil, client.correlate(ctx, err)
+	}
+
+	var env eoriEnvelope
+	if err := xml.Unmarshal(content, &env); err != nil {
+		return nil, client.correlate(ctx, fmt.Errorf("unexpected response structure: %w", err))
+	}
+	if fault := env.Body.Fault; fault != nil {
+		return nil, client.correlate(ctx, &ApiError{Err: fault.Code, Message: fault.String})
+	}
+	if env.Body.Return == nil || len(env.Body.Return.Results) == 0 {
+		return nil, client.correlate(ctx, fmt.Errorf("unexpected response structure"))
+	}
+
+	r := env.Body.Return.Results[0]
+	var address []string
+	for _, line := range []string{r.Street, strings.TrimSpace(r.PostalCode + " " + r.City), r.Country} {
+		if line != "" {
+			address = append(address, line)
+		}
+	}
+
+	return &EoriResult{
+		Eori:        r.Eori,
+		Valid:       r.Status == eoriStatusValid,
+		Status:      r.StatusDescr,
+		Name:        r.Name,
+		Address:     strings.Join(address, "\n"),
+		RequestDate: env.Body.Return.RequestDate,
+	}, nil
+}
@@ -0,0 +1,108 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const eoriValidResponse = `<S:Envelope xmlns:S="http://schemas.xmlsoap.org/soap/envelope/"><S:Body>
+<ns0:validateEORIResponse xmlns:ns0="http://eori.ws.eos.dds.s/"><return>
+<requestDate>15/10/2026</requestDate>
+<result><eori>DE123456789012345</eori><status>0</status><statusDescr>Valid</statusDescr><name>ACME GmbH</name><street>Hauptstr. 1</street><postalCode>10115</postalCode><city>Berlin</city><country>DE</country></result>
+</return></ns0:validateEORIResponse>
+</S:Body></S:Envelope>`
+
+const eoriInvalidResponse = `<S:Envelope xmlns:S="http://schemas.xmlsoap.org/soap/envelope/"><S:Body>
+<ns0:validateEORIResponse xmlns:ns0="http://eori.ws.eos.dds.s/"><return>
+<requestDate>15/10/2026</requestDate>
+<result><eori>DE000</eori><status>1</status><statusDescr>Not valid</statusDescr></result>
+</return></ns0:validateEORIResponse>
+</S:Body></S:Envelope>`
+
+const eoriFaultResponse = `<S:Envelope xmlns:S="http://schemas.xmlsoap.org/soap/envelope/"><S:Body>
+<S:Fault><faultcode>S:Server</faultcode><faultstring>Service unavailable</faultstring></S:Fault>
+</S:Body></S:Envelope>`
+
+func TestCheckEori(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		eori     string
+		sent     string
+		code     int
+		response string
+		result   *EoriResult
+		err      string
+	}{
+		{
+			name:     "valid",
+			eori:     "de 123456789012345",
+			sent:     "DE123456789012345",
+			code:     http.StatusOK,
+			response: eoriValidResponse,
+			result: &EoriResult{
+				Eori:        "DE123456789012345",
+				Valid:       true,
+				Status:      "Valid",
+				Name:        "ACME GmbH",
+				Address:     "Hauptstr. 1\n10115 Berlin\nDE",
+				RequestDate: "15/10/2026",
+			},
+		},
+		{
+			name:     "invalid",
+			eori:     "DE000",
+			sent:     "DE000",
+			code:     http.StatusOK,
+			response: eoriInvalidResponse,
+			result:   &EoriResult{Eori: "DE000", Status: "Not valid", RequestDate: "15/10/2026"},
+		},
+		{
+			name:     "fault",
+			eori:     "DE000",
+			sent:     "DE000",
+			code:     http.StatusInternalServerError,
+			response: eoriFaultResponse,
+			err:      "S:Server: Service unavailable",
+		},
+		{
+			name: "input",
+			eori: "D",
+			err:  "invalid VAT provided D",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := &recordingMetrics{}
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				assert.Equal(t, "https://example.com/eori", req.URL.String())
+				body, _ := io.ReadAll(req.Body)
+				assert.Contains(t, string(body), "<ev:eori>"+tt.sent+"</ev:eori>")
+				return &http.Response{
+					StatusCode: tt.code,
+					Body:       io.NopCloser(bytes.NewBufferString(tt.response)),
+					Header:     make(http.Header),
+				}
+			})
+
+			v, err := NewClient(&ClientConfig{HttpClient: client, EoriEndpointUrl: "https://example.com/eori", Metrics: metrics})
+			assert.NoError(t, err)
+
+			result, err := v.CheckEori(context.Background(), tt.eori)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				assert.Len(t, metrics.counters, 1)
+				assert.Contains(t, metrics.counters[0], "operation:eori")
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+			assert.Equal(t, tt.result, result)
+		})
+	}
+}
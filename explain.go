@@ -0,0 +1,241 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/alytsin/go-vies/viescountries"
+)
+
+// Codes of the explanations of errors which are neither VIES fault codes
+// nor InputReason values.
+const (
+	ExplanationCountryNotAllowed  = "country_not_allowed"
+	ExplanationUnexpectedResponse = "unexpected_response"
+	ExplanationCanceled           = "canceled"
+	ExplanationUnknown            = "unknown"
+)
+
+// Explanation is a human-readable explanation of an error of Check, for
+// frontends.
+type Explanation struct {
+	// Code is the VIES fault code, such as MS_UNAVAILABLE, the
+	// InputReason of numbers rejected before calling VIES, or one of the
+	// Explanation constants.
+	Code            string `json:"code"`
+	Title           string `json:"title"`
+	SuggestedAction string `json:"suggestedAction"`
+}
+
+// ExplanationText is the text of an explanation in a language, {country}
+// being replaced by the name of the member state.
+type ExplanationText struct {
+	Title           string
+	SuggestedAction string
+}
+
+// ExplanationCatalog holds the explanations in a language.
+type ExplanationCatalog struct {
+	// Texts are keyed by Explanation.Code, the English ones being used
+	// for missing codes.
+	Texts map[string]ExplanationText
+	// Countries are the names of the member states keyed by VIES code,
+	// and MemberState replaces {country} when the member state is not
+	// known.
+	Countries   map[string]string
+	MemberState string
+}
+
+var (
+	explanationsMu sync.RWMutex
+	explanations   = map[string]*ExplanationCatalog{
+		"en": &englishExplanations,
+		"de": &germanExplanations,
+	}
+)
+
+// RegisterExplanations adds or replaces the explanations of a language,
+// English ("en") and German ("de") being built in. A nil catalog removes
+// the language.
+func RegisterExplanations(lang string, catalog *ExplanationCatalog) {
+	explanationsMu.Lock()
+	defer explanationsMu.Unlock()
+	if catalog == nil {
+		delete(explanations, strings.ToLower(lang))
+		return
+	}
+	explanations[strings.ToLower(lang)] = catalog
+}
+
+// Explain explains an error of Check in English:
+//
+//	_, err := client.Check(ctx, vat)
+//	e := vies.Explain(err)
+//	// MS_UNAVAILABLE: The registry of Germany is temporarily down. Retry later...
+//
+// It returns an empty Explanation for nil errors.
+func Explain(err error) Explanation {
+	return ExplainIn(err, "en")
+}
+
+// ExplainIn explains an error of Check in the language lang, such as "de"
+// or "de-AT", in English when the language has no explanations.
+func ExplainIn(err error, lang string) Explanation {
+
+	if err == nil {
+		return Explanation{}
+	}
+	code, country := explanationCode(err)
+
+	explanationsMu.RLock()
+	catalog := findCatalog(lang)
+	text, ok := catalog.Texts[code]
+	if !ok {
+		catalog = &englishExplanations
+		text = catalog.Texts[code]
+	}
+	explanationsMu.RUnlock()
+
+	name := catalog.Countries[country]
+	if name == "" {
+		name = catalog.MemberState
+	}
+	replacer := strings.NewReplacer("{country}", name)
+	return Explanation{
+		Code:            code,
+		Title:           capitalize(replacer.Replace(text.Title)),
+		SuggestedAction: replacer.Replace(text.SuggestedAction),
+	}
+}
+
+// findCatalog returns the catalog of lang, or of its primary language.
+func findCatalog(lang string) *ExplanationCatalog {
+	lang = strings.ToLower(lang)
+	if catalog, ok := explanations[lang]; ok {
+		return catalog
+	}
+	primary, _, _ := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-")
+	if catalog, ok := explanations[primary]; ok {
+		return catalog
+	}
+	return &englishExplanations
+}
+
+// explanationCode returns the code explaining err and the member state it
+// is about, if known.
+func explanationCode(err error) (string, string) {
+
+	var apiErr *ApiError
+	var inputErr *ErrInvalidInput
+	switch {
+	case errors.As(err, &apiErr):
+		if _, ok := englishExplanations.Texts[apiErr.Err]; ok {
+			return apiErr.Err, apiErr.CountryCode
+		}
+		return ExplanationUnknown, apiErr.CountryCode
+	case errors.As(err, &inputErr):
+		return string(inputErr.Reason), inputErr.CountryCode
+	case errors.Is(err, ErrCountryUnavailable):
+		return "MS_UNAVAILABLE", ""
+	case errors.Is(err, ErrVowUnavailable):
+		return "SERVICE_UNAVAILABLE", ""
+	case errors.Is(err, ErrCountryNotAllowed):
+		return ExplanationCountryNotAllowed, ""
+	case errors.Is(err, ErrUnexpectedResponse), errors.Is(err, ErrResponseTooLarge):
+		return ExplanationUnexpectedResponse, ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return "TIMEOUT", ""
+	case errors.Is(err, context.Canceled):
+		return ExplanationCanceled, ""
+	}
+	return ExplanationUnknown, ""
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+var englishExplanations = ExplanationCatalog{
+	Texts: map[string]ExplanationText{
+		"INVALID_INPUT":                  {"The VAT number is not valid", "Check the number and its country prefix, then try again."},
+		"INVALID_REQUESTER_INFO":         {"The requester VAT number is not valid", "Check the VAT number the checks are made on behalf of."},
+		"SERVICE_UNAVAILABLE":            {"VIES is temporarily unavailable", "Retry later."},
+		"MS_UNAVAILABLE":                 {"The registry of {country} is temporarily down", "Retry later, registries are usually back within a few hours."},
+		"TIMEOUT":                        {"The registry of {country} did not answer in time", "Retry later."},
+		"VAT_BLOCKED":                    {"The VAT number cannot be checked in VIES", "Ask the trader for a proof of registration, or contact the tax authority of {country}."},
+		"IP_BLOCKED":                     {"VIES blocked the requests of this server", "Make fewer checks, and contact the VIES helpdesk if it persists."},
+		"GLOBAL_MAX_CONCURRENT_REQ":      {"VIES is overloaded", "Retry in a few minutes."},
+		"GLOBAL_MAX_CONCURRENT_REQ_TIME": {"VIES is overloaded", "Retry in a few minutes."},
+		"MS_MAX_CONCURRENT_REQ":          {"The registry of {country} is overloaded", "Retry in a few minutes."},
+		"MS_MAX_CONCURRENT_REQ_TIME":     {"The registry of {country} is overloaded", "Retry in a few minutes."},
+
+		string(ReasonEmpty):             {"No VAT number was given", "Enter the VAT number with its country prefix, such as DE123456789."},
+		string(ReasonTooLong):           {"The input is too long to be a VAT number", "Enter a single VAT number."},
+		string(ReasonNewline):           {"The input spans several lines", "Enter a single VAT number on one line."},
+		string(ReasonControlCharacters): {"The input contains invisible characters", "Type the VAT number instead of pasting it."},
+		string(ReasonTooShort):          {"The VAT number is incomplete", "Enter the whole number after the country prefix."},
+		string(ReasonBadCountryCode):    {"The VAT number doesn't start with the code of a member state", "Start the number with its country prefix, such as DE, FR or EL for Greece."},
+		string(ReasonBadCharacters):     {"The VAT number contains characters not used in {country}", "Check the number for typing mistakes."},
+		string(ReasonBadLength):         {"The VAT number has the wrong length for {country}", "Check that no character is missing or added."},
+		string(ReasonBadFormat):         {"The VAT number doesn't have the format used in {country}", "Check the number for typing mistakes."},
+
+		ExplanationCountryNotAllowed:  {"VAT numbers of {country} are not accepted", "Use a VAT number of another member state."},
+		ExplanationUnexpectedResponse: {"VIES returned an unexpected response", "Retry later, and report the problem if it persists."},
+		ExplanationCanceled:           {"The check was canceled", "Try again."},
+		ExplanationUnknown:            {"The VAT number could not be checked", "Retry later, and report the problem if it persists."},
+	},
+	Countries: func() map[string]string {
+		names := make(map[string]string)
+		for _, country := range viescountries.All() {
+			names[country.Code] = country.Name
+		}
+		return names
+	}(),
+	MemberState: "the member state",
+}
+
+var germanExplanations = ExplanationCatalog{
+	Texts: map[string]ExplanationText{
+		"INVALID_INPUT":                  {"Die USt-IdNr. ist ungültig", "Prüfen Sie die Nummer und ihr Länderkennzeichen und versuchen Sie es erneut."},
+		"INVALID_REQUESTER_INFO":         {"Die USt-IdNr. des Anfragenden ist ungültig", "Prüfen Sie die USt-IdNr., in deren Auftrag geprüft wird."},
+		"SERVICE_UNAVAILABLE":            {"VIES ist vorübergehend nicht verfügbar", "Versuchen Sie es später erneut."},
+		"MS_UNAVAILABLE":                 {"Das Register für {country} ist vorübergehend nicht erreichbar", "Versuchen Sie es später erneut, die Register sind meist nach wenigen Stunden wieder erreichbar."},
+		"TIMEOUT":                        {"Das Register für {country} hat nicht rechtzeitig geantwortet", "Versuchen Sie es später erneut."},
+		"VAT_BLOCKED":                    {"Die USt-IdNr. kann in VIES nicht geprüft werden", "Bitten Sie den Unternehmer um einen Nachweis der Registrierung oder wenden Sie sich an die Steuerbehörde für {country}."},
+		"IP_BLOCKED":                     {"VIES hat die Anfragen dieses Servers gesperrt", "Prüfen Sie seltener und wenden Sie sich an den VIES-Helpdesk, falls es anhält."},
+		"GLOBAL_MAX_CONCURRENT_REQ":      {"VIES ist überlastet", "Versuchen Sie es in einigen Minuten erneut."},
+		"GLOBAL_MAX_CONCURRENT_REQ_TIME": {"VIES ist überlastet", "Versuchen Sie es in einigen Minuten erneut."},
+		"MS_MAX_CONCURRENT_REQ":          {"Das Register für {country} ist überlastet", "Versuchen Sie es in einigen Minuten erneut."},
+		"MS_MAX_CONCURRENT_REQ_TIME":     {"Das Register für {country} ist überlastet", "Versuchen Sie es in einigen Minuten erneut."},
+
+		string(ReasonEmpty):             {"Es wurde keine USt-IdNr. angegeben", "Geben Sie die USt-IdNr. mit Länderkennzeichen ein, z. B. DE123456789."},
+		string(ReasonTooLong):           {"Die Eingabe ist zu lang für eine USt-IdNr.", "Geben Sie eine einzelne USt-IdNr. ein."},
+		string(ReasonNewline):           {"Die Eingabe umfasst mehrere Zeilen", "Geben Sie eine einzelne USt-IdNr. in einer Zeile ein."},
+		string(ReasonControlCharacters): {"Die Eingabe enthält unsichtbare Zeichen", "Tippen Sie die USt-IdNr. ein, statt sie einzufügen."},
+		string(ReasonTooShort):          {"Die USt-IdNr. ist unvollständig", "Geben Sie die vollständige Nummer nach dem Länderkennzeichen ein."},
+		string(ReasonBadCountryCode):    {"Die USt-IdNr. beginnt nicht mit dem Kennzeichen eines Mitgliedstaats", "Beginnen Sie die Nummer mit dem Länderkennzeichen, z. B. DE, FR oder EL für Griechenland."},
+		string(ReasonBadCharacters):     {"Die USt-IdNr. enthält Zeichen, die für {country} nicht vorkommen", "Prüfen Sie die Nummer auf Tippfehler."},
+		string(ReasonBadLength):         {"Die USt-IdNr. hat nicht die Länge der Nummern für {country}", "Prüfen Sie, ob ein Zeichen fehlt oder zu viel ist."},
+		string(ReasonBadFormat):         {"Die USt-IdNr. hat nicht das Format der Nummern für {country}", "Prüfen Sie die Nummer auf Tippfehler."},
+
+		ExplanationCountryNotAllowed:  {"USt-IdNrn. für {country} werden nicht akzeptiert", "Verwenden Sie eine USt-IdNr. eines anderen Mitgliedstaats."},
+		ExplanationUnexpectedResponse: {"VIES hat eine unerwartete Antwort geliefert", "Versuchen Sie es später erneut und melden Sie das Problem, falls es anhält."},
+		ExplanationCanceled:           {"Die Prüfung wurde abgebrochen", "Versuchen Sie es erneut."},
+		ExplanationUnknown:            {"Die USt-IdNr. konnte nicht geprüft werden", "Versuchen Sie es später erneut und melden Sie das Problem, falls es anhält."},
+	},
+	Countries: map[string]string{
+		"AT": "Österreich", "BE": "Belgien", "BG": "Bulgarien", "CY": "Zypern",
+		"CZ": "Tschechien", "DE": "Deutschland", "DK": "Dänemark", "EE": "Estland",
+		"EL": "Griechenland", "ES": "Spanien", "FI": "Finnland", "FR": "Frankreich",
+		"HR": "Kroatien", "HU": "Ungarn", "IE": "Irland", "IT": "Italien",
+		"LT": "Litauen", "LU": "Luxemburg", "LV": "Lettland", "MT": "Malta",
+		"NL": "Niederlande", "PL": "Polen", "PT": "Portugal", "RO": "Rumänien",
+		"SE": "Schweden", "SI": "Slowenien", "SK": "Slowakei", "XI": "Nordirland",
+	},
+	MemberState: "den Mitgliedstaat",
+}
@@ -0,0 +1,67 @@
+package vies
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+
+	client, err := New(
+		WithPreflightFormat(),
+		WithFakeErrors(map[string]error{
+			"DE": &ApiError{Err: "MS_UNAVAILABLE"},
+			"FR": &ApiError{Err: "MS_UNAVAILABLE"},
+			"IT": &ApiError{Err: "SOMETHING_NEW"},
+		}),
+	)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.Check(ctx, "DE123456789")
+	assert.Equal(t, Explanation{
+		Code:            "MS_UNAVAILABLE",
+		Title:           "The registry of Germany is temporarily down",
+		SuggestedAction: "Retry later, registries are usually back within a few hours.",
+	}, Explain(err))
+	assert.Equal(t, "Das Register für Deutschland ist vorübergehend nicht erreichbar", ExplainIn(err, "de-AT").Title)
+	assert.Equal(t, "The registry of Germany is temporarily down", ExplainIn(err, "xx").Title)
+
+	// the configured error is shared by the member states
+	_, err = client.Check(ctx, "FR12345678901")
+	assert.Equal(t, "The registry of France is temporarily down", Explain(err).Title)
+
+	_, err = client.Check(ctx, "IT12345678901")
+	assert.Equal(t, ExplanationUnknown, Explain(err).Code)
+
+	_, err = client.Check(ctx, "NL123")
+	e := Explain(err)
+	assert.Equal(t, string(ReasonBadLength), e.Code)
+	assert.Equal(t, "The VAT number has the wrong length for Netherlands", e.Title)
+
+	_, err = client.Check(ctx, "")
+	assert.Equal(t, string(ReasonEmpty), Explain(err).Code)
+
+	err = fmt.Errorf("%w %s: %s", ErrCountryUnavailable, "PL", AvailabilityUnavailable)
+	assert.Equal(t, "The registry of the member state is temporarily down", Explain(err).Title)
+	assert.Equal(t, "Das Register für den Mitgliedstaat ist vorübergehend nicht erreichbar", ExplainIn(err, "de").Title)
+	assert.Equal(t, "TIMEOUT", Explain(context.DeadlineExceeded).Code)
+	assert.Equal(t, Explanation{}, Explain(nil))
+
+	RegisterExplanations("fr", &ExplanationCatalog{
+		Texts:       map[string]ExplanationText{"MS_UNAVAILABLE": {"le registre de {country} est indisponible", "Réessayez plus tard."}},
+		Countries:   map[string]string{"DE": "l'Allemagne"},
+		MemberState: "l'État membre",
+	})
+	_, err = client.Check(ctx, "DE123456789")
+	assert.Equal(t, "Le registre de l'Allemagne est indisponible", ExplainIn(err, "fr").Title)
+	_, err = client.Check(ctx, "")
+	assert.Equal(t, "No VAT number was given", ExplainIn(err, "fr").Title)
+
+	RegisterExplanations("fr", nil)
+	_, err = client.Check(ctx, "DE123456789")
+	assert.Equal(t, "The registry of Germany is temporarily down", ExplainIn(err, "fr").Title)
+}
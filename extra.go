@@ -0,0 +1,60 @@
+package vies
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// extraFields collects the members of a JSON object which don't match a
+// field of known, matching case-insensitively like encoding/json.
+type extraFields struct {
+	known any
+	into  *map[string]json.RawMessage
+}
+
+func (e *extraFields) UnmarshalJSON(data []byte) error {
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	names := jsonFieldNames(reflect.TypeOf(e.known))
+	for name, value := range members {
+		if names[strings.ToLower(name)] {
+			continue
+		}
+		if *e.into == nil {
+			*e.into = make(map[string]json.RawMessage)
+		}
+		(*e.into)[name] = value
+	}
+	return nil
+}
+
+// jsonFieldNames returns the lower-cased JSON names of the fields of a
+// struct.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	names := make(map[string]bool, t.NumField())
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
@@ -0,0 +1,116 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureExtraFields(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body := `{"countryCode":"DE","VatNumber":"123","valid":true,"traderName":"Acme","traderNameMatch":"VALID"}`
+		if req.URL.Path == "/check-status" {
+			body = `{"vow":{"available":true},"countries":[],"maintenance":{"from":"22:00"}}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := New(WithHttpClient(client), WithEndpoint("https://example.com/"), WithExtraFields())
+	assert.NoError(t, err)
+
+	result, err := v.Check(context.Background(), "DE123")
+	assert.NoError(t, err)
+	assert.Equal(t, "123", result.VatNumber)
+	assert.Equal(t, map[string]json.RawMessage{
+		"traderName":      json.RawMessage(`"Acme"`),
+		"traderNameMatch": json.RawMessage(`"VALID"`),
+	}, result.Extra)
+
+	status, err := v.Status(context.Background())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"from":"22:00"}`, string(status.Extra["maintenance"]))
+
+	v, err = New(WithHttpClient(client), WithEndpoint("https://example.com/"))
+	assert.NoError(t, err)
+	result, err = v.Check(context.Background(), "DE123")
+	assert.NoError(t, err)
+	assert.Nil(t, result.Extra)
+}
+
+func TestKeepRawResponse(t *testing.T) {
+
+	body := `{"countryCode":"DE", "vatNumber":"123", "valid":true, "traderName":"Acme"}`
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	cache := NewMemoryCache(time.Minute)
+	v, err := New(WithHttpClient(client), WithEndpoint("https://example.com/"), WithRawResponse(), WithCache(cache))
+	assert.NoError(t, err)
+
+	result, err := v.Check(context.Background(), "DE123")
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(result.Raw))
+
+	cached, ok := cache.Get("DE123")
+	assert.True(t, ok)
+	assert.Equal(t, body, string(cached.Raw))
+
+	out, err := json.Marshal(result)
+	assert.NoError(t, err)
+	assert.Contains(t, string(out), `"raw":{"countryCode":"DE","vatNumber":"123","valid":true,"traderName":"Acme"}`)
+}
+
+func TestResponseHeaders(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		header := make(http.Header)
+		header.Set("X-Request-Id", "abc")
+		header.Set("Date", "Thu, 15 Oct 2026 10:00:00 GMT")
+		header.Set("Server", "vies")
+		body := `{"countryCode":"DE","vatNumber":"123","valid":true}`
+		if req.URL.Path == "/check-status" {
+			body = `{"vow":{"available":true}}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     header,
+		}
+	})
+
+	v, err := New(WithHttpClient(client), WithEndpoint("https://example.com/"), WithResponseHeaders("x-request-id", "Date", "X-Missing"))
+	assert.NoError(t, err)
+
+	result, err := v.Check(context.Background(), "DE123")
+	assert.NoError(t, err)
+	assert.Equal(t, http.Header{
+		"X-Request-Id": {"abc"},
+		"Date":         {"Thu, 15 Oct 2026 10:00:00 GMT"},
+	}, result.ResponseHeaders)
+
+	status, err := v.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", status.ResponseHeaders.Get("X-Request-Id"))
+
+	v, err = New(WithHttpClient(client), WithEndpoint("https://example.com/"))
+	assert.NoError(t, err)
+	result, err = v.Check(context.Background(), "DE123")
+	assert.NoError(t, err)
+	assert.Nil(t, result.ResponseHeaders)
+}
@@ -0,0 +1,94 @@
+package vies
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// fakeResponder answers Check and Status in place of VIES, see
+// ClientConfig.FakeResponses.
+type fakeResponder struct {
+	results map[string]CheckResult
+	errors  map[string]error
+}
+
+func newFakeResponder(results map[string]CheckResult, errors map[string]error) *fakeResponder {
+	if results == nil && errors == nil {
+		return nil
+	}
+	f := &fakeResponder{
+		results: make(map[string]CheckResult, len(results)),
+		errors:  make(map[string]error, len(errors)),
+	}
+	for vat, result := range results {
+		f.results[vatKey(vat)] = result
+	}
+	for key, err := range errors {
+		f.errors[vatKey(key)] = err
+	}
+	return f
+}
+
+// check answers req with the configured result or error, unknown numbers
+// being invalid. The result is also decoded into into when not nil.
+func (f *fakeResponder) check(req *checkRequest, status *CheckResult, into any, now time.Time) error {
+
+	key := string(req.CountryCode) + strings.ToUpper(req.VatNumber)
+	if err, ok := f.errors[key]; ok {
+		return copyError(err)
+	}
+	if err, ok := f.errors[string(req.CountryCode)]; ok {
+		return copyError(err)
+	}
+
+	result, ok := f.results[key]
+	if !ok {
+		result = CheckResult{Name: "---", Address: "---"}
+	}
+	*status = result
+	if status.CountryCode == "" {
+		status.CountryCode = req.CountryCode
+	}
+	if status.VatNumber == "" {
+		status.VatNumber = req.VatNumber
+	}
+	if status.RequestDate == "" {
+		status.RequestDate = now.Format("2006-01-02-07:00")
+	}
+
+	if into != nil {
+		content, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(content, into)
+	}
+	return nil
+}
+
+// copyError copies the configured *ApiError errors, which the client
+// completes with the member state.
+func copyError(err error) error {
+	if apiErr, ok := err.(*ApiError); ok {
+		copied := *apiErr
+		return &copied
+	}
+	return err
+}
+
+// status reports every member state available but those whose code has
+// an error.
+func (f *fakeResponder) status() *Status {
+	status := &Status{Vow: StatusVow{Available: true}}
+	for _, code := range slices.Sorted(maps.Keys(vatFormats)) {
+		availability := AvailabilityAvailable
+		if _, ok := f.errors[code]; ok {
+			availability = AvailabilityUnavailable
+		}
+		status.Countries = append(status.Countries, CountryStatus{CountryCode: CountryCode(code), Availability: availability})
+	}
+	return status
+}
@@ -0,0 +1,71 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeResponses(t *testing.T) {
+
+	client, err := New(
+		WithHttpClient(NewTestClient(func(req *http.Request) *http.Response {
+			t.Fatal("no request expected")
+			return nil
+		})),
+		WithRequester("EE100354546"),
+		WithFakeResponses(map[string]CheckResult{
+			"de 123 456 789": {Valid: true, Name: "ACME GmbH", Address: "Berlin", RequestIdentifier: "WAPIDEMO00000001"},
+		}),
+		WithFakeErrors(map[string]error{
+			"FR12345678901": &ApiError{Err: "VAT_BLOCKED"},
+			"IT":            &ApiError{Err: "MS_UNAVAILABLE"},
+		}),
+	)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	result, err := client.Check(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "ACME GmbH", result.Name)
+	assert.Equal(t, "DE123456789", result.Vat)
+	assert.Equal(t, CountryCode("DE"), result.CountryCode)
+	assert.Equal(t, "WAPIDEMO00000001", result.RequestIdentifier)
+	assert.NotEmpty(t, result.RequestDate)
+
+	result, err = client.Check(ctx, "NL123456789B01")
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "---", result.Name)
+
+	_, err = client.Check(ctx, "FR12345678901")
+	var apiErr *ApiError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "VAT_BLOCKED", apiErr.Err)
+	_, err = client.Check(ctx, "IT12345678901")
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "MS_UNAVAILABLE", apiErr.Err)
+
+	status, err := client.Status(ctx)
+	assert.NoError(t, err)
+	assert.True(t, status.Vow.Available)
+	assert.Len(t, status.Countries, 28)
+	for _, country := range status.Countries {
+		if country.CountryCode == "IT" {
+			assert.Equal(t, AvailabilityUnavailable, country.Availability)
+		} else {
+			assert.Equal(t, AvailabilityAvailable, country.Availability)
+		}
+	}
+
+	// clones answer the same
+	clone, err := client.Clone(WithUserAgent("demo"))
+	assert.NoError(t, err)
+	valid, err := clone.Valid(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
@@ -0,0 +1,168 @@
+package vies
+
+import (
+	"regexp"
+	"strings"
+)
+
+// vatFormats are the patterns of the number part of the VAT numbers of
+// each member state, without the country prefix.
+var vatFormats = map[string]*regexp.Regexp{
+	"AT": regexp.MustCompile(`^U\d{8}$`),
+	"BE": regexp.MustCompile(`^[01]\d{9}$`),
+	"BG": regexp.MustCompile(`^\d{9,10}$`),
+	"CY": regexp.MustCompile(`^\d{8}[A-Z]$`),
+	"CZ": regexp.MustCompile(`^\d{8,10}$`),
+	"DE": regexp.MustCompile(`^\d{9}$`),
+	"DK": regexp.MustCompile(`^\d{8}$`),
+	"EE": regexp.MustCompile(`^\d{9}$`),
+	"EL": regexp.MustCompile(`^\d{9}$`),
+	"ES": regexp.MustCompile(`^[A-Z0-9]\d{7}[A-Z0-9]$`),
+	"FI": regexp.MustCompile(`^\d{8}$`),
+	"FR": regexp.MustCompile(`^[A-HJ-NP-Z0-9]{2}\d{9}$`),
+	"HR": regexp.MustCompile(`^\d{11}$`),
+	"HU": regexp.MustCompile(`^\d{8}$`),
+	"IE": regexp.MustCompile(`^(\d{7}[A-W][A-IW]?|\d[A-Z+*]\d{5}[A-W])$`),
+	"IT": regexp.MustCompile(`^\d{11}$`),
+	"LT": regexp.MustCompile(`^(\d{9}|\d{12})$`),
+	"LU": regexp.MustCompile(`^\d{8}$`),
+	"LV": regexp.MustCompile(`^\d{11}$`),
+	"MT": regexp.MustCompile(`^\d{8}$`),
+	"NL": regexp.MustCompile(`^\d{9}B\d{2}$`),
+	"PL": regexp.MustCompile(`^\d{10}$`),
+	"PT": regexp.MustCompile(`^\d{9}$`),
+	"RO": regexp.MustCompile(`^[1-9]\d{1,9}$`),
+	"SE": regexp.MustCompile(`^\d{10}01$`),
+	"SI": regexp.MustCompile(`^\d{8}$`),
+	"SK": regexp.MustCompile(`^\d{10}$`),
+	"XI": regexp.MustCompile(`^(\d{9}|\d{12}|GD[0-4]\d{2}|HA[5-9]\d{2})$`),
+}
+
+// numberRule is the length range and character set of the number part of
+// the VAT numbers of a member state, a looser check than vatFormats telling
+// what is wrong with a number.
+type numberRule struct {
+	min, max int
+	// letters tells whether the number may contain letters, besides
+	// digits.
+	letters bool
+}
+
+var numberRules = map[string]numberRule{
+	"AT": {9, 9, true},
+	"BE": {10, 10, false},
+	"BG": {9, 10, false},
+	"CY": {9, 9, true},
+	"CZ": {8, 10, false},
+	"DE": {9, 9, false},
+	"DK": {8, 8, false},
+	"EE": {9, 9, false},
+	"EL": {9, 9, false},
+	"ES": {9, 9, true},
+	"FI": {8, 8, false},
+	"FR": {11, 11, true},
+	"HR": {11, 11, false},
+	"HU": {8, 8, false},
+	"IE": {8, 9, true},
+	"IT": {11, 11, false},
+	"LT": {9, 12, false},
+	"LU": {8, 8, false},
+	"LV": {11, 11, false},
+	"MT": {8, 8, false},
+	"NL": {12, 12, true},
+	"PL": {10, 10, false},
+	"PT": {9, 9, false},
+	"RO": {2, 10, false},
+	"SE": {12, 12, false},
+	"SI": {8, 8, false},
+	"SK": {10, 10, false},
+	"XI": {5, 12, true},
+}
+
+// checkNumber verifies the length and characters of the number part of a
+// VAT number of the member state countryCode, the input being reported
+// in errors. It returns nil when the number passes.
+func checkNumber(input, countryCode, number string) *ErrInvalidInput {
+
+	rule, ok := numberRules[countryCode]
+	if !ok {
+		return &ErrInvalidInput{Input: input, Reason: ReasonBadCountryCode}
+	}
+	e := &ErrInvalidInput{Input: input, CountryCode: countryCode}
+	charset := isDigits
+	if rule.letters {
+		charset = func(s string) bool {
+			// Irish numbers may contain + and *
+			return isAlphanumeric(strings.NewReplacer("+", "", "*", "").Replace(s))
+		}
+	}
+	switch {
+	case !charset(number):
+		e.Reason = ReasonBadCharacters
+	case len(number) < rule.min || len(number) > rule.max:
+		e.Reason = ReasonBadLength
+	default:
+		return nil
+	}
+	return e
+}
+
+// VatNumber is a VAT number with its country prefix, such as EE100354546.
+type VatNumber string
+
+// ParseVatNumber sanitizes a VAT number with SanitizeVat, removes its
+// spaces, dots and dashes and checks its format offline, without any
+// request to VIES.
+func ParseVatNumber(s string) (VatNumber, error) {
+	sanitized, err := SanitizeVat(s)
+	if err != nil {
+		return "", err
+	}
+	v := VatNumber(normalizeVat(sanitized))
+	if !v.Valid() {
+		return "", invalidInput(s)
+	}
+	return v, nil
+}
+
+// normalizeVat upper-cases a VAT number and removes its spaces, dots and
+// dashes.
+func normalizeVat(s string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", ".", "", "-", "").Replace(s))
+}
+
+// ValidFormat reports whether a VAT number has the format used by its
+// member state.
+func ValidFormat(vat string) bool {
+	_, err := ParseVatNumber(vat)
+	return err == nil
+}
+
+// Valid reports whether the number matches the format of its member state.
+func (v VatNumber) Valid() bool {
+	if len(v) < 3 {
+		return false
+	}
+	format, ok := vatFormats[v.CountryCode()]
+	return ok && format.MatchString(v.Number())
+}
+
+// CountryCode returns the VIES country prefix.
+func (v VatNumber) CountryCode() string {
+	if len(v) < 2 {
+		return ""
+	}
+	return string(v[0:2])
+}
+
+// Number returns the number without the country prefix.
+func (v VatNumber) Number() string {
+	if len(v) < 2 {
+		return ""
+	}
+	return string(v[2:])
+}
+
+func (v VatNumber) String() string {
+	return string(v)
+}
@@ -0,0 +1,53 @@
+package vies
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alytsin/go-vies/viescountries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVatNumber(t *testing.T) {
+
+	cases := []struct {
+		input string
+		vat   VatNumber
+		err   error
+	}{
+		{input: "EE100354546", vat: "EE100354546"},
+		{input: "ee 100 354 546", vat: "EE100354546"},
+		{input: "ATU12345678", vat: "ATU12345678"},
+		{input: "NL123456789B01", vat: "NL123456789B01"},
+		{input: "FR-40.303.265.045", vat: "FR40303265045"},
+		{input: "IE1234567FA", vat: "IE1234567FA"},
+		{input: "XIGD123", vat: "XIGD123"},
+		{input: "AT12345678", err: ErrInvalidVat},
+		{input: "DE12345678", err: ErrInvalidVat},
+		{input: "GB123456789", err: ErrInvalidVat},
+		{input: "EE", err: ErrInvalidVat},
+		{input: "", err: ErrInvalidVat},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			vat, err := ParseVatNumber(tt.input)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Equal(t, tt.vat, vat)
+			assert.Equal(t, tt.err == nil, ValidFormat(tt.input))
+		})
+	}
+}
+
+func TestVatNumberParts(t *testing.T) {
+	vat := VatNumber("EE100354546")
+	assert.Equal(t, "EE", vat.CountryCode())
+	assert.Equal(t, "100354546", vat.Number())
+	assert.Equal(t, "", VatNumber("E").Number())
+}
+
+func TestVatFormatsCoverMemberStates(t *testing.T) {
+	for _, code := range viescountries.Codes() {
+		assert.Contains(t, vatFormats, code)
+	}
+}
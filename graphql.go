@@ -0,0 +1,36 @@
+package vies
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// UnmarshalGQL lets VatNumber be bound to a custom scalar in gqlgen
+// schemas, rejecting numbers with an invalid format at the API boundary:
+//
+//	# schema.graphql
+//	scalar VatNumber
+//
+//	# gqlgen.yml
+//	models:
+//	  VatNumber:
+//	    model: github.com/alytsin/go-vies.VatNumber
+func (v *VatNumber) UnmarshalGQL(input any) error {
+	s, ok := input.(string)
+	if !ok {
+		return fmt.Errorf("%w: VAT number must be a string, got %T", ErrInvalidVat, input)
+	}
+	vat, err := ParseVatNumber(s)
+	if err != nil {
+		return err
+	}
+	*v = vat
+	return nil
+}
+
+// MarshalGQL writes the number as a GraphQL string.
+func (v VatNumber) MarshalGQL(w io.Writer) {
+	content, _ := json.Marshal(string(v))
+	_, _ = w.Write(content)
+}
@@ -0,0 +1,38 @@
+package vies
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVatNumberUnmarshalGQL(t *testing.T) {
+
+	cases := []struct {
+		name  string
+		input any
+		vat   VatNumber
+		err   error
+	}{
+		{name: "valid", input: "ee 100354546", vat: "EE100354546"},
+		{name: "bad format", input: "EE1", err: ErrInvalidVat},
+		{name: "not a string", input: 100354546, err: ErrInvalidVat},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var vat VatNumber
+			err := vat.UnmarshalGQL(tt.input)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Equal(t, tt.vat, vat)
+		})
+	}
+}
+
+func TestVatNumberMarshalGQL(t *testing.T) {
+	var buf bytes.Buffer
+	VatNumber("EE100354546").MarshalGQL(&buf)
+	assert.Equal(t, `"EE100354546"`, buf.String())
+}
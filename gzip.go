@@ -0,0 +1,55 @@
+package vies
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptGzip asks for a gzip compressed response unless the request already
+// names its encodings. The transport only decompresses the responses to the
+// requests it added the header to, so decompress handles them instead,
+// whatever the transport.
+func acceptGzip(req *http.Request) {
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+}
+
+// decompress replaces the body of a gzip encoded response with its
+// decompressed content, as http.Transport does.
+func decompress(rsp *http.Response) {
+	if !strings.EqualFold(rsp.Header.Get("Content-Encoding"), "gzip") {
+		return
+	}
+	rsp.Body = &gzipBody{body: rsp.Body}
+	rsp.Header.Del("Content-Encoding")
+	rsp.Header.Del("Content-Length")
+	rsp.ContentLength = -1
+	rsp.Uncompressed = true
+}
+
+// gzipBody reads the gzip header on the first Read, so that a malformed
+// header surfaces as a read error of the body.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.zr == nil {
+		if b.zr, b.err = gzip.NewReader(b.body); b.err != nil {
+			return 0, b.err
+		}
+	}
+	return b.zr.Read(p)
+}
+
+func (b *gzipBody) Close() error {
+	return b.body.Close()
+}
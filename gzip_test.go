@@ -0,0 +1,69 @@
+package vies
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(s))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestGzip(t *testing.T) {
+
+	body := gzipped(t, `{"countryCode":"EE","vatNumber":"123","valid":true}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	for _, transport := range []*http.Transport{
+		{},
+		{DisableCompression: true},
+	} {
+		v, err := New(WithEndpoint(server.URL), WithHttpClient(&http.Client{Transport: transport}))
+		assert.NoError(t, err)
+
+		result, err := v.Check(context.Background(), "EE123", SkipCache())
+		assert.NoError(t, err)
+		assert.True(t, result.Valid)
+	}
+}
+
+func TestGzipErrors(t *testing.T) {
+
+	var body []byte
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Header:     http.Header{"Content-Encoding": {"gzip"}},
+		}
+	})
+	v, err := NewClient(&ClientConfig{HttpClient: client, MaxResponseSize: 100})
+	assert.NoError(t, err)
+
+	body = []byte(`{"valid":true}`)
+	_, err = v.Check(context.Background(), "EE123")
+	assert.ErrorIs(t, err, gzip.ErrHeader)
+
+	// The limit applies to the decompressed body.
+	body = gzipped(t, `{"countryCode":"EE","vatNumber":"123","name":"`+string(bytes.Repeat([]byte("x"), 200))+`"}`)
+	assert.Less(t, len(body), 100)
+	_, err = v.Check(context.Background(), "EE123")
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+}
@@ -0,0 +1,244 @@
+package vies
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrNoHistory is returned by HistoryInterface.Last for VAT numbers never
+// checked.
+var ErrNoHistory = errors.New("no check in history")
+
+// HistoryEntry is a check kept in a history store.
+type HistoryEntry struct {
+	Vat       string       `json:"vat"`
+	CheckedAt time.Time    `json:"checkedAt"`
+	Result    *CheckResult `json:"result"`
+}
+
+// HistoryInterface keeps the results of past checks for queries, see
+// SQLHistory. VAT numbers are compared once normalized, "de 123" being
+// "DE123".
+type HistoryInterface interface {
+	// Add keeps result, checked at checkedAt.
+	Add(ctx context.Context, checkedAt time.Time, result *CheckResult) error
+	// Last returns the latest check of vat, or ErrNoHistory.
+	Last(ctx context.Context, vat string) (*HistoryEntry, error)
+	// Between returns the checks made from from until to, excluded, in
+	// the order they were made.
+	Between(ctx context.Context, from, to time.Time) ([]HistoryEntry, error)
+	// Flipped returns the VAT numbers which became valid or invalid from
+	// from until to, excluded, compared with their check before.
+	Flipped(ctx context.Context, from, to time.Time) ([]ValidityChange, error)
+}
+
+const defaultHistoryTable = "vies_history"
+
+type SQLHistoryConfig struct {
+	// Table holding the checks, defaults to vies_history.
+	Table string
+	// Placeholder returns the placeholder of the n-th query argument,
+	// starting at 1. Defaults to "?", use DollarPlaceholder for PostgreSQL.
+	Placeholder func(n int) string
+	// Cipher, when set, encrypts the name and result, and replaces the
+	// VAT number with its keyed hash, see Cipher.
+	Cipher *Cipher
+}
+
+// SQLHistory keeps checks in a database table, see CreateTable for its
+// schema. It is also an AuditSinkInterface which adds the results of
+// ClientConfig.Audit that didn't come from the cache.
+type SQLHistory struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+	cipher      *Cipher
+}
+
+func NewSQLHistory(db *sql.DB, config *SQLHistoryConfig) *SQLHistory {
+
+	table := defaultHistoryTable
+	placeholder := func(int) string { return "?" }
+	var cipher *Cipher
+
+	if config != nil {
+		if config.Table != "" {
+			table = config.Table
+		}
+		if config.Placeholder != nil {
+			placeholder = config.Placeholder
+		}
+		cipher = config.Cipher
+	}
+
+	return &SQLHistory{
+		db:          db,
+		table:       table,
+		placeholder: placeholder,
+		cipher:      cipher,
+	}
+}
+
+// CreateTable creates the history table and its index unless they exist.
+func (h *SQLHistory) CreateTable(ctx context.Context) error {
+	_, err := h.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+h.table+` (
+	vat VARCHAR(32) NOT NULL,
+	country_code CHAR(2) NOT NULL,
+	checked_at TIMESTAMP NOT NULL,
+	valid BOOLEAN NOT NULL,
+	name TEXT NOT NULL,
+	request_identifier VARCHAR(64) NOT NULL,
+	result TEXT NOT NULL
+)`)
+	if err != nil {
+		return err
+	}
+	_, err = h.db.ExecContext(ctx, "CREATE INDEX IF NOT EXISTS "+h.table+"_vat ON "+h.table+" (vat, checked_at)")
+	return err
+}
+
+func (h *SQLHistory) Add(ctx context.Context, checkedAt time.Time, result *CheckResult) error {
+
+	content, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	name, stored := result.Name, string(content)
+	if h.cipher != nil {
+		if name, err = h.cipher.encryptString(name); err != nil {
+			return err
+		}
+		if stored, err = h.cipher.encryptString(stored); err != nil {
+			return err
+		}
+	}
+
+	_, err = h.db.ExecContext(ctx,
+		"INSERT INTO "+h.table+" (vat, country_code, checked_at, valid, name, request_identifier, result) VALUES ("+h.placeholders(7)+")",
+		h.key(string(result.CountryCode)+result.VatNumber), string(result.CountryCode), checkedAt.UTC(),
+		result.Valid, name, result.RequestIdentifier, stored,
+	)
+	return err
+}
+
+// Record adds the result of record unless it came from the cache.
+func (h *SQLHistory) Record(ctx context.Context, record *AuditRecord) error {
+	if record.Result == nil || record.Cached {
+		return nil
+	}
+	return h.Add(ctx, record.Time, record.Result)
+}
+
+func (h *SQLHistory) Last(ctx context.Context, vat string) (*HistoryEntry, error) {
+
+	entries, err := h.query(ctx, " WHERE vat = "+h.placeholder(1)+" ORDER BY checked_at DESC LIMIT 1", h.key(vat))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrNoHistory
+	}
+	return &entries[0], nil
+}
+
+func (h *SQLHistory) Between(ctx context.Context, from, to time.Time) ([]HistoryEntry, error) {
+	return h.query(ctx, " WHERE checked_at >= "+h.placeholder(1)+" AND checked_at < "+h.placeholder(2)+" ORDER BY checked_at", from.UTC(), to.UTC())
+}
+
+func (h *SQLHistory) Flipped(ctx context.Context, from, to time.Time) ([]ValidityChange, error) {
+
+	// the checks of the numbers checked in the period, with those before
+	entries, err := h.query(ctx,
+		" WHERE vat IN (SELECT vat FROM "+h.table+" WHERE checked_at >= "+h.placeholder(1)+" AND checked_at < "+h.placeholder(2)+")"+
+			" AND checked_at < "+h.placeholder(3)+" ORDER BY vat, checked_at",
+		from.UTC(), to.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []ValidityChange
+	for i := 1; i < len(entries); i++ {
+		previous, current := entries[i-1], entries[i]
+		if previous.Vat != current.Vat || current.CheckedAt.Before(from) {
+			continue
+		}
+		if previous.Result.Valid != current.Result.Valid {
+			changes = append(changes, ValidityChange{Vat: current.Vat, Previous: previous.Result, Current: current.Result})
+		}
+	}
+	return changes, nil
+}
+
+func (h *SQLHistory) Prune(ctx context.Context, before time.Time) (int, error) {
+	return execCount(h.db.ExecContext(ctx, "DELETE FROM "+h.table+" WHERE checked_at < "+h.placeholder(1), before.UTC()))
+}
+
+// Erase removes the checks of vat.
+func (h *SQLHistory) Erase(ctx context.Context, vat string) (int, error) {
+	return execCount(h.db.ExecContext(ctx, "DELETE FROM "+h.table+" WHERE vat = "+h.placeholder(1), h.key(vat)))
+}
+
+func (h *SQLHistory) query(ctx context.Context, where string, args ...any) ([]HistoryEntry, error) {
+
+	rows, err := h.db.QueryContext(ctx, "SELECT vat, checked_at, result FROM "+h.table+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []HistoryEntry
+	for rows.Next() {
+		var entry HistoryEntry
+		var content string
+		if err := rows.Scan(&entry.Vat, &entry.CheckedAt, &content); err != nil {
+			return nil, err
+		}
+		if h.cipher != nil {
+			if content, err = h.cipher.decryptString(content); err != nil {
+				return nil, err
+			}
+		}
+		if err := json.Unmarshal([]byte(content), &entry.Result); err != nil {
+			return nil, err
+		}
+		if h.cipher != nil {
+			entry.Vat = vatKey(string(entry.Result.CountryCode) + entry.Result.VatNumber)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// key returns the value of the vat column for vat.
+func (h *SQLHistory) key(vat string) string {
+	if h.cipher != nil {
+		return h.cipher.hash(vatKey(vat))
+	}
+	return vatKey(vat)
+}
+
+func (h *SQLHistory) placeholders(n int) string {
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = h.placeholder(i + 1)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// vatKey is the key of vat in caches and history stores.
+func vatKey(vat string) string {
+	return normalizeVat(CleanVat(vat))
+}
+
+// execCount returns the number of rows affected by a statement.
+func execCount(result sql.Result, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,142 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func historyRow(t *testing.T, result *CheckResult) string {
+	content, err := json.Marshal(result)
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func TestSQLHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	valid := &CheckResult{CountryCode: "DE", VatNumber: "1", Valid: true, Name: "ACME"}
+	invalid := &CheckResult{CountryCode: "DE", VatNumber: "1"}
+	other := &CheckResult{CountryCode: "FR", VatNumber: "2", Valid: true}
+	history := NewSQLHistory(db, &SQLHistoryConfig{Table: "checks", Placeholder: DollarPlaceholder})
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS checks (")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE INDEX IF NOT EXISTS checks_vat ON checks (vat, checked_at)")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO checks (vat, country_code, checked_at, valid, name, request_identifier, result) VALUES ($1, $2, $3, $4, $5, $6, $7)")).
+		WithArgs("DE1", "DE", day, true, "ACME", "", historyRow(t, valid)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vat, checked_at, result FROM checks WHERE vat = $1 ORDER BY checked_at DESC LIMIT 1")).
+		WithArgs("DE1").
+		WillReturnRows(sqlmock.NewRows([]string{"vat", "checked_at", "result"}).AddRow("DE1", day, historyRow(t, valid)))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vat, checked_at, result FROM checks WHERE vat = $1")).
+		WithArgs("FR2").
+		WillReturnRows(sqlmock.NewRows([]string{"vat", "checked_at", "result"}))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vat, checked_at, result FROM checks WHERE checked_at >= $1 AND checked_at < $2 ORDER BY checked_at")).
+		WithArgs(day, day.AddDate(0, 0, 1)).
+		WillReturnRows(sqlmock.NewRows([]string{"vat", "checked_at", "result"}).AddRow("DE1", day, historyRow(t, valid)))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vat, checked_at, result FROM checks WHERE vat IN (SELECT vat FROM checks WHERE checked_at >= $1 AND checked_at < $2) AND checked_at < $3 ORDER BY vat, checked_at")).
+		WithArgs(day, day.AddDate(0, 0, 7), day.AddDate(0, 0, 7)).
+		WillReturnRows(sqlmock.NewRows([]string{"vat", "checked_at", "result"}).
+			// flipped before the period
+			AddRow("DE1", day.AddDate(0, 0, -2), historyRow(t, invalid)).
+			AddRow("DE1", day.AddDate(0, 0, -1), historyRow(t, valid)).
+			AddRow("DE1", day.AddDate(0, 0, 1), historyRow(t, valid)).
+			AddRow("DE1", day.AddDate(0, 0, 2), historyRow(t, invalid)).
+			AddRow("FR2", day.AddDate(0, 0, 3), historyRow(t, other)))
+
+	assert.NoError(t, history.CreateTable(ctx))
+	// cached results are not added again
+	assert.NoError(t, history.Record(ctx, &AuditRecord{Time: day, Result: valid}))
+	assert.NoError(t, history.Record(ctx, &AuditRecord{Time: day, Result: valid, Cached: true}))
+	assert.NoError(t, history.Record(ctx, &AuditRecord{Time: day, Error: "down"}))
+
+	last, err := history.Last(ctx, "de 1")
+	assert.NoError(t, err)
+	assert.Equal(t, &HistoryEntry{Vat: "DE1", CheckedAt: day, Result: valid}, last)
+
+	_, err = history.Last(ctx, "FR2")
+	assert.ErrorIs(t, err, ErrNoHistory)
+
+	entries, err := history.Between(ctx, day, day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	changes, err := history.Flipped(ctx, day, day.AddDate(0, 0, 7))
+	assert.NoError(t, err)
+	assert.Equal(t, []ValidityChange{{Vat: "DE1", Previous: valid, Current: invalid}}, changes)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// memoryHistory keeps the checks of each VAT number, for Scheduler tests.
+type memoryHistory map[string][]HistoryEntry
+
+func (h memoryHistory) Add(_ context.Context, checkedAt time.Time, result *CheckResult) error {
+	vat := vatKey(string(result.CountryCode) + result.VatNumber)
+	h[vat] = append(h[vat], HistoryEntry{Vat: vat, CheckedAt: checkedAt, Result: result})
+	return nil
+}
+
+func (h memoryHistory) Last(_ context.Context, vat string) (*HistoryEntry, error) {
+	entries := h[vatKey(vat)]
+	if len(entries) == 0 {
+		return nil, ErrNoHistory
+	}
+	return &entries[len(entries)-1], nil
+}
+
+func (h memoryHistory) Between(context.Context, time.Time, time.Time) ([]HistoryEntry, error) {
+	return nil, nil
+}
+
+func (h memoryHistory) Flipped(context.Context, time.Time, time.Time) ([]ValidityChange, error) {
+	return nil, nil
+}
+
+func TestSchedulerHistory(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"DE","vatNumber":"1234567890","valid":false}`)),
+			Header:     make(http.Header),
+		}
+	})
+	v, err := New(WithHttpClient(client))
+	assert.NoError(t, err)
+
+	// the previous round ran before a restart
+	history := memoryHistory{}
+	assert.NoError(t, history.Add(context.Background(), time.Now(), &CheckResult{CountryCode: "DE", VatNumber: "1234567890", Valid: true}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var changes []ValidityChange
+	scheduler := NewScheduler(v, VatList{"de1234567890"}, ResultSinkFunc(func(context.Context, BulkResult) { cancel() }), &SchedulerConfig{
+		Interval: time.Millisecond,
+		OnChange: func(ctx context.Context, change ValidityChange) {
+			changes = append(changes, change)
+		},
+		History: history,
+	})
+	assert.ErrorIs(t, scheduler.Run(ctx), context.Canceled)
+
+	assert.Len(t, changes, 1)
+	assert.True(t, changes[0].Previous.Valid)
+	assert.False(t, changes[0].Current.Valid)
+	assert.Len(t, history["DE1234567890"], 2)
+}
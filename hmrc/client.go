@@ -0,0 +1,278 @@
+// Package hmrc checks UK VAT numbers with the HMRC "Check a UK VAT number"
+// API, which replaced VIES for GB numbers, and returns vies.CheckResult.
+package hmrc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alytsin/go-vies"
+)
+
+const (
+	apiEndpointUrl = "https://api.service.hmrc.gov.uk/"
+	apiLookupPath  = "organisations/vat/check-vat-number/lookup"
+	apiTokenPath   = "oauth/token"
+	apiAccept      = "application/vnd.hmrc.2.0+json"
+
+	countryCode = "GB"
+
+	// Registry marks the results of this package in vies.CheckResult.
+	Registry = "HMRC"
+)
+
+type Config struct {
+	HttpClient  vies.HttpClientInterface
+	EndpointUrl string
+	// ClientID and ClientSecret of the HMRC developer hub application,
+	// used to obtain an application-restricted access token.
+	ClientID     string
+	ClientSecret string
+	// Requester is the UK VAT number of the party on whose behalf checks
+	// are made. When set, HMRC returns a consultation number.
+	Requester string
+}
+
+type Client struct {
+	endpoint     *url.URL
+	httpClient   vies.HttpClientInterface
+	clientID     string
+	clientSecret string
+	requester    string
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+}
+
+type lookupResponse struct {
+	Target struct {
+		Name      string  `json:"name"`
+		VatNumber string  `json:"vatNumber"`
+		Address   address `json:"address"`
+	} `json:"target"`
+	ProcessingDate     string `json:"processingDate"`
+	ConsultationNumber string `json:"consultationNumber"`
+}
+
+type address struct {
+	Line1    string `json:"line1"`
+	Line2    string `json:"line2"`
+	Line3    string `json:"line3"`
+	Line4    string `json:"line4"`
+	Line5    string `json:"line5"`
+	Postcode string `json:"postcode"`
+}
+
+type errorResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Reason  string `json:"reason"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+func NewClient(config *Config) (*Client, error) {
+
+	var client vies.HttpClientInterface
+	var clientID, clientSecret, requester string
+
+	endpoint := apiEndpointUrl
+	client = vies.NewHttpClient()
+
+	if config != nil {
+		if config.EndpointUrl != "" {
+			endpoint = config.EndpointUrl
+		}
+		if config.HttpClient != nil {
+			client = config.HttpClient
+		}
+		clientID = config.ClientID
+		clientSecret = config.ClientSecret
+		requester = config.Requester
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if requester != "" {
+		if requester, err = normalize(requester); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Client{
+		endpoint:     u,
+		httpClient:   client,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		requester:    requester,
+	}, nil
+}
+
+// Check looks up a UK VAT number, with or without the GB prefix. Numbers
+// unknown to HMRC are returned as invalid results.
+func (client *Client) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+
+	vrn, err := normalize(vat)
+	if err != nil {
+		return nil, err
+	}
+
+	path := client.endpoint.JoinPath(apiLookupPath, vrn)
+	if client.requester != "" {
+		path = path.JoinPath(client.requester)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, path.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", apiAccept)
+	if client.clientID != "" {
+		token, err := client.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rsp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch rsp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return &vies.CheckResult{
+			CountryCode: countryCode,
+			VatNumber:   vrn,
+			Vat:         countryCode + vrn,
+			Valid:       false,
+			Registry:    Registry,
+		}, nil
+	default:
+		return nil, apiError(body)
+	}
+
+	var lookup lookupResponse
+	if err := json.Unmarshal(body, &lookup); err != nil {
+		return nil, err
+	}
+
+	return &vies.CheckResult{
+		CountryCode:       countryCode,
+		VatNumber:         lookup.Target.VatNumber,
+		Vat:               countryCode + lookup.Target.VatNumber,
+		Valid:             true,
+		Name:              lookup.Target.Name,
+		Address:           lookup.Target.Address.String(),
+		RequestDate:       lookup.ProcessingDate,
+		RequestIdentifier: lookup.ConsultationNumber,
+		Registry:          Registry,
+	}, nil
+}
+
+func (client *Client) accessToken(ctx context.Context) (string, error) {
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.token != "" && time.Now().Before(client.tokenExpiry) {
+		return client.token, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {client.clientID},
+		"client_secret": {client.clientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.endpoint.JoinPath(apiTokenPath).String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rsp, err := client.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer rsp.Body.Close()
+
+	body, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return "", err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return "", apiError(body)
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return "", err
+	}
+
+	// renew a minute early so a token never expires in flight
+	client.token = token.AccessToken
+	client.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return client.token, nil
+}
+
+func apiError(body []byte) error {
+	var e errorResponse
+	if err := json.Unmarshal(body, &e); err != nil || e.Code == "" {
+		return fmt.Errorf("unexpected response structure")
+	}
+	message := e.Message
+	if message == "" {
+		message = e.Reason
+	}
+	return &vies.ApiError{Err: e.Code, Message: message}
+}
+
+// normalize strips the GB prefix and spaces, a VRN has 9 digits or 12 for
+// a branch.
+func normalize(vat string) (string, error) {
+
+	vrn := strings.ToUpper(strings.ReplaceAll(vat, " ", ""))
+	vrn = strings.TrimPrefix(vrn, countryCode)
+
+	if len(vrn) != 9 && len(vrn) != 12 {
+		return "", fmt.Errorf("%w %s", vies.ErrInvalidVat, vies.PrivateVat(vat))
+	}
+	for _, c := range vrn {
+		if c < '0' || c > '9' {
+			return "", fmt.Errorf("%w %s", vies.ErrInvalidVat, vies.PrivateVat(vat))
+		}
+	}
+	return vrn, nil
+}
+
+func (a address) String() string {
+	var lines []string
+	for _, line := range []string{a.Line1, a.Line2, a.Line3, a.Line4, a.Line5, a.Postcode} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
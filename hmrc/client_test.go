@@ -0,0 +1,120 @@
+package hmrc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+
+	tokens := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			tokens++
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+			assert.Equal(t, "id", r.PostForm.Get("client_id"))
+			_, _ = w.Write([]byte(`{"access_token":"token","token_type":"bearer","expires_in":14400}`))
+			return
+		}
+
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/vnd.hmrc.2.0+json", r.Header.Get("Accept"))
+
+		switch r.URL.Path {
+		case "/organisations/vat/check-vat-number/lookup/553557881/123456789":
+			_, _ = w.Write([]byte(`{
+				"target": {
+					"name": "Credite Sberger Donal Inc.",
+					"vatNumber": "553557881",
+					"address": {"line1": "131B Barton Hamlet", "postcode": "SW97 5CK", "countryCode": "GB"}
+				},
+				"requester": "123456789",
+				"consultationNumber": "Vtf-Vo1-jaM",
+				"processingDate": "2024-05-01T10:00:00+01:00"
+			}`))
+		case "/organisations/vat/check-vat-number/lookup/111111111/123456789":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"NOT_FOUND","reason":"targetVrn does not match a registered company"}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"code":"INVALID_CREDENTIALS","message":"Invalid Authentication information provided"}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(&Config{
+		EndpointUrl:  server.URL,
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Requester:    "GB123456789",
+	})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		vat    string
+		result *vies.CheckResult
+		err    string
+	}{
+		{
+			name: "valid",
+			vat:  "GB 553 557 881",
+			result: &vies.CheckResult{
+				CountryCode:       "GB",
+				VatNumber:         "553557881",
+				Vat:               "GB553557881",
+				Valid:             true,
+				Name:              "Credite Sberger Donal Inc.",
+				Address:           "131B Barton Hamlet\nSW97 5CK",
+				RequestDate:       "2024-05-01T10:00:00+01:00",
+				RequestIdentifier: "Vtf-Vo1-jaM",
+				Registry:          "HMRC",
+			},
+		},
+		{
+			name:   "not found",
+			vat:    "111111111",
+			result: &vies.CheckResult{CountryCode: "GB", VatNumber: "111111111", Vat: "GB111111111", Registry: "HMRC"},
+		},
+		{
+			name: "api error",
+			vat:  "222222222",
+			err:  "INVALID_CREDENTIALS: Invalid Authentication information provided",
+		},
+		{
+			name: "invalid input",
+			vat:  "GB12345",
+			err:  "invalid VAT provided GB12345",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.Check(context.Background(), tt.vat)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+			assert.Equal(t, tt.result, result)
+		})
+	}
+
+	assert.Equal(t, 1, tokens)
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient(&Config{Requester: "GBABC"})
+	assert.ErrorIs(t, err, vies.ErrInvalidVat)
+
+	client, err := NewClient(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://api.service.hmrc.gov.uk/", client.endpoint.String())
+	assert.NotZero(t, client.httpClient.(*http.Client).Timeout)
+}
@@ -0,0 +1,241 @@
+package vies
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultHttpTimeout           = 30 * time.Second
+	defaultDialTimeout           = 10 * time.Second
+	defaultKeepAlive             = 30 * time.Second
+	defaultTLSHandshakeTimeout   = 10 * time.Second
+	defaultResponseHeaderTimeout = 20 * time.Second
+	defaultIdleConnTimeout       = 90 * time.Second
+	defaultMaxIdleConnsPerHost   = 16
+)
+
+// HttpClientConfig holds the settings of NewHttpClient.
+type HttpClientConfig struct {
+	// Timeout limits a whole exchange, 30s by default.
+	Timeout time.Duration
+	// DialTimeout and KeepAlive configure the TCP connections, 10s and
+	// 30s by default.
+	DialTimeout time.Duration
+	KeepAlive   time.Duration
+	// TLSHandshakeTimeout defaults to 10s.
+	TLSHandshakeTimeout time.Duration
+	// ResponseHeaderTimeout limits the wait for the response headers once
+	// the request is sent, 20s by default.
+	ResponseHeaderTimeout time.Duration
+	// IdleConnTimeout and MaxIdleConnsPerHost configure the reuse of
+	// connections, 90s and 16 by default.
+	IdleConnTimeout     time.Duration
+	MaxIdleConnsPerHost int
+	// DialContext, when set, opens the connections instead of a
+	// net.Dialer, e.g. to cache DNS answers or pin the IP addresses of
+	// ec.europa.eu. DialTimeout, KeepAlive and Resolver are ignored then.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// Resolver, when set, resolves host names for the default dialer, e.g.
+	// to query a corporate DNS server.
+	Resolver *net.Resolver
+	// Proxy selects the proxy of a request, http.ProxyFromEnvironment by
+	// default.
+	Proxy func(req *http.Request) (*url.URL, error)
+	// TLSConfig, when set, configures the TLS connections, e.g. with a
+	// client certificate, a private CA or a minimum version.
+	TLSConfig *tls.Config
+	// IPv4Only dials IPv4 addresses only, with DialContext too.
+	IPv4Only bool
+	// Http1Only disables HTTP/2.
+	Http1Only bool
+}
+
+// HttpClientOption configures the client returned by NewHttpClient.
+type HttpClientOption func(config *HttpClientConfig)
+
+// NewHttpClient returns an http.Client tuned for VIES, with timeouts at
+// every stage and a pool of connections kept alive. NewClient uses one
+// when ClientConfig.HttpClient is nil, pass one to tune it:
+//
+//	client, err := vies.New(vies.WithHttpClient(vies.NewHttpClient(
+//		vies.WithHttpTimeout(15 * time.Second),
+//	)))
+func NewHttpClient(opts ...HttpClientOption) *http.Client {
+
+	config := HttpClientConfig{
+		Proxy:                 http.ProxyFromEnvironment,
+		Timeout:               defaultHttpTimeout,
+		DialTimeout:           defaultDialTimeout,
+		KeepAlive:             defaultKeepAlive,
+		TLSHandshakeTimeout:   defaultTLSHandshakeTimeout,
+		ResponseHeaderTimeout: defaultResponseHeaderTimeout,
+		IdleConnTimeout:       defaultIdleConnTimeout,
+		MaxIdleConnsPerHost:   defaultMaxIdleConnsPerHost,
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	dial := config.DialContext
+	if dial == nil {
+		dialer := &net.Dialer{
+			Timeout:   config.DialTimeout,
+			KeepAlive: config.KeepAlive,
+			Resolver:  config.Resolver,
+		}
+		dial = dialer.DialContext
+	}
+	if config.IPv4Only {
+		dial = ipv4Only(dial)
+	}
+
+	var nextProto map[string]func(string, *tls.Conn) http.RoundTripper
+	if config.Http1Only {
+		// A non-nil empty map keeps the transport from negotiating h2.
+		nextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	return &http.Client{
+		Timeout: config.Timeout,
+		Transport: &http.Transport{
+			Proxy:                 config.Proxy,
+			DialContext:           dial,
+			ForceAttemptHTTP2:     !config.Http1Only,
+			TLSNextProto:          nextProto,
+			TLSClientConfig:       config.TLSConfig.Clone(),
+			TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+			ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+			IdleConnTimeout:       config.IdleConnTimeout,
+			MaxIdleConns:          config.MaxIdleConnsPerHost * 2,
+			MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+			ExpectContinueTimeout: time.Second,
+		},
+	}
+}
+
+// WithHttpTimeout limits a whole exchange, zero disables the limit.
+func WithHttpTimeout(d time.Duration) HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.Timeout = d
+	}
+}
+
+// WithDialTimeout limits the time to open a TCP connection.
+func WithDialTimeout(d time.Duration) HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.DialTimeout = d
+	}
+}
+
+// WithTLSHandshakeTimeout limits the TLS handshake.
+func WithTLSHandshakeTimeout(d time.Duration) HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.TLSHandshakeTimeout = d
+	}
+}
+
+// WithResponseHeaderTimeout limits the wait for the response headers.
+func WithResponseHeaderTimeout(d time.Duration) HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.ResponseHeaderTimeout = d
+	}
+}
+
+// WithIdleConns keeps up to perHost idle connections for idleTimeout.
+func WithIdleConns(perHost int, idleTimeout time.Duration) HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.MaxIdleConnsPerHost = perHost
+		config.IdleConnTimeout = idleTimeout
+	}
+}
+
+// WithDialContext opens the connections with dial, e.g. a dialer caching
+// DNS answers or pinning IP addresses:
+//
+//	dialer := &net.Dialer{Timeout: 5 * time.Second}
+//	vies.WithDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+//		if addr == "ec.europa.eu:443" {
+//			addr = "147.67.34.30:443"
+//		}
+//		return dialer.DialContext(ctx, network, addr)
+//	})
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.DialContext = dial
+	}
+}
+
+// WithResolver resolves host names with resolver, e.g. one querying a
+// split-horizon corporate DNS server.
+func WithResolver(resolver *net.Resolver) HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.Resolver = resolver
+	}
+}
+
+// WithProxy sends every request through proxy, whatever the environment
+// variables say. The scheme is http, https or socks5 and the credentials,
+// if any, are given in the URL:
+//
+//	vies.WithProxy(&url.URL{
+//		Scheme: "http",
+//		Host:   "egress.corp:3128",
+//		User:   url.UserPassword("vies", secret),
+//	})
+func WithProxy(proxy *url.URL) HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.Proxy = http.ProxyURL(proxy)
+	}
+}
+
+// WithoutProxy connects directly, ignoring the proxy environment
+// variables.
+func WithoutProxy() HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.Proxy = nil
+	}
+}
+
+// WithTLSConfig configures the TLS connections with config, e.g. for a
+// gateway requiring a client certificate:
+//
+//	vies.WithTLSConfig(&tls.Config{
+//		Certificates: []tls.Certificate{cert},
+//		RootCAs:      gatewayCAs,
+//		MinVersion:   tls.VersionTLS13,
+//	})
+func WithTLSConfig(tlsConfig *tls.Config) HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.TLSConfig = tlsConfig
+	}
+}
+
+// WithIPv4Only dials IPv4 addresses only, for networks where IPv6 routes
+// to ec.europa.eu are announced but broken.
+func WithIPv4Only() HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.IPv4Only = true
+	}
+}
+
+// WithHttp1Only disables HTTP/2, which some corporate proxies mishandle.
+func WithHttp1Only() HttpClientOption {
+	return func(config *HttpClientConfig) {
+		config.Http1Only = true
+	}
+}
+
+// ipv4Only restricts the TCP connections opened by dial to IPv4.
+func ipv4Only(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if network == "tcp" {
+			network = "tcp4"
+		}
+		return dial(ctx, network, addr)
+	}
+}
@@ -0,0 +1,164 @@
+package vies
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHttpClient(t *testing.T) {
+
+	client := NewHttpClient()
+	transport := client.Transport.(*http.Transport)
+	assert.Equal(t, defaultHttpTimeout, client.Timeout)
+	assert.Equal(t, defaultTLSHandshakeTimeout, transport.TLSHandshakeTimeout)
+	assert.Equal(t, defaultResponseHeaderTimeout, transport.ResponseHeaderTimeout)
+	assert.Equal(t, defaultMaxIdleConnsPerHost, transport.MaxIdleConnsPerHost)
+	assert.NotNil(t, transport.DialContext)
+
+	client = NewHttpClient(
+		WithHttpTimeout(time.Second),
+		WithTLSHandshakeTimeout(2*time.Second),
+		WithResponseHeaderTimeout(3*time.Second),
+		WithIdleConns(4, time.Minute),
+	)
+	transport = client.Transport.(*http.Transport)
+	assert.Equal(t, time.Second, client.Timeout)
+	assert.Equal(t, 2*time.Second, transport.TLSHandshakeTimeout)
+	assert.Equal(t, 3*time.Second, transport.ResponseHeaderTimeout)
+	assert.Equal(t, 4, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+}
+
+func TestNewHttpClientTimeout(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+	}))
+	defer server.Close()
+
+	v, err := New(WithEndpoint(server.URL), WithHttpClient(NewHttpClient(WithResponseHeaderTimeout(20*time.Millisecond))))
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.ErrorContains(t, err, "timeout awaiting response headers")
+}
+
+func TestNewHttpClientDialContext(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"123","valid":true}`))
+	}))
+	defer server.Close()
+
+	var dialed []string
+	dialer := &net.Dialer{}
+	httpClient := NewHttpClient(WithDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+	}))
+
+	v, err := New(WithEndpoint("http://vies.internal/"), WithHttpClient(httpClient))
+	assert.NoError(t, err)
+
+	result, err := v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, []string{"vies.internal:80"}, dialed)
+}
+
+func TestNewHttpClientProxy(t *testing.T) {
+
+	var proxied *http.Request
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r
+		_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"123","valid":true}`))
+	}))
+	defer proxy.Close()
+
+	proxyUrl, err := url.Parse(proxy.URL)
+	assert.NoError(t, err)
+	proxyUrl.User = url.UserPassword("vies", "secret")
+
+	v, err := New(WithEndpoint("http://vies.example.com/"), WithHttpClient(NewHttpClient(WithProxy(proxyUrl))))
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.Equal(t, "http://vies.example.com/check-vat-number", proxied.RequestURI)
+	assert.Equal(t, "Basic dmllczpzZWNyZXQ=", proxied.Header.Get("Proxy-Authorization"))
+
+	assert.Nil(t, NewHttpClient(WithoutProxy()).Transport.(*http.Transport).Proxy)
+}
+
+func TestNewHttpClientTLS(t *testing.T) {
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Len(t, r.TLS.PeerCertificates, 1)
+		_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"123","valid":true}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	check := func(tlsConfig *tls.Config) error {
+		v, err := New(WithEndpoint(server.URL), WithHttpClient(NewHttpClient(WithTLSConfig(tlsConfig))))
+		assert.NoError(t, err)
+		_, err = v.Check(context.Background(), "EE123")
+		return err
+	}
+
+	assert.NoError(t, check(&tls.Config{RootCAs: roots, Certificates: server.TLS.Certificates}))
+	assert.ErrorContains(t, check(&tls.Config{Certificates: server.TLS.Certificates}), "certificate")
+	assert.Error(t, check(&tls.Config{RootCAs: roots}))
+}
+
+func TestNewHttpClientNetwork(t *testing.T) {
+
+	var protos []int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		protos = append(protos, r.ProtoMajor)
+		_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"123","valid":true}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	var networks []string
+	dialer := &net.Dialer{}
+	dial := WithDialContext(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		networks = append(networks, network)
+		return dialer.DialContext(ctx, network, addr)
+	})
+
+	for _, opts := range [][]HttpClientOption{
+		{dial},
+		{dial, WithIPv4Only(), WithHttp1Only()},
+	} {
+		opts = append(opts, WithTLSConfig(&tls.Config{RootCAs: roots}))
+		v, err := New(WithEndpoint(server.URL), WithHttpClient(NewHttpClient(opts...)))
+		assert.NoError(t, err)
+		_, err = v.Check(context.Background(), "EE123")
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []int{2, 1}, protos)
+	assert.Equal(t, []string{"tcp", "tcp4"}, networks)
+}
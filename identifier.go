@@ -0,0 +1,91 @@
+package vies
+
+import (
+	"strings"
+
+	"github.com/alytsin/go-vies/viescountries"
+)
+
+// IdentifierKind tells apart the numbers used in the EU VAT schemes.
+type IdentifierKind string
+
+const (
+	IdentifierUnknown IdentifierKind = "unknown"
+	// IdentifierVat is a regular VAT number, also used in the Union OSS
+	// scheme.
+	IdentifierVat IdentifierKind = "vat"
+	// IdentifierIoss is an Import One-Stop Shop number, IM followed by
+	// ten digits.
+	IdentifierIoss IdentifierKind = "ioss"
+	// IdentifierIossIntermediary identifies an intermediary acting in the
+	// IOSS scheme, IN followed by ten digits.
+	IdentifierIossIntermediary IdentifierKind = "ioss_intermediary"
+	// IdentifierNonUnionOss is a non-Union OSS scheme number, EU followed by
+	// nine digits.
+	IdentifierNonUnionOss IdentifierKind = "non_union_oss"
+)
+
+// Identifier is a classified scheme identifier.
+type Identifier struct {
+	Kind   IdentifierKind `json:"kind"`
+	Number string         `json:"number"`
+	// CountryCode is the member state of identification, taken from the
+	// prefix of VAT numbers and from the ISO 3166 numeric code of the
+	// other identifiers.
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+// ClassifyIdentifier checks the format of what a customer pasted, without
+// any request, and tells whether it is an IOSS, intermediary, non-Union OSS
+// or regular VAT number. Spaces, dots and dashes are ignored.
+func ClassifyIdentifier(s string) Identifier {
+
+	number := strings.ToUpper(strings.NewReplacer(" ", "", ".", "", "-", "").Replace(s))
+	id := Identifier{Kind: IdentifierUnknown, Number: number}
+
+	if len(number) < 4 {
+		return id
+	}
+	prefix, rest := number[0:2], number[2:]
+
+	switch {
+	case prefix == "IM" && len(rest) == 10 && isDigits(rest):
+		id.Kind = IdentifierIoss
+	case prefix == "IN" && len(rest) == 10 && isDigits(rest):
+		id.Kind = IdentifierIossIntermediary
+	case prefix == "EU" && len(rest) == 9 && isDigits(rest):
+		id.Kind = IdentifierNonUnionOss
+	case viescountries.IsMember(prefix) && len(rest) <= 12 && isAlphanumeric(rest):
+		id.Kind = IdentifierVat
+		id.CountryCode = prefix
+		return id
+	default:
+		return id
+	}
+
+	country, ok := viescountries.ByNumeric(rest[0:3])
+	if !ok {
+		id.Kind = IdentifierUnknown
+		return id
+	}
+	id.CountryCode = country.Code
+	return id
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package vies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyIdentifier(t *testing.T) {
+
+	cases := []struct {
+		input string
+		id    Identifier
+	}{
+		{input: "IM2760000001", id: Identifier{Kind: IdentifierIoss, Number: "IM2760000001", CountryCode: "DE"}},
+		{input: "im 372 000 0123", id: Identifier{Kind: IdentifierIoss, Number: "IM3720000123", CountryCode: "IE"}},
+		{input: "IN5280000042", id: Identifier{Kind: IdentifierIossIntermediary, Number: "IN5280000042", CountryCode: "NL"}},
+		{input: "EU372000041", id: Identifier{Kind: IdentifierNonUnionOss, Number: "EU372000041", CountryCode: "IE"}},
+		{input: "EE100354546", id: Identifier{Kind: IdentifierVat, Number: "EE100354546", CountryCode: "EE"}},
+		{input: "FR-40.303.265.045", id: Identifier{Kind: IdentifierVat, Number: "FR40303265045", CountryCode: "FR"}},
+		{input: "IM8400000001", id: Identifier{Kind: IdentifierUnknown, Number: "IM8400000001"}},
+		{input: "IM27600001", id: Identifier{Kind: IdentifierUnknown, Number: "IM27600001"}},
+		{input: "US123456789", id: Identifier{Kind: IdentifierUnknown, Number: "US123456789"}},
+		{input: "DE", id: Identifier{Kind: IdentifierUnknown, Number: "DE"}},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.id, ClassifyIdentifier(tt.input))
+		})
+	}
+}
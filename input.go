@@ -0,0 +1,82 @@
+package vies
+
+// InputReason tells why a VAT number was rejected before any request was
+// sent to VIES.
+type InputReason string
+
+const (
+	// ReasonEmpty is the reason of empty or blank inputs.
+	ReasonEmpty InputReason = "empty"
+	// ReasonTooLong is the reason of inputs too long to be a VAT number
+	// in any notation, Input is truncated then.
+	ReasonTooLong InputReason = "too_long"
+	// ReasonNewline is the reason of inputs spanning several lines.
+	ReasonNewline InputReason = "newline"
+	// ReasonControlCharacters is the reason of inputs with control
+	// characters other than newlines.
+	ReasonControlCharacters InputReason = "control_characters"
+	// ReasonTooShort is the reason of inputs without a number after the
+	// country code.
+	ReasonTooShort InputReason = "too_short"
+	// ReasonBadCountryCode is the reason of inputs not starting with the
+	// code of a member state.
+	ReasonBadCountryCode InputReason = "bad_country_code"
+	// ReasonBadCharacters is the reason of numbers with characters no
+	// member state uses, or of inputs which are not valid UTF-8.
+	ReasonBadCharacters InputReason = "bad_characters"
+	// ReasonBadLength is the reason of numbers longer or shorter than
+	// those of their member state.
+	ReasonBadLength InputReason = "bad_length"
+	// ReasonBadFormat is the reason of numbers not matching the format of
+	// their member state.
+	ReasonBadFormat InputReason = "bad_format"
+)
+
+// ErrInvalidInput is the error of a VAT number rejected before any request
+// is sent to VIES. It wraps ErrInvalidVat:
+//
+//	var inputErr *vies.ErrInvalidInput
+//	if errors.As(err, &inputErr) && inputErr.Reason == vies.ReasonBadCountryCode {
+//		...
+//	}
+type ErrInvalidInput struct {
+	// Input is the VAT number as given.
+	Input string
+	// CountryCode is the country code detected in Input, if any.
+	CountryCode string
+	Reason      InputReason
+}
+
+func (e *ErrInvalidInput) Error() string {
+	return ErrInvalidVat.Error() + " " + PrivateVat(e.Input)
+}
+
+func (e *ErrInvalidInput) Unwrap() error {
+	return ErrInvalidVat
+}
+
+// invalidInput returns the error of a rejected VAT number, with the reason
+// guessed from its normalized form.
+func invalidInput(input string) *ErrInvalidInput {
+
+	vat := normalizeVat(CleanVat(input))
+	switch {
+	case len(vat) >= 2 && !isLetters(vat[0:2]):
+		return &ErrInvalidInput{Input: input, Reason: ReasonBadCountryCode}
+	case len(vat) < 3:
+		return &ErrInvalidInput{Input: input, Reason: ReasonTooShort}
+	}
+	if err := checkNumber(input, vat[0:2], vat[2:]); err != nil {
+		return err
+	}
+	return &ErrInvalidInput{Input: input, CountryCode: vat[0:2], Reason: ReasonBadFormat}
+}
+
+func isLetters(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
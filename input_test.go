@@ -0,0 +1,95 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrInvalidInput(t *testing.T) {
+
+	cases := []struct {
+		input   string
+		country string
+		reason  InputReason
+	}{
+		{input: "", reason: ReasonEmpty},
+		{input: "EE", reason: ReasonTooShort},
+		{input: "12345", reason: ReasonBadCountryCode},
+		{input: "GB123456789", reason: ReasonBadCountryCode},
+		{input: "DE12345678", country: "DE", reason: ReasonBadLength},
+		{input: "BE2123456789", country: "BE", reason: ReasonBadFormat},
+		{input: "de 123_456_789", country: "DE", reason: ReasonBadCharacters},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseVatNumber(tt.input)
+			assert.ErrorIs(t, err, ErrInvalidVat)
+			assert.EqualError(t, err, "invalid VAT provided "+tt.input)
+
+			var inputErr *ErrInvalidInput
+			assert.True(t, errors.As(err, &inputErr))
+			assert.Equal(t, tt.input, inputErr.Input)
+			assert.Equal(t, tt.country, inputErr.CountryCode)
+			assert.Equal(t, tt.reason, inputErr.Reason)
+		})
+	}
+}
+
+func TestCheckInvalidInput(t *testing.T) {
+
+	v, err := NewClient(nil)
+	assert.NoError(t, err)
+
+	var inputErr *ErrInvalidInput
+	_, err = v.Check(context.Background(), "1234567")
+	assert.True(t, errors.As(err, &inputErr))
+	assert.Equal(t, ReasonBadCountryCode, inputErr.Reason)
+
+	_, err = v.CheckNumber(context.Background(), "ee", "")
+	assert.True(t, errors.As(err, &inputErr))
+	assert.Equal(t, ErrInvalidInput{Input: "EE", CountryCode: "EE", Reason: ReasonTooShort}, *inputErr)
+}
+
+func TestPreflightFormat(t *testing.T) {
+
+	calls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"100354546","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+	v, err := New(WithHttpClient(client), WithPreflightFormat())
+	assert.NoError(t, err)
+
+	cases := []struct {
+		vat    string
+		reason InputReason
+	}{
+		{vat: "EE10035454", reason: ReasonBadLength},
+		{vat: "EE10035454X", reason: ReasonBadCharacters},
+		{vat: "GB123456789", reason: ReasonBadCountryCode},
+	}
+	for _, tt := range cases {
+		_, err := v.Check(context.Background(), tt.vat)
+		var inputErr *ErrInvalidInput
+		assert.True(t, errors.As(err, &inputErr), tt.vat)
+		assert.Equal(t, tt.reason, inputErr.Reason, tt.vat)
+	}
+	assert.Zero(t, calls)
+
+	_, err = v.Check(context.Background(), "ee100354546")
+	assert.NoError(t, err)
+	_, err = v.CheckNumber(context.Background(), "ie", "1234567fa")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,37 @@
+package vies
+
+import (
+	"net/http"
+	"slices"
+)
+
+// RoundTripperFunc sends a request to VIES and returns its response.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+// Interceptor wraps the sending of every request made by the client. It
+// can modify the request, inspect the response or call next several times,
+// e.g. to add authentication headers, log or retry.
+type Interceptor func(next RoundTripperFunc) RoundTripperFunc
+
+// chain applies the interceptors around base, the first interceptor being
+// the outermost one.
+func chain(base RoundTripperFunc, interceptors []Interceptor) RoundTripperFunc {
+	next := base
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		next = interceptors[i](next)
+	}
+	return next
+}
+
+// buildSend chains the interceptors of client, then its retry policy and
+// debug dump, around roundTrip.
+func (client *Client) buildSend() {
+	interceptors := client.interceptors
+	if client.retry != nil {
+		interceptors = append(slices.Clone(interceptors), retryInterceptor(client.retry, client.clock))
+	}
+	if client.debug != nil {
+		interceptors = append(slices.Clone(interceptors), debugInterceptor(client.debug, client.redactVat))
+	}
+	client.send = chain(client.roundTrip, interceptors)
+}
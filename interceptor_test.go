@@ -0,0 +1,71 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterceptors(t *testing.T) {
+	calls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		assert.Equal(t, "secret", req.Header.Get("X-Api-Key"))
+		if calls == 1 {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"errorWrappers":[{"error":"SERVICE_UNAVAILABLE","message":"down"}]}`)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	var order []string
+	trace := func(name string) Interceptor {
+		return func(next RoundTripperFunc) RoundTripperFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return next(req)
+			}
+		}
+	}
+	apiKey := func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Api-Key", "secret")
+			return next(req)
+		}
+	}
+	retryOnce := func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			rsp, err := next(req)
+			if err == nil && rsp.StatusCode == http.StatusServiceUnavailable {
+				rsp.Body.Close()
+				return next(req)
+			}
+			return rsp, err
+		}
+	}
+
+	v, err := NewClient(&ClientConfig{
+		HttpClient:   client,
+		EndpointUrl:  "https://example.com/",
+		Interceptors: []Interceptor{trace("outer"), apiKey, trace("inner"), retryOnce},
+	})
+	assert.NoError(t, err)
+
+	result, err := v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []string{"outer", "inner"}, order)
+}
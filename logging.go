@@ -0,0 +1,63 @@
+package vies
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// MaskVat keeps the country code and the last two characters of a VAT
+// number and replaces everything in between with asterisks.
+func MaskVat(vat string) string {
+	if len(vat) <= 4 {
+		return strings.Repeat("*", len(vat))
+	}
+	return vat[:2] + strings.Repeat("*", len(vat)-4) + vat[len(vat)-2:]
+}
+
+func (client *Client) logVat(vat string) string {
+	switch {
+	case PrivacyMode():
+		return PseudonymizeVat(vat)
+	case client.redactVat:
+		return MaskVat(vat)
+	}
+	return vat
+}
+
+func (client *Client) logRequest(ctx context.Context, operation, vat, outcome string, obs *observation, duration time.Duration, err error) {
+	if client.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", operation),
+		slog.String("outcome", outcome),
+		slog.Duration("duration", duration),
+		slog.Int("attempts", obs.attempts),
+	}
+	if vat != "" {
+		attrs = append(attrs, slog.String("vat", client.logVat(vat)))
+	}
+	if obs.statusCode != 0 {
+		attrs = append(attrs, slog.Int("status", obs.statusCode))
+	}
+	if id := client.correlationID(ctx); id != "" {
+		attrs = append(attrs, slog.String("correlation_id", id))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+		client.logger.LogAttrs(ctx, slog.LevelWarn, "vies request failed", attrs...)
+		return
+	}
+	client.logger.LogAttrs(ctx, slog.LevelDebug, "vies request", attrs...)
+}
+
+func (client *Client) logCacheHit(ctx context.Context, vat string) {
+	if client.logger == nil {
+		return
+	}
+	client.logger.LogAttrs(ctx, slog.LevelDebug, "vies cache hit", slog.String("vat", client.logVat(vat)))
+}
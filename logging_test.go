@@ -0,0 +1,67 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskVat(t *testing.T) {
+	cases := map[string]string{
+		"EE100354546": "EE*******46",
+		"DE12345":     "DE***45",
+		"EE12":        "****",
+		"":            "",
+	}
+
+	for in, want := range cases {
+		t.Run(in, func(t *testing.T) {
+			assert.Equal(t, want, MaskVat(in))
+		})
+	}
+}
+
+func TestLogging(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/check-status" {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"errorWrappers":[{"error":"err","message":"msg"}]}`)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"100354546","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Logger: logger, RedactVat: true})
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE100354546")
+	assert.NoError(t, err)
+	_, err = v.Status(context.Background())
+	assert.Error(t, err)
+
+	assert.Equal(t, "level=DEBUG msg=\"vies request\" operation=check outcome=valid attempts=1 vat=EE*******46 status=200\n"+
+		"level=WARN msg=\"vies request failed\" operation=status outcome=error attempts=1 status=500 error=\"err: msg\"\n", out.String())
+	assert.NotContains(t, out.String(), "100354546")
+}
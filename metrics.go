@@ -0,0 +1,124 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	operationCheck  = "check"
+	operationStatus = "status"
+	operationEori   = "eori"
+
+	outcomeValid   = "valid"
+	outcomeInvalid = "invalid"
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+)
+
+// Names of the metrics reported through MetricsInterface.
+const (
+	// MetricRequests counts requests sent to VIES, labeled with operation,
+	// country, outcome and status (the HTTP status code).
+	MetricRequests = "requests_total"
+	// MetricRequestDuration observes the latency of requests in seconds,
+	// labeled with operation, country and outcome.
+	MetricRequestDuration = "request_duration_seconds"
+	// MetricCacheRequests counts cache lookups, labeled with result (hit
+	// or miss).
+	MetricCacheRequests = "cache_requests_total"
+	// MetricErrors counts failed requests, labeled with operation and code
+	// (the VIES error code, or TRANSPORT_ERROR, CANCELED, DEADLINE_EXCEEDED).
+	MetricErrors = "errors_total"
+	// MetricRetries counts requests sent again by the retry policy of
+	// ClientConfig.Retry, labeled with operation and country.
+	MetricRetries = "retries_total"
+)
+
+// MetricsInterface receives the metrics of a client. The viesmetrics
+// package provides Prometheus, statsd and Datadog implementations.
+type MetricsInterface interface {
+	IncCounter(name string, labels map[string]string)
+	ObserveHistogram(name string, value float64, labels map[string]string)
+}
+
+func (client *Client) observeRequest(operation, country, outcome string, obs *observation, duration time.Duration, err error) {
+	if client.metrics == nil {
+		return
+	}
+	status := ""
+	if obs.statusCode != 0 {
+		status = strconv.Itoa(obs.statusCode)
+	}
+	client.metrics.IncCounter(MetricRequests, map[string]string{
+		"operation": operation,
+		"country":   country,
+		"outcome":   outcome,
+		"status":    status,
+	})
+	client.metrics.ObserveHistogram(MetricRequestDuration, duration.Seconds(), map[string]string{
+		"operation": operation,
+		"country":   country,
+		"outcome":   outcome,
+	})
+	for range obs.attempts - 1 {
+		client.metrics.IncCounter(MetricRetries, map[string]string{
+			"operation": operation,
+			"country":   country,
+		})
+	}
+	if err != nil {
+		client.metrics.IncCounter(MetricErrors, map[string]string{
+			"operation": operation,
+			"code":      errorCode(err),
+		})
+	}
+}
+
+// errorCode classifies an error for metric labels.
+func errorCode(err error) string {
+	var apiErr *ApiError
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.Err
+	case errors.Is(err, context.Canceled):
+		return "CANCELED"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "DEADLINE_EXCEEDED"
+	}
+	return "TRANSPORT_ERROR"
+}
+
+func (client *Client) observeCache(hit bool) {
+	if client.metrics == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	client.metrics.IncCounter(MetricCacheRequests, map[string]string{"result": result})
+}
+
+type observationKey struct{}
+
+// observation collects details of the HTTP exchange behind a single client
+// call, it is filled in by Client.do.
+type observation struct {
+	statusCode int
+	attempts   int
+	header     http.Header
+}
+
+func observe(ctx context.Context) (context.Context, *observation) {
+	obs := &observation{}
+	return context.WithValue(ctx, observationKey{}, obs), obs
+}
+
+func observationFrom(ctx context.Context) *observation {
+	obs, _ := ctx.Value(observationKey{}).(*observation)
+	return obs
+}
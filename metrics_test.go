@@ -0,0 +1,84 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingMetrics struct {
+	mu         sync.Mutex
+	counters   []string
+	histograms []string
+}
+
+func (m *recordingMetrics) IncCounter(name string, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters = append(m.counters, fmt.Sprintf("%s %v", name, labels))
+}
+
+func (m *recordingMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.histograms = append(m.histograms, fmt.Sprintf("%s %v", name, labels))
+}
+
+func TestMetrics(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		if req.URL.Path == "/check-status" {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"errorWrappers":[{"error":"err","message":"msg"}]}`)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	metrics := &recordingMetrics{}
+	v, err := NewClient(&ClientConfig{
+		HttpClient:  client,
+		EndpointUrl: "https://example.com/",
+		Cache:       NewMemoryCache(time.Minute),
+		Metrics:     metrics,
+	})
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	_, err = v.Status(context.Background())
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"cache_requests_total map[result:miss]",
+		"requests_total map[country:EE operation:check outcome:valid status:200]",
+		"cache_requests_total map[result:hit]",
+		"requests_total map[country: operation:status outcome:error status:500]",
+		"errors_total map[code:err operation:status]",
+	}, metrics.counters)
+	assert.Equal(t, []string{
+		"request_duration_seconds map[country:EE operation:check outcome:valid]",
+		"request_duration_seconds map[country: operation:status outcome:error]",
+	}, metrics.histograms)
+}
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, "MS_UNAVAILABLE", errorCode(&CorrelationError{Err: &ApiError{Err: "MS_UNAVAILABLE"}}))
+	assert.Equal(t, "CANCELED", errorCode(context.Canceled))
+	assert.Equal(t, "DEADLINE_EXCEEDED", errorCode(context.DeadlineExceeded))
+	assert.Equal(t, "TRANSPORT_ERROR", errorCode(io.ErrUnexpectedEOF))
+}
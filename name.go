@@ -0,0 +1,273 @@
+package vies
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// legalForms are the legal forms of companies recognized by NormalizeName
+// and SplitLegalForm, compared after normalization so that "Sp. z o.o." matches "SP Z O O".
+var legalForms = []string{
+	// Austria, Germany
+	"GmbH & Co. KG", "GmbH", "gGmbH", "AG", "KG", "OHG", "UG", "e.K.", "e.V.", "KGaA",
+	// Belgium, France, Luxembourg
+	"SA", "SAS", "SASU", "SARL", "EURL", "SNC", "SCS", "SC", "SRL", "SPRL", "BV", "BVBA", "CV", "SCRL",
+	// Bulgaria
+	"EOOD", "OOD", "EAD", "AD", "ET",
+	// Croatia, Slovenia
+	"d.o.o.", "d.d.", "j.d.o.o.", "s.p.",
+	// Cyprus, Ireland, Malta, Northern Ireland
+	"Ltd", "Limited", "PLC", "DAC", "CLG", "UC",
+	// Czechia, Slovakia
+	"s.r.o.", "a.s.", "v.o.s.", "k.s.",
+	// Denmark, Sweden, Finland
+	"ApS", "A/S", "AB", "HB", "KB", "Oy", "Oyj", "Ky", "Tmi",
+	// Estonia, Latvia, Lithuania
+	"OÜ", "AS", "TÜ", "UÜ", "MTÜ", "SIA", "UAB", "AB", "IĮ", "MB",
+	// Greece
+	"AE", "EPE", "IKE", "OE", "EE",
+	// Hungary
+	"Kft.", "Zrt.", "Nyrt.", "Bt.", "Kkt.",
+	// Italy, Spain, Portugal
+	"S.p.A.", "S.r.l.", "S.r.l.s.", "S.a.s.", "S.n.c.", "S.L.", "S.L.U.", "S.A.U.", "S.C.", "Lda", "Unipessoal Lda",
+	// Netherlands
+	"B.V.", "N.V.", "V.O.F.", "C.V.",
+	// Poland
+	"Sp. z o.o.", "S.A.", "Sp.k.", "Sp.j.", "S.K.A.",
+	// Romania
+	"S.R.L.", "PFA",
+	// Europe
+	"SE", "SCE", "EEIG",
+}
+
+// legalFormTokens are the normalized words of legalForms, the longest
+// first.
+var legalFormTokens = func() [][]string {
+	forms := make([][]string, 0, len(legalForms))
+	for _, form := range legalForms {
+		forms = append(forms, nameTokens(form))
+	}
+	slices.SortStableFunc(forms, func(a, b []string) int { return len(b) - len(a) })
+	return forms
+}()
+
+// NormalizeName returns a company name reduced for comparisons: lower
+// case, without diacritics, punctuation or legal form, such as GmbH or
+// Sp. z o.o., the words being separated by single spaces.
+func NormalizeName(name string) string {
+	return strings.Join(stripLegalForm(nameWords(name)), " ")
+}
+
+// NameSimilarity scores how alike two company names are, from 0 to 1 for
+// names equal once normalized with NormalizeName. Words in another order
+// score as high as in the same order. Names VIES doesn't disclose ("---")
+// score 0.
+func NameSimilarity(a, b string) float64 {
+
+	ta, tb := stripLegalForm(nameWords(a)), stripLegalForm(nameWords(b))
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	score := similarity(strings.Join(ta, " "), strings.Join(tb, " "))
+
+	ta, tb = slices.Clone(ta), slices.Clone(tb)
+	slices.Sort(ta)
+	slices.Sort(tb)
+	return max(score, similarity(strings.Join(ta, " "), strings.Join(tb, " ")))
+}
+
+// NameSimilarity scores the name of the trader against name, see
+// NameSimilarity.
+func (result *CheckResult) NameSimilarity(name string) float64 {
+	return NameSimilarity(result.Name, name)
+}
+
+// nameTrim are the characters trimmed around the name left by
+// SplitLegalForm, such as the quotes of SIA "Rīgas Piens".
+const nameTrim = " ,;-&\"'“”„«»"
+
+// SplitLegalForm splits the legal form off a company name, at its end or
+// at its start as in Latvia and Lithuania, both as written in name:
+//
+//	vies.SplitLegalForm("ACME SP. Z O.O.")  // "ACME", "SP. Z O.O."
+//	vies.SplitLegalForm(`SIA "Rīgas Piens"`) // "Rīgas Piens", "SIA"
+//
+// The form is empty when name has none. Forms are recognized whatever
+// their case and punctuation, compare them with NormalizeName.
+func SplitLegalForm(name string) (base, form string) {
+
+	words := nameWords(name)
+	found, rest := findLegalForm(words)
+	if !found {
+		return strings.Trim(name, nameTrim), ""
+	}
+	if rest[0].start == words[0].start {
+		base, form = name[:words[len(rest)].start], name[words[len(rest)].start:words[len(words)-1].end]
+	} else {
+		n := len(words) - len(rest)
+		base, form = name[rest[0].start:], name[:words[n-1].end]
+	}
+	return strings.Trim(base, nameTrim), strings.Trim(form, nameTrim)
+}
+
+// SplitName splits the legal form off the name of the trader, see
+// SplitLegalForm.
+func (result *CheckResult) SplitName() (base, form string) {
+	return SplitLegalForm(result.Name)
+}
+
+// nameWord is a word of a name, normalized, with its position in the
+// name.
+type nameWord struct {
+	word       string
+	start, end int
+}
+
+// nameWords splits a name into lower-case words without diacritics,
+// punctuation being dropped.
+func nameWords(name string) []nameWord {
+
+	var words []nameWord
+	var b strings.Builder
+	start := -1
+	flush := func(end int) {
+		if start >= 0 && b.Len() > 0 {
+			words = append(words, nameWord{word: b.String(), start: start, end: end})
+		}
+		b.Reset()
+		start = -1
+	}
+
+	for i, r := range name {
+		switch {
+		case r == '&':
+			flush(i)
+			words = append(words, nameWord{word: "&", start: i, end: i + 1})
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+			b.WriteString(foldRune(r))
+		case (r == '.' || r == '/' || r == '\'') && start >= 0:
+			// "S.A." and "A/S" are one word
+		default:
+			flush(i)
+		}
+	}
+	flush(len(name))
+
+	// a word ends after its last letter or dot
+	for i := range words {
+		for words[i].end < len(name) && strings.ContainsRune("./'", rune(name[words[i].end])) {
+			words[i].end++
+		}
+	}
+	return words
+}
+
+// foldRune returns r in lower case without diacritics.
+func foldRune(r rune) string {
+	switch r {
+	case 'ß':
+		return "ss"
+	case 'ø', 'Ø':
+		return "o"
+	case 'ł', 'Ł':
+		return "l"
+	case 'æ', 'Æ':
+		return "ae"
+	}
+	var b strings.Builder
+	for _, c := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, c) {
+			b.WriteRune(unicode.ToLower(c))
+		}
+	}
+	return b.String()
+}
+
+// nameTokens returns the normalized words of nameWords.
+func nameTokens(name string) []string {
+	words := nameWords(name)
+	tokens := make([]string, len(words))
+	for i, w := range words {
+		tokens[i] = w.word
+	}
+	return tokens
+}
+
+// findLegalForm returns the legal form at the end or, as in Latvia and
+// Lithuania, at the start of the words of a name, and the words left, unless
+// it is the whole name.
+func findLegalForm(words []nameWord) (bool, []nameWord) {
+	for _, form := range legalFormTokens {
+		n := len(form)
+		if len(words) <= n {
+			continue
+		}
+		if wordsEqual(words[len(words)-n:], form) {
+			return true, trimAnd(words[:len(words)-n])
+		}
+		if wordsEqual(words[:n], form) {
+			return true, words[n:]
+		}
+	}
+	return false, trimAnd(words)
+}
+
+func wordsEqual(words []nameWord, tokens []string) bool {
+	for i, w := range words {
+		if w.word != tokens[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// stripLegalForm returns the words of a name without its legal form.
+func stripLegalForm(words []nameWord) []string {
+	_, rest := findLegalForm(words)
+	tokens := make([]string, len(rest))
+	for i, w := range rest {
+		tokens[i] = w.word
+	}
+	return tokens
+}
+
+// trimAnd removes the & left over by "Foo & Co." once "Co." is gone.
+func trimAnd(words []nameWord) []nameWord {
+	for len(words) > 1 && words[len(words)-1].word == "&" {
+		words = words[:len(words)-1]
+	}
+	return words
+}
+
+// similarity is one minus the Levenshtein distance of a and b divided by
+// the length of the longest.
+func similarity(a, b string) float64 {
+
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			prev, row[j] = row[j], min(row[j]+1, row[j-1]+1, prev+cost)
+		}
+	}
+	return 1 - float64(row[len(rb)])/float64(max(len(ra), len(rb)))
+}
@@ -0,0 +1,64 @@
+package vies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeName(t *testing.T) {
+
+	cases := map[string]string{
+		"Müller Bäckerei GmbH":         "muller backerei",
+		"ACME Sp. z o.o.":              "acme",
+		"Straße & Söhne GmbH & Co. KG": "strasse & sohne",
+		"SIA \"Rīgas Piens\"":          "rigas piens",
+		"  Foo,  Bar   B.V. ":          "foo bar",
+		"OÜ":                           "ou",
+		"---":                          "",
+	}
+	for name, normalized := range cases {
+		assert.Equal(t, normalized, NormalizeName(name), name)
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+
+	assert.Equal(t, 1.0, NameSimilarity("Müller Bäckerei GmbH", "MULLER BACKEREI"))
+	assert.Equal(t, 1.0, NameSimilarity("Acme Trading S.R.L.", "trading acme srl"))
+	assert.InDelta(t, 0.92, NameSimilarity("Acme Tradng", "Acme Trading"), 0.01)
+	assert.Less(t, NameSimilarity("Acme Trading", "Globex Corporation"), 0.3)
+	assert.Zero(t, NameSimilarity("---", "Acme"))
+
+	result := &CheckResult{Name: "ACME OÜ"}
+	assert.Equal(t, 1.0, result.NameSimilarity("Acme"))
+}
+
+func TestSplitLegalForm(t *testing.T) {
+
+	cases := []struct {
+		name, base, form string
+	}{
+		{name: "Müller Bäckerei GmbH", base: "Müller Bäckerei", form: "GmbH"},
+		{name: "ACME SP. Z O.O.", base: "ACME", form: "SP. Z O.O."},
+		{name: "Straße & Söhne GmbH & Co. KG", base: "Straße & Söhne", form: "GmbH & Co. KG"},
+		{name: "ACME (Europe), S.R.L.", base: "ACME (Europe)", form: "S.R.L."},
+		{name: "Tere OÜ", base: "Tere", form: "OÜ"},
+		{name: "Heineken N.V.", base: "Heineken", form: "N.V."},
+		{name: `SIA "Rīgas Piens"`, base: "Rīgas Piens", form: "SIA"},
+		{name: "UAB Vilniaus Duona", base: "Vilniaus Duona", form: "UAB"},
+		{name: "Acme Trading", base: "Acme Trading"},
+		{name: "GmbH", base: "GmbH"},
+		{name: "", base: ""},
+	}
+	for _, tt := range cases {
+		base, form := SplitLegalForm(tt.name)
+		assert.Equal(t, tt.base, base, tt.name)
+		assert.Equal(t, tt.form, form, tt.name)
+	}
+
+	result := &CheckResult{Name: "ACME S.P.A."}
+	base, form := result.SplitName()
+	assert.Equal(t, "ACME", base)
+	assert.Equal(t, "S.P.A.", form)
+}
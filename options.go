@@ -0,0 +1,233 @@
+package vies
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a client created with New.
+type Option func(config *ClientConfig)
+
+// New returns a client configured by options, as an alternative to
+// NewClient:
+//
+//	client, err := vies.New(
+//		vies.WithTimeout(10*time.Second),
+//		vies.WithRetry(3, time.Second),
+//		vies.WithCache(vies.NewMemoryCache(time.Hour)),
+//	)
+func New(opts ...Option) (*Client, error) {
+	var config ClientConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return NewClient(&config)
+}
+
+// WithHttpClient sends the requests with client instead of one of
+// NewHttpClient.
+func WithHttpClient(client HttpClientInterface) Option {
+	return func(config *ClientConfig) {
+		config.HttpClient = client
+	}
+}
+
+// WithEndpoint overrides the URL of the VIES REST API.
+func WithEndpoint(url string) Option {
+	return func(config *ClientConfig) {
+		config.EndpointUrl = url
+	}
+}
+
+// WithTimeout limits each request to d. It replaces the HTTP client by one
+// of NewHttpClient with that timeout unless WithHttpClient is given too.
+func WithTimeout(d time.Duration) Option {
+	return func(config *ClientConfig) {
+		config.Timeout = d
+	}
+}
+
+// WithRetry sends a request up to attempts times, waiting backoff before
+// the first retry and doubling it for each following one.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(config *ClientConfig) {
+		config.Retry = &RetryConfig{MaxAttempts: attempts, Backoff: backoff}
+	}
+}
+
+// WithCache consults cache before each Check and fills it after.
+func WithCache(cache CacheInterface) Option {
+	return func(config *ClientConfig) {
+		config.Cache = cache
+	}
+}
+
+// WithRateLimiter throttles every request with limiter.
+func WithRateLimiter(limiter RateLimiterInterface) Option {
+	return func(config *ClientConfig) {
+		config.RateLimiter = limiter
+	}
+}
+
+// WithRequester makes the checks on behalf of the VAT number requester, so
+// that VIES returns a consultation number.
+func WithRequester(requester string) Option {
+	return func(config *ClientConfig) {
+		config.Requester = requester
+	}
+}
+
+// WithUserAgent sends userAgent in the User-Agent header of every request.
+func WithUserAgent(userAgent string) Option {
+	return func(config *ClientConfig) {
+		config.UserAgent = userAgent
+	}
+}
+
+// WithHeader adds a header to every request, it can be given several
+// times.
+func WithHeader(name, value string) Option {
+	return func(config *ClientConfig) {
+		if config.Headers == nil {
+			config.Headers = make(http.Header)
+		}
+		config.Headers.Add(name, value)
+	}
+}
+
+// WithExtraFields keeps the fields VIES adds to its responses in
+// CheckResult.Extra and Status.Extra.
+func WithExtraFields() Option {
+	return func(config *ClientConfig) {
+		config.CaptureExtraFields = true
+	}
+}
+
+// WithStrictDecoding fails calls whose response doesn't match the result
+// type exactly, see ClientConfig.StrictDecoding.
+func WithStrictDecoding() Option {
+	return func(config *ClientConfig) {
+		config.StrictDecoding = true
+	}
+}
+
+// WithRawResponse keeps the body of the VIES responses in CheckResult.Raw
+// and Status.Raw.
+func WithRawResponse() Option {
+	return func(config *ClientConfig) {
+		config.KeepRawResponse = true
+	}
+}
+
+// WithResponseHeaders copies the named headers of the VIES responses to
+// CheckResult.ResponseHeaders and Status.ResponseHeaders.
+func WithResponseHeaders(names ...string) Option {
+	return func(config *ClientConfig) {
+		config.ResponseHeaders = append(config.ResponseHeaders, names...)
+	}
+}
+
+// WithMaxResponseSize caps the size of response bodies, see
+// ClientConfig.MaxResponseSize.
+func WithMaxResponseSize(bytes int64) Option {
+	return func(config *ClientConfig) {
+		config.MaxResponseSize = bytes
+	}
+}
+
+// WithAllowedCountries restricts Check to the VAT numbers of these member
+// states, see ClientConfig.AllowedCountries.
+func WithAllowedCountries(codes ...string) Option {
+	return func(config *ClientConfig) {
+		config.AllowedCountries = append(config.AllowedCountries, codes...)
+	}
+}
+
+// WithBlockedCountries makes Check reject the VAT numbers of these member
+// states, see ClientConfig.BlockedCountries.
+func WithBlockedCountries(codes ...string) Option {
+	return func(config *ClientConfig) {
+		config.BlockedCountries = append(config.BlockedCountries, codes...)
+	}
+}
+
+// WithRevalidation stamps results with the time of the check and the date
+// after which to verify them again, valid results after valid and
+// invalid ones after invalid, see ClientConfig.Revalidation.
+func WithRevalidation(valid, invalid time.Duration) Option {
+	return func(config *ClientConfig) {
+		config.Revalidation = &RevalidationPolicy{Valid: valid, Invalid: invalid}
+	}
+}
+
+// WithProofSigner signs the results of Check with signer, see
+// ClientConfig.ProofSigner.
+func WithProofSigner(signer ProofSignerInterface) Option {
+	return func(config *ClientConfig) {
+		config.ProofSigner = signer
+	}
+}
+
+// WithAuditSink records every check to sink, see ClientConfig.Audit.
+func WithAuditSink(sink AuditSinkInterface) Option {
+	return func(config *ClientConfig) {
+		config.Audit = sink
+	}
+}
+
+// WithFakeResponses makes Check answer with results, keyed by VAT number,
+// without calling VIES, see ClientConfig.FakeResponses.
+func WithFakeResponses(results map[string]CheckResult) Option {
+	return func(config *ClientConfig) {
+		config.FakeResponses = results
+	}
+}
+
+// WithFakeErrors makes Check fail with the errors keyed by VAT number or
+// country code instead of calling VIES, see ClientConfig.FakeResponses.
+func WithFakeErrors(errors map[string]error) Option {
+	return func(config *ClientConfig) {
+		config.FakeErrors = errors
+	}
+}
+
+// WithClock replaces the system clock of the client, see
+// ClientConfig.Clock.
+func WithClock(clock ClockInterface) Option {
+	return func(config *ClientConfig) {
+		config.Clock = clock
+	}
+}
+
+// WithPreflightFormat checks the length and characters of numbers before
+// calling VIES, see ClientConfig.PreflightFormat.
+func WithPreflightFormat() Option {
+	return func(config *ClientConfig) {
+		config.PreflightFormat = true
+	}
+}
+
+// WithStrictInput rejects numbers CleanVat would repair, see
+// ClientConfig.StrictInput.
+func WithStrictInput() Option {
+	return func(config *ClientConfig) {
+		config.StrictInput = true
+	}
+}
+
+// WithRequestTimeout sets a deadline of d on calls whose context has none,
+// see ClientConfig.RequestTimeout.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(config *ClientConfig) {
+		config.RequestTimeout = d
+	}
+}
+
+// defaultHttpClient returns the client of NewHttpClient, with timeout when
+// set.
+func defaultHttpClient(timeout time.Duration) HttpClientInterface {
+	if timeout > 0 {
+		return NewHttpClient(WithHttpTimeout(timeout))
+	}
+	return NewHttpClient()
+}
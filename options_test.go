@@ -0,0 +1,146 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+
+	var requester string
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		assert.Equal(t, "https://example.com/check-vat-number", req.URL.String())
+		var body bytes.Buffer
+		_, _ = body.ReadFrom(req.Body)
+		requester = body.String()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	cache := NewMemoryCache(time.Minute)
+	v, err := New(
+		WithHttpClient(httpClient),
+		WithEndpoint("https://example.com/"),
+		WithRetry(2, time.Millisecond),
+		WithCache(cache),
+		WithRequester("de123"),
+	)
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.Contains(t, requester, `"requesterMemberStateCode":"DE"`)
+
+	_, ok := cache.Get("EE123@DE123")
+	assert.True(t, ok)
+
+	t.Run("timeout", func(t *testing.T) {
+		v, err := New(WithTimeout(5 * time.Second))
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Second, v.httpClient.(*http.Client).Timeout)
+
+		v, err = New()
+		assert.NoError(t, err)
+		assert.Equal(t, defaultHttpTimeout, v.httpClient.(*http.Client).Timeout)
+	})
+}
+
+func TestHeaders(t *testing.T) {
+
+	var headers []http.Header
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		headers = append(headers, req.Header.Clone())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := New(
+		WithHttpClient(httpClient),
+		WithEndpoint("https://example.com/"),
+		WithUserAgent("billing/1.2"),
+		WithHeader("x-api-key", "secret"),
+		WithHeader("X-Tenant", "a"),
+		WithHeader("X-Tenant", "b"),
+	)
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	_, err = v.Status(context.Background())
+	assert.NoError(t, err)
+
+	assert.Len(t, headers, 2)
+	for _, h := range headers {
+		assert.Equal(t, "billing/1.2", h.Get("User-Agent"))
+		assert.Equal(t, "secret", h.Get("X-Api-Key"))
+		assert.Equal(t, []string{"a", "b"}, h.Values("X-Tenant"))
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+
+	v, err := New(WithHttpClient(blockingClient{}), WithEndpoint("https://example.com/"), WithRequestTimeout(10*time.Millisecond))
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = v.CheckEori(context.Background(), "EE123")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The deadline of the caller wins.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = v.Status(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestCountries(t *testing.T) {
+
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	allowed, err := New(WithHttpClient(httpClient), WithAllowedCountries("ee", "de"), WithBlockedCountries("DE"))
+	assert.NoError(t, err)
+	blocked, err := New(WithHttpClient(httpClient), WithBlockedCountries("xi"))
+	assert.NoError(t, err)
+
+	cases := []struct {
+		client *Client
+		vat    string
+		err    string
+	}{
+		{client: allowed, vat: "EE123"},
+		{client: allowed, vat: "DE123", err: "member state not allowed DE"},
+		{client: allowed, vat: "FR123", err: "member state not allowed FR"},
+		{client: blocked, vat: "FR123"},
+		{client: blocked, vat: "xi123", err: "member state not allowed XI"},
+	}
+	for _, tt := range cases {
+		_, err := tt.client.Check(context.Background(), tt.vat)
+		if tt.err == "" {
+			assert.NoError(t, err, tt.vat)
+			continue
+		}
+		assert.ErrorIs(t, err, ErrCountryNotAllowed, tt.vat)
+		assert.EqualError(t, err, tt.err)
+	}
+}
@@ -0,0 +1,261 @@
+package vies
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const fileQueueDeadLetters = "dead-letters"
+
+// FileQueue keeps deferred checks as JSON files in a directory, one file
+// per member state plus one for the dead-letter list, so they survive
+// process restarts.
+type FileQueue struct {
+	dir string
+	mu  sync.Mutex
+}
+
+var _ QueueInterface = (*FileQueue)(nil)
+
+func NewFileQueue(dir string) (*FileQueue, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileQueue{dir: dir}, nil
+}
+
+func (q *FileQueue) Push(check DeferredCheck) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.append(strings.ToUpper(check.Vat[0:2]), check)
+}
+
+func (q *FileQueue) Peek(countryCode string) ([]DeferredCheck, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.load(strings.ToUpper(countryCode))
+}
+
+func (q *FileQueue) Ack(check DeferredCheck) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	name := strings.ToUpper(check.Vat[0:2])
+	checks, err := q.load(name)
+	if err != nil {
+		return err
+	}
+	if i := slices.IndexFunc(checks, func(c DeferredCheck) bool { return sameCheck(c, check) }); i >= 0 {
+		checks = slices.Delete(checks, i, i+1)
+	}
+	if len(checks) == 0 {
+		if err := os.Remove(q.path(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	return q.store(name, checks)
+}
+
+func (q *FileQueue) Update(check DeferredCheck) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	name := strings.ToUpper(check.Vat[0:2])
+	checks, err := q.load(name)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(checks, func(c DeferredCheck) bool { return sameCheck(c, check) })
+	if i < 0 {
+		return nil
+	}
+	checks[i] = check
+	return q.store(name, checks)
+}
+
+func (q *FileQueue) DeadLetter(check DeferredCheck) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.append(fileQueueDeadLetters, check)
+}
+
+func (q *FileQueue) DeadLetters() ([]DeferredCheck, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.load(fileQueueDeadLetters)
+}
+
+func (q *FileQueue) append(name string, check DeferredCheck) error {
+
+	checks, err := q.load(name)
+	if err != nil {
+		return err
+	}
+	return q.store(name, append(checks, check))
+}
+
+func (q *FileQueue) store(name string, checks []DeferredCheck) error {
+
+	content, err := json.Marshal(checks)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(q.dir, q.path(name), content)
+}
+
+func (q *FileQueue) load(name string) ([]DeferredCheck, error) {
+
+	content, err := os.ReadFile(q.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []DeferredCheck
+	if err := json.Unmarshal(content, &checks); err != nil {
+		return nil, fmt.Errorf("corrupted queue file %s: %w", q.path(name), err)
+	}
+	return checks, nil
+}
+
+func (q *FileQueue) path(name string) string {
+	return filepath.Join(q.dir, filepath.Base(name)+".json")
+}
+
+const defaultQueueTable = "vies_queue"
+
+type SQLQueueConfig struct {
+	// Table holding the deferred checks, defaults to vies_queue.
+	Table string
+	// Placeholder returns the placeholder of the n-th query argument,
+	// starting at 1. Defaults to "?", use DollarPlaceholder for PostgreSQL.
+	Placeholder func(n int) string
+}
+
+// DollarPlaceholder numbers query arguments as $1, $2...
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// SQLQueue keeps deferred checks in a database table, see CreateTable for
+// its schema.
+type SQLQueue struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+}
+
+var _ QueueInterface = (*SQLQueue)(nil)
+
+func NewSQLQueue(db *sql.DB, config *SQLQueueConfig) *SQLQueue {
+
+	table := defaultQueueTable
+	placeholder := func(int) string { return "?" }
+
+	if config != nil {
+		if config.Table != "" {
+			table = config.Table
+		}
+		if config.Placeholder != nil {
+			placeholder = config.Placeholder
+		}
+	}
+
+	return &SQLQueue{
+		db:          db,
+		table:       table,
+		placeholder: placeholder,
+	}
+}
+
+// CreateTable creates the queue table unless it exists.
+func (q *SQLQueue) CreateTable(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+q.table+` (
+	vat VARCHAR(32) NOT NULL,
+	country_code CHAR(2) NOT NULL,
+	queued TIMESTAMP NOT NULL,
+	attempts INTEGER NOT NULL,
+	last_error TEXT NOT NULL,
+	dead_letter BOOLEAN NOT NULL
+)`)
+	return err
+}
+
+func (q *SQLQueue) Push(check DeferredCheck) error {
+	return q.insert(check, false)
+}
+
+func (q *SQLQueue) Peek(countryCode string) ([]DeferredCheck, error) {
+	where := " WHERE country_code = " + q.placeholder(1) + " AND dead_letter = " + q.placeholder(2) + " ORDER BY queued"
+	return q.query(q.db, where, strings.ToUpper(countryCode), false)
+}
+
+func (q *SQLQueue) Ack(check DeferredCheck) error {
+	_, err := q.db.Exec(
+		"DELETE FROM "+q.table+" WHERE vat = "+q.placeholder(1)+" AND queued = "+q.placeholder(2)+" AND dead_letter = "+q.placeholder(3),
+		check.Vat, check.Queued, false,
+	)
+	return err
+}
+
+func (q *SQLQueue) Update(check DeferredCheck) error {
+	_, err := q.db.Exec(
+		"UPDATE "+q.table+" SET attempts = "+q.placeholder(1)+", last_error = "+q.placeholder(2)+
+			" WHERE vat = "+q.placeholder(3)+" AND queued = "+q.placeholder(4)+" AND dead_letter = "+q.placeholder(5),
+		check.Attempts, check.LastError, check.Vat, check.Queued, false,
+	)
+	return err
+}
+
+func (q *SQLQueue) DeadLetter(check DeferredCheck) error {
+	return q.insert(check, true)
+}
+
+func (q *SQLQueue) DeadLetters() ([]DeferredCheck, error) {
+	return q.query(q.db, " WHERE dead_letter = "+q.placeholder(1)+" ORDER BY queued", true)
+}
+
+func (q *SQLQueue) insert(check DeferredCheck, deadLetter bool) error {
+
+	placeholders := make([]string, 6)
+	for i := range placeholders {
+		placeholders[i] = q.placeholder(i + 1)
+	}
+
+	_, err := q.db.Exec(
+		"INSERT INTO "+q.table+" (vat, country_code, queued, attempts, last_error, dead_letter) VALUES ("+strings.Join(placeholders, ", ")+")",
+		check.Vat, strings.ToUpper(check.Vat[0:2]), check.Queued, check.Attempts, check.LastError, deadLetter,
+	)
+	return err
+}
+
+func (q *SQLQueue) query(db *sql.DB, where string, args ...any) ([]DeferredCheck, error) {
+
+	rows, err := db.Query("SELECT vat, queued, attempts, last_error FROM "+q.table+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []DeferredCheck
+	for rows.Next() {
+		var check DeferredCheck
+		if err := rows.Scan(&check.Vat, &check.Queued, &check.Attempts, &check.LastError); err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, rows.Err()
+}
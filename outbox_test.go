@@ -0,0 +1,97 @@
+package vies
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileQueue(t *testing.T) {
+	dir := t.TempDir()
+	queued := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	queue, err := NewFileQueue(dir)
+	assert.NoError(t, err)
+	assert.NoError(t, queue.Push(DeferredCheck{Vat: "DE1", Queued: queued, Attempts: 1}))
+	assert.NoError(t, queue.Push(DeferredCheck{Vat: "FR2", Queued: queued, Attempts: 1}))
+	assert.NoError(t, queue.DeadLetter(DeferredCheck{Vat: "DE3", Queued: queued, Attempts: 5, LastError: "down"}))
+
+	// a new queue on the same directory sees the checks of the previous one
+	queue, err = NewFileQueue(dir)
+	assert.NoError(t, err)
+
+	checks, err := queue.Peek("de")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeferredCheck{{Vat: "DE1", Queued: queued, Attempts: 1}}, checks)
+
+	// checks stay queued until acknowledged
+	assert.NoError(t, queue.Update(DeferredCheck{Vat: "DE1", Queued: queued, Attempts: 2, LastError: "down"}))
+	queue, err = NewFileQueue(dir)
+	assert.NoError(t, err)
+	checks, err = queue.Peek("DE")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeferredCheck{{Vat: "DE1", Queued: queued, Attempts: 2, LastError: "down"}}, checks)
+
+	assert.NoError(t, queue.Ack(checks[0]))
+	checks, err = queue.Peek("DE")
+	assert.NoError(t, err)
+	assert.Empty(t, checks)
+
+	checks, err = queue.DeadLetters()
+	assert.NoError(t, err)
+	assert.Equal(t, []DeferredCheck{{Vat: "DE3", Queued: queued, Attempts: 5, LastError: "down"}}, checks)
+
+	checks, err = queue.Peek("FR")
+	assert.NoError(t, err)
+	assert.Len(t, checks, 1)
+}
+
+func TestSQLQueue(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	queued := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	queue := NewSQLQueue(db, &SQLQueueConfig{Table: "deferred", Placeholder: DollarPlaceholder})
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS deferred (")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO deferred (vat, country_code, queued, attempts, last_error, dead_letter) VALUES ($1, $2, $3, $4, $5, $6)")).
+		WithArgs("de1", "DE", queued, 1, "down", false).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO deferred")).
+		WithArgs("DE2", "DE", queued, 5, "down", true).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vat, queued, attempts, last_error FROM deferred WHERE country_code = $1 AND dead_letter = $2 ORDER BY queued")).
+		WithArgs("DE", false).
+		WillReturnRows(sqlmock.NewRows([]string{"vat", "queued", "attempts", "last_error"}).AddRow("de1", queued, 1, "down"))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE deferred SET attempts = $1, last_error = $2 WHERE vat = $3 AND queued = $4 AND dead_letter = $5")).
+		WithArgs(2, "down again", "de1", queued, false).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM deferred WHERE vat = $1 AND queued = $2 AND dead_letter = $3")).
+		WithArgs("de1", queued, false).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT vat, queued, attempts, last_error FROM deferred WHERE dead_letter = $1 ORDER BY queued")).
+		WithArgs(true).
+		WillReturnRows(sqlmock.NewRows([]string{"vat", "queued", "attempts", "last_error"}).AddRow("DE2", queued, 5, "down"))
+
+	assert.NoError(t, queue.CreateTable(context.Background()))
+	assert.NoError(t, queue.Push(DeferredCheck{Vat: "de1", Queued: queued, Attempts: 1, LastError: "down"}))
+	assert.NoError(t, queue.DeadLetter(DeferredCheck{Vat: "DE2", Queued: queued, Attempts: 5, LastError: "down"}))
+
+	checks, err := queue.Peek("de")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeferredCheck{{Vat: "de1", Queued: queued, Attempts: 1, LastError: "down"}}, checks)
+	assert.NoError(t, queue.Update(DeferredCheck{Vat: "de1", Queued: queued, Attempts: 2, LastError: "down again"}))
+	assert.NoError(t, queue.Ack(checks[0]))
+
+	checks, err = queue.DeadLetters()
+	assert.NoError(t, err)
+	assert.Equal(t, []DeferredCheck{{Vat: "DE2", Queued: queued, Attempts: 5, LastError: "down"}}, checks)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
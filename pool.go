@@ -0,0 +1,91 @@
+package vies
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledBuffer is the capacity above which buffers are left to the
+// garbage collector rather than kept in a pool.
+const maxPooledBuffer = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+var bodyPool = sync.Pool{
+	New: func() any { return new(pooledBody) },
+}
+
+// pooledBody is a pooled request body. Transports may close a body after
+// the response is returned and retries read it again through GetBody, so
+// the body goes back to the pool once its owner and every reader released
+// it.
+type pooledBody struct {
+	buf   bytes.Buffer
+	refs  atomic.Int32
+	first pooledReader
+}
+
+func getBody() *pooledBody {
+	body := bodyPool.Get().(*pooledBody)
+	body.refs.Store(1)
+	return body
+}
+
+// open returns the first reader of the body, the Body of the request.
+func (body *pooledBody) open() io.ReadCloser {
+	body.refs.Add(1)
+	body.first.Reset(body.buf.Bytes())
+	body.first.body = body
+	body.first.closed.Store(false)
+	return &body.first
+}
+
+// reader returns a new reader of the body, the GetBody of the request.
+func (body *pooledBody) reader() (io.ReadCloser, error) {
+	body.refs.Add(1)
+	r := &pooledReader{body: body}
+	r.Reset(body.buf.Bytes())
+	return r, nil
+}
+
+// release drops a reference to the body.
+func (body *pooledBody) release() {
+	if body.refs.Add(-1) != 0 {
+		return
+	}
+	if body.buf.Cap() > maxPooledBuffer {
+		return
+	}
+	body.buf.Reset()
+	body.first.Reset(nil)
+	bodyPool.Put(body)
+}
+
+type pooledReader struct {
+	bytes.Reader
+	body   *pooledBody
+	closed atomic.Bool
+}
+
+func (r *pooledReader) Close() error {
+	if r.closed.CompareAndSwap(false, true) {
+		r.body.release()
+	}
+	return nil
+}
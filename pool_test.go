@@ -0,0 +1,59 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPooledBody(t *testing.T) {
+
+	body := getBody()
+	body.buf.WriteString(`{"a":1}`)
+
+	first := body.open()
+	content, _ := io.ReadAll(first)
+	assert.Equal(t, `{"a":1}`, string(content))
+	assert.NoError(t, first.Close())
+	assert.NoError(t, first.Close())
+	assert.Equal(t, int32(1), body.refs.Load())
+
+	// a retry reads the body again after the first reader was closed
+	second, _ := body.reader()
+	body.release()
+	content, _ = io.ReadAll(second)
+	assert.Equal(t, `{"a":1}`, string(content))
+	assert.Equal(t, `{"a":1}`, body.buf.String())
+
+	assert.NoError(t, second.Close())
+	assert.Equal(t, int32(0), body.refs.Load())
+	assert.Zero(t, body.buf.Len())
+}
+
+func BenchmarkCheck(b *testing.B) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true,"name":"Acme","address":"Tallinn"}`)),
+			Header:     make(http.Header),
+		}
+	})
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for range b.N {
+		if _, err := v.Check(context.Background(), "EE123"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
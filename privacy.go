@@ -0,0 +1,66 @@
+package vies
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// privacy holds the key of the privacy mode, nil when it is off.
+var privacy atomic.Pointer[[]byte]
+
+// EnablePrivacyMode makes the package, and the clients of every
+// registry, report VAT numbers as their PseudonymizeVat in logs, error
+// strings, traces and debug dumps, which also drop trader names and
+// addresses. Metric labels only ever hold country codes.
+//
+// hashKey keys the hash of the pseudonyms, so that they can't be reversed
+// by hashing every possible number; keep it constant for pseudonyms to stay
+// the same across restarts.
+func EnablePrivacyMode(hashKey []byte) {
+	key := append([]byte{}, hashKey...)
+	privacy.Store(&key)
+}
+
+// DisablePrivacyMode turns the privacy mode off.
+func DisablePrivacyMode() {
+	privacy.Store(nil)
+}
+
+// PrivacyMode reports whether the privacy mode is on.
+func PrivacyMode() bool {
+	return privacy.Load() != nil
+}
+
+// PseudonymizeVat returns the country code of a VAT number followed by a
+// stable hash of its number, such as "DE#5e1c4a0b9f2d", keyed with the
+// key of EnablePrivacyMode.
+func PseudonymizeVat(vat string) string {
+	key := vatKey(vat)
+	if len(key) < 2 || !isLetters(key[:2]) {
+		return "#" + pseudonym(key)
+	}
+	return key[:2] + "#" + pseudonym(key[2:])
+}
+
+// PrivateVat returns vat as the package reports it: unchanged, or its
+// PseudonymizeVat in privacy mode. Registries outside VIES use it in their
+// errors.
+func PrivateVat(vat string) string {
+	if PrivacyMode() {
+		return PseudonymizeVat(vat)
+	}
+	return vat
+}
+
+// pseudonym returns 12 hexadecimal characters of the keyed hash of s.
+func pseudonym(s string) string {
+	var key []byte
+	if k := privacy.Load(); k != nil {
+		key = *k
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil)[:6])
+}
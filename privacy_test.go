@@ -0,0 +1,74 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPseudonymizeVat(t *testing.T) {
+
+	EnablePrivacyMode([]byte("key"))
+	t.Cleanup(DisablePrivacyMode)
+
+	pseudonym := PseudonymizeVat("DE123456789")
+	assert.Regexp(t, `^DE#[0-9a-f]{12}$`, pseudonym)
+	assert.Equal(t, pseudonym, PseudonymizeVat("de 123 456 789"))
+	assert.Equal(t, pseudonym, PrivateVat("DE123456789"))
+	assert.NotEqual(t, pseudonym, PseudonymizeVat("DE123456788"))
+	assert.Regexp(t, `^#[0-9a-f]{12}$`, PseudonymizeVat("1"))
+
+	EnablePrivacyMode([]byte("other"))
+	assert.NotEqual(t, pseudonym, PseudonymizeVat("DE123456789"))
+
+	DisablePrivacyMode()
+	assert.False(t, PrivacyMode())
+	assert.Equal(t, "DE123456789", PrivateVat("DE123456789"))
+}
+
+func TestPrivacyMode(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"100354546","valid":true,"name":"ACME \"OU\"","address":"TALLINN"}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	var logs, debug bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Logger: logger, Debug: &debug, Requester: "DE123456789"})
+	assert.NoError(t, err)
+
+	EnablePrivacyMode([]byte("key"))
+	t.Cleanup(DisablePrivacyMode)
+
+	_, err = v.Check(context.Background(), "EE100354546")
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "EE1")
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "1234")
+	assert.EqualError(t, err, "invalid VAT provided "+PseudonymizeVat("1234"))
+
+	assert.NotContains(t, logs.String(), "100354546")
+	assert.Contains(t, logs.String(), "vat="+PseudonymizeVat("EE100354546"))
+
+	dump := debug.String()
+	assert.NotContains(t, dump, "100354546")
+	assert.NotContains(t, dump, "ACME")
+	assert.NotContains(t, dump, "TALLINN")
+	// numbers are shown as their pseudonym, for correlation with the logs
+	assert.Contains(t, dump, `"vatNumber":"`+PseudonymizeVat("EE100354546")+`"`)
+	assert.NotContains(t, dump, "123456789")
+	assert.Contains(t, dump, `"requesterNumber":"`+PseudonymizeVat("DE123456789")+`"`)
+	assert.Contains(t, dump, `"name":"***"`)
+	assert.Contains(t, dump, `"countryCode":"EE"`)
+}
@@ -0,0 +1,142 @@
+package vies
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidProof is returned by VerifyProof for results without a proof
+// or whose fields don't match their proof.
+var ErrInvalidProof = errors.New("invalid proof")
+
+const (
+	ProofHmacSha256 = "HMAC-SHA256"
+	ProofEd25519    = "Ed25519"
+)
+
+// proofVersion prefixes the signed message, so that its layout can change.
+const proofVersion = "vies-proof-v1"
+
+// Proof is a signature over the fields of a CheckResult which show what
+// VIES answered: the country code, number, validity, name, address,
+// request date and consultation number, with the time of signing.
+type Proof struct {
+	Algorithm string    `json:"algorithm"`
+	SignedAt  time.Time `json:"signedAt"`
+	Signature []byte    `json:"signature"`
+}
+
+// ProofSignerInterface signs the results of Check, see HmacSigner and
+// Ed25519Signer.
+type ProofSignerInterface interface {
+	Algorithm() string
+	Sign(message []byte) ([]byte, error)
+}
+
+type hmacSigner []byte
+
+// HmacSigner signs results with the HMAC-SHA256 of key, which verifies
+// them too.
+func HmacSigner(key []byte) ProofSignerInterface {
+	return hmacSigner(key)
+}
+
+func (signer hmacSigner) Algorithm() string {
+	return ProofHmacSha256
+}
+
+func (signer hmacSigner) Sign(message []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, signer)
+	mac.Write(message)
+	return mac.Sum(nil), nil
+}
+
+type ed25519Signer ed25519.PrivateKey
+
+// Ed25519Signer signs results with key, the proofs being verified with
+// its public key.
+func Ed25519Signer(key ed25519.PrivateKey) ProofSignerInterface {
+	return ed25519Signer(key)
+}
+
+func (signer ed25519Signer) Algorithm() string {
+	return ProofEd25519
+}
+
+func (signer ed25519Signer) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(ed25519.PrivateKey(signer), message), nil
+}
+
+// SignResult sets the proof of result, signed by signer. The signing time
+// is result.CheckedAt when set, now otherwise.
+func SignResult(result *CheckResult, signer ProofSignerInterface, now time.Time) error {
+
+	signedAt := now.UTC().Truncate(time.Second)
+	if result.CheckedAt != nil {
+		signedAt = *result.CheckedAt
+	}
+
+	algorithm := signer.Algorithm()
+	signature, err := signer.Sign(proofMessage(result, algorithm, signedAt))
+	if err != nil {
+		return err
+	}
+	result.Proof = &Proof{Algorithm: algorithm, SignedAt: signedAt, Signature: signature}
+	return nil
+}
+
+// VerifyProof checks that the signed fields of result haven't changed
+// since its proof was made. key is the []byte given to HmacSigner or the
+// ed25519.PublicKey of the key given to Ed25519Signer.
+func VerifyProof(result *CheckResult, key any) error {
+
+	proof := result.Proof
+	if proof == nil {
+		return fmt.Errorf("%w: no proof", ErrInvalidProof)
+	}
+	message := proofMessage(result, proof.Algorithm, proof.SignedAt)
+
+	switch key := key.(type) {
+	case []byte:
+		if proof.Algorithm != ProofHmacSha256 {
+			return fmt.Errorf("%w: %s proof for an HMAC key", ErrInvalidProof, proof.Algorithm)
+		}
+		expected, _ := hmacSigner(key).Sign(message)
+		if !hmac.Equal(expected, proof.Signature) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidProof)
+		}
+	case ed25519.PublicKey:
+		if proof.Algorithm != ProofEd25519 {
+			return fmt.Errorf("%w: %s proof for an Ed25519 key", ErrInvalidProof, proof.Algorithm)
+		}
+		if !ed25519.Verify(key, message, proof.Signature) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidProof)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported key %T", ErrInvalidProof, key)
+	}
+	return nil
+}
+
+// proofMessage encodes the signed fields of result as a JSON array, which
+// leaves no ambiguity between fields.
+func proofMessage(result *CheckResult, algorithm string, signedAt time.Time) []byte {
+	message, _ := json.Marshal([]any{
+		proofVersion,
+		algorithm,
+		result.CountryCode,
+		result.VatNumber,
+		result.Valid,
+		result.Name,
+		result.Address,
+		result.RequestDate,
+		result.RequestIdentifier,
+		signedAt.UTC().Format(time.RFC3339Nano),
+	})
+	return message
+}
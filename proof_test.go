@@ -0,0 +1,72 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProof(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true,"name":"ACME OU","requestIdentifier":"WAPIAAAAYx"}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	key := []byte("secret")
+	v, err := New(WithHttpClient(client), WithProofSigner(HmacSigner(key)))
+	assert.NoError(t, err)
+
+	result, err := v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.Equal(t, ProofHmacSha256, result.Proof.Algorithm)
+	assert.NoError(t, VerifyProof(result, key))
+	assert.ErrorIs(t, VerifyProof(result, []byte("other")), ErrInvalidProof)
+
+	// the proof survives a JSON round trip
+	data, err := json.Marshal(result)
+	assert.NoError(t, err)
+	var stored CheckResult
+	assert.NoError(t, json.Unmarshal(data, &stored))
+	assert.NoError(t, VerifyProof(&stored, key))
+
+	stored.Valid = false
+	assert.ErrorIs(t, VerifyProof(&stored, key), ErrInvalidProof)
+
+	plain, err := New(WithHttpClient(client))
+	assert.NoError(t, err)
+	result, err = plain.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.Nil(t, result.Proof)
+	assert.ErrorIs(t, VerifyProof(result, key), ErrInvalidProof)
+}
+
+func TestProofEd25519(t *testing.T) {
+
+	public, private, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	checkedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	result := &CheckResult{CountryCode: "DE", VatNumber: "123456789", Valid: true, CheckedAt: &checkedAt}
+	assert.NoError(t, SignResult(result, Ed25519Signer(private), time.Now()))
+	assert.Equal(t, ProofEd25519, result.Proof.Algorithm)
+	assert.Equal(t, checkedAt, result.Proof.SignedAt)
+	assert.NoError(t, VerifyProof(result, public))
+
+	// an HMAC key doesn't verify an Ed25519 proof
+	assert.ErrorIs(t, VerifyProof(result, []byte("secret")), ErrInvalidProof)
+	assert.ErrorIs(t, VerifyProof(result, "secret"), ErrInvalidProof)
+
+	result.Proof.SignedAt = result.Proof.SignedAt.Add(time.Hour)
+	assert.ErrorIs(t, VerifyProof(result, public), ErrInvalidProof)
+}
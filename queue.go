@@ -0,0 +1,318 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueued is returned by Forwarder.Check when the check was queued until
+// its member state becomes available again.
+var ErrQueued = errors.New("check queued until the member state is available")
+
+// ErrDeadLetter is passed to ForwarderConfig.OnResult for checks given up
+// after ForwarderConfig.MaxAttempts.
+var ErrDeadLetter = errors.New("check moved to the dead-letter list")
+
+// DeferredCheck is a check waiting for its member state to come back.
+type DeferredCheck struct {
+	Vat      string    `json:"vat"`
+	Queued   time.Time `json:"queued"`
+	Attempts int       `json:"attempts"`
+	// LastError is the error of the last attempt.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// QueueInterface stores deferred checks per member state, and the checks
+// given up after too many attempts in a dead-letter list. A queued check is
+// identified by its VAT number and Queued time, it stays queued until
+// acknowledged so that a crash during a flush doesn't lose it.
+type QueueInterface interface {
+	Push(check DeferredCheck) error
+	// Peek returns the checks queued for the member state, oldest first,
+	// without removing them.
+	Peek(countryCode string) ([]DeferredCheck, error)
+	// Ack removes a check once its outcome is final.
+	Ack(check DeferredCheck) error
+	// Update records the Attempts and LastError of a queued check.
+	Update(check DeferredCheck) error
+	DeadLetter(check DeferredCheck) error
+	DeadLetters() ([]DeferredCheck, error)
+}
+
+// sameCheck reports whether a and b are the same queued check.
+func sameCheck(a, b DeferredCheck) bool {
+	return a.Vat == b.Vat && a.Queued.Equal(b.Queued)
+}
+
+// MemoryQueue keeps deferred checks in process memory.
+type MemoryQueue struct {
+	mu          sync.Mutex
+	checks      map[string][]DeferredCheck
+	deadLetters []DeferredCheck
+}
+
+var _ QueueInterface = (*MemoryQueue)(nil)
+
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{checks: make(map[string][]DeferredCheck)}
+}
+
+func (q *MemoryQueue) Push(check DeferredCheck) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	countryCode := strings.ToUpper(check.Vat[0:2])
+	q.checks[countryCode] = append(q.checks[countryCode], check)
+	return nil
+}
+
+func (q *MemoryQueue) Peek(countryCode string) ([]DeferredCheck, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return slices.Clone(q.checks[strings.ToUpper(countryCode)]), nil
+}
+
+func (q *MemoryQueue) Ack(check DeferredCheck) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	countryCode := strings.ToUpper(check.Vat[0:2])
+	checks := q.checks[countryCode]
+	if i := slices.IndexFunc(checks, func(c DeferredCheck) bool { return sameCheck(c, check) }); i >= 0 {
+		checks = slices.Delete(checks, i, i+1)
+	}
+	if len(checks) == 0 {
+		delete(q.checks, countryCode)
+	} else {
+		q.checks[countryCode] = checks
+	}
+	return nil
+}
+
+func (q *MemoryQueue) Update(check DeferredCheck) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	checks := q.checks[strings.ToUpper(check.Vat[0:2])]
+	if i := slices.IndexFunc(checks, func(c DeferredCheck) bool { return sameCheck(c, check) }); i >= 0 {
+		checks[i] = check
+	}
+	return nil
+}
+
+func (q *MemoryQueue) DeadLetter(check DeferredCheck) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.deadLetters = append(q.deadLetters, check)
+	return nil
+}
+
+func (q *MemoryQueue) DeadLetters() ([]DeferredCheck, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return slices.Clone(q.deadLetters), nil
+}
+
+// CheckResultFunc receives the outcome of a deferred check.
+type CheckResultFunc func(vat string, result *CheckResult, err error)
+
+type ForwarderConfig struct {
+	// Queue stores the deferred checks, defaults to a MemoryQueue.
+	Queue QueueInterface
+	// OnResult is called with the outcome of every deferred check,
+	// including those moved to the dead-letter list.
+	OnResult CheckResultFunc
+	// MaxAttempts moves a check to the dead-letter list once it has been
+	// attempted that many times, zero means no limit.
+	MaxAttempts int
+	// OnError receives the errors of the flushes started by the watcher.
+	OnError func(countryCode string, err error)
+}
+
+// Forwarder queues checks of member states which are down and runs them
+// once the Watcher reports the member state available again.
+type Forwarder struct {
+	client      *Client
+	queue       QueueInterface
+	onResult    CheckResultFunc
+	onError     func(countryCode string, err error)
+	maxAttempts int
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	flushing map[string]bool
+}
+
+// NewForwarder returns a Forwarder flushing the queue of a member state
+// when the watcher sees it available: at its first poll, which replays the
+// checks queued before a restart, then whenever it comes back. Flushes run
+// on their own goroutines until Close.
+func NewForwarder(client *Client, watcher *Watcher, config *ForwarderConfig) *Forwarder {
+
+	var queue QueueInterface
+	var onResult CheckResultFunc
+	var onError func(string, error)
+	var maxAttempts int
+
+	if config != nil {
+		queue = config.Queue
+		onResult = config.OnResult
+		onError = config.OnError
+		maxAttempts = config.MaxAttempts
+	}
+	if queue == nil {
+		queue = NewMemoryQueue()
+	}
+	if onResult == nil {
+		onResult = func(string, *CheckResult, error) {}
+	}
+
+	if onError == nil {
+		onError = func(string, error) {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	f := &Forwarder{
+		client:      client,
+		queue:       queue,
+		onResult:    onResult,
+		onError:     onError,
+		maxAttempts: maxAttempts,
+		ctx:         ctx,
+		cancel:      cancel,
+		flushing:    make(map[string]bool),
+	}
+	if watcher != nil {
+		watcher.OnPoll(func(previous, current *Status) {
+			for _, countryCode := range current.AvailableCountries() {
+				if previous != nil {
+					if availability, _ := previous.Country(countryCode); availability == AvailabilityAvailable {
+						continue
+					}
+				}
+				f.startFlush(countryCode)
+			}
+		})
+	}
+	return f
+}
+
+// Close stops the flushes started by the watcher and waits for them to
+// return. The checks they didn't run stay queued.
+func (f *Forwarder) Close() {
+	f.cancel()
+	f.wg.Wait()
+}
+
+// startFlush flushes the queue of the member state in the background,
+// unless a flush of it is already running.
+func (f *Forwarder) startFlush(countryCode string) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.flushing[countryCode] || f.ctx.Err() != nil {
+		return
+	}
+	f.flushing[countryCode] = true
+
+	f.wg.Add(1)
+	go func() {
+		defer f.wg.Done()
+		err := f.Flush(f.ctx, countryCode)
+
+		f.mu.Lock()
+		delete(f.flushing, countryCode)
+		f.mu.Unlock()
+
+		if err != nil && f.ctx.Err() == nil {
+			f.onError(countryCode, err)
+		}
+	}()
+}
+
+// Check runs the check, or queues it and returns ErrQueued when the member
+// state is unavailable. The VAT number is queued cleaned and normalized,
+// opts are not kept: the deferred check runs with the settings of the
+// client.
+func (f *Forwarder) Check(ctx context.Context, vat string, opts ...CheckOption) (*CheckResult, error) {
+
+	result, err := f.client.Check(ctx, vat, opts...)
+	if !isCountryUnavailable(err) {
+		return result, err
+	}
+
+	check := DeferredCheck{
+		Vat:       vatKey(vat),
+		Queued:    f.client.clock.Now(),
+		Attempts:  1,
+		LastError: err.Error(),
+	}
+	if err := f.queue.Push(check); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: %w", ErrQueued, err)
+}
+
+// DeadLetters returns the checks given up after MaxAttempts.
+func (f *Forwarder) DeadLetters() ([]DeferredCheck, error) {
+	return f.queue.DeadLetters()
+}
+
+// Flush runs the checks queued for the member state and passes their
+// outcome to OnResult. A check leaves the queue once its outcome is final:
+// a check hitting an unavailable member state again, or timing out, stays
+// queued with one more attempt, or moves to the dead-letter list after
+// MaxAttempts, and the rest waits for the next flush. When ctx is done the
+// check running stays queued as it was.
+func (f *Forwarder) Flush(ctx context.Context, countryCode string) error {
+
+	checks, err := f.queue.Peek(countryCode)
+	if err != nil {
+		return err
+	}
+
+	for _, check := range checks {
+		result, err := f.client.Check(ctx, check.Vat)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !isCountryUnavailable(err) && !errors.Is(err, context.DeadlineExceeded) {
+			f.onResult(check.Vat, result, err)
+			if err := f.queue.Ack(check); err != nil {
+				return err
+			}
+			continue
+		}
+
+		check.Attempts++
+		check.LastError = err.Error()
+		if f.maxAttempts > 0 && check.Attempts >= f.maxAttempts {
+			if err := f.queue.DeadLetter(check); err != nil {
+				return err
+			}
+			if err := f.queue.Ack(check); err != nil {
+				return err
+			}
+			f.onResult(check.Vat, nil, fmt.Errorf("%w: %w", ErrDeadLetter, err))
+			return nil
+		}
+		// the member state is down again, keep the rest for the next flush
+		return f.queue.Update(check)
+	}
+	return nil
+}
+
+func isCountryUnavailable(err error) bool {
+	var apiErr *ApiError
+	return errors.Is(err, ErrCountryUnavailable) || (errors.As(err, &apiErr) && apiErr.Err == "MS_UNAVAILABLE")
+}
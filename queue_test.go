@@ -0,0 +1,262 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwarder(t *testing.T) {
+
+	down := true
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, code := `{"countryCode":"DE","vatNumber":"123456789","valid":true}`, http.StatusOK
+		switch {
+		case req.URL.Path == "/check-status" && down:
+			body = `{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Unavailable"}]}`
+		case req.URL.Path == "/check-status":
+			body = `{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"}]}`
+		case down:
+			body, code = `{"errorWrappers":[{"error":"MS_UNAVAILABLE","message":"down"}]}`, http.StatusInternalServerError
+		}
+		return &http.Response{
+			StatusCode: code,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	clock := newTestClock()
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Clock: clock})
+	assert.NoError(t, err)
+
+	var results []string
+	queue := NewMemoryQueue()
+	watcher := NewWatcher(v, nil)
+	forwarder := NewForwarder(v, watcher, &ForwarderConfig{
+		Queue: queue,
+		OnResult: func(vat string, result *CheckResult, err error) {
+			assert.NoError(t, err)
+			results = append(results, result.Vat)
+		},
+	})
+
+	ctx := context.Background()
+
+	_, err = forwarder.Check(ctx, "de123456789")
+	assert.ErrorIs(t, err, ErrQueued)
+	assert.Len(t, queue.checks["DE"], 1)
+	assert.Equal(t, clock.Now(), queue.checks["DE"][0].Queued)
+
+	// still down, the check goes back to the queue
+	assert.NoError(t, forwarder.Flush(ctx, "DE"))
+	assert.Empty(t, results)
+	assert.Len(t, queue.checks["DE"], 1)
+
+	watcher.poll(ctx)
+	down = false
+	watcher.poll(ctx)
+	forwarder.wg.Wait()
+
+	assert.Equal(t, []string{"DE123456789"}, results)
+	assert.Empty(t, queue.checks["DE"])
+
+	result, err := forwarder.Check(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+
+	// formatted input is queued under its member state
+	down = true
+	watcher.poll(ctx)
+	_, err = forwarder.Check(ctx, " \u200bde 123.456.789")
+	assert.ErrorIs(t, err, ErrQueued)
+	assert.Equal(t, "DE123456789", queue.checks["DE"][0].Vat)
+	down = false
+	watcher.poll(ctx)
+	forwarder.wg.Wait()
+	assert.Equal(t, []string{"DE123456789", "DE123456789"}, results)
+	assert.Empty(t, queue.checks)
+}
+
+func TestMemoryQueue(t *testing.T) {
+	queue := NewMemoryQueue()
+	assert.NoError(t, queue.Push(DeferredCheck{Vat: "DE1"}))
+	assert.NoError(t, queue.Push(DeferredCheck{Vat: "FR2"}))
+	assert.NoError(t, queue.Push(DeferredCheck{Vat: "de3"}))
+
+	checks, err := queue.Peek("de")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeferredCheck{{Vat: "DE1"}, {Vat: "de3"}}, checks)
+
+	assert.NoError(t, queue.Update(DeferredCheck{Vat: "de3", Attempts: 2, LastError: "down"}))
+	assert.NoError(t, queue.Ack(DeferredCheck{Vat: "DE1"}))
+	checks, err = queue.Peek("DE")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeferredCheck{{Vat: "de3", Attempts: 2, LastError: "down"}}, checks)
+
+	assert.NoError(t, queue.Ack(checks[0]))
+	checks, err = queue.Peek("DE")
+	assert.NoError(t, err)
+	assert.Empty(t, checks)
+	assert.Len(t, queue.checks, 1)
+}
+
+func TestForwarderReplay(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body := `{"countryCode":"DE","vatNumber":"123456789","valid":true}`
+		if req.URL.Path == "/check-status" {
+			body = `{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"},{"countryCode":"FR","availability":"Available"}]}`
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+	assert.NoError(t, err)
+
+	// checks queued before a restart run at the first poll
+	queue, err := NewFileQueue(t.TempDir())
+	assert.NoError(t, err)
+	assert.NoError(t, queue.Push(DeferredCheck{Vat: "DE123456789", Attempts: 1}))
+
+	var results []string
+	var errs []string
+	watcher := NewWatcher(v, nil)
+	forwarder := NewForwarder(v, watcher, &ForwarderConfig{
+		Queue: queue,
+		OnResult: func(vat string, result *CheckResult, err error) {
+			assert.NoError(t, err)
+			results = append(results, vat)
+		},
+		OnError: func(countryCode string, err error) {
+			errs = append(errs, countryCode+": "+err.Error())
+		},
+	})
+	defer forwarder.Close()
+
+	ctx := context.Background()
+	watcher.poll(ctx)
+	forwarder.wg.Wait()
+	assert.Equal(t, []string{"DE123456789"}, results)
+	assert.Empty(t, errs)
+	checks, err := queue.Peek("DE")
+	assert.NoError(t, err)
+	assert.Empty(t, checks)
+
+	// countries still available aren't flushed again
+	assert.NoError(t, queue.Push(DeferredCheck{Vat: "DE123456789", Attempts: 1}))
+	watcher.poll(ctx)
+	forwarder.wg.Wait()
+	assert.Len(t, results, 1)
+
+	// errors of the flushes are reported
+	var mu sync.Mutex
+	watcher = NewWatcher(v, nil)
+	failing := NewForwarder(v, watcher, &ForwarderConfig{
+		Queue: failingQueue{NewMemoryQueue()},
+		OnError: func(countryCode string, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			errs = append(errs, countryCode+": "+err.Error())
+		},
+	})
+	defer failing.Close()
+	watcher.poll(ctx)
+	failing.wg.Wait()
+	assert.ElementsMatch(t, []string{"DE: queue down", "FR: queue down"}, errs)
+}
+
+type failingQueue struct {
+	*MemoryQueue
+}
+
+func (failingQueue) Peek(string) ([]DeferredCheck, error) {
+	return nil, errors.New("queue down")
+}
+
+func TestForwarderCanceled(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		cancel()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"DE","vatNumber":"123456789","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+	assert.NoError(t, err)
+
+	queue := NewMemoryQueue()
+	check := DeferredCheck{Vat: "DE123456789", Attempts: 1}
+	assert.NoError(t, queue.Push(check))
+
+	var results int
+	forwarder := NewForwarder(v, nil, &ForwarderConfig{
+		Queue: queue,
+		OnResult: func(vat string, result *CheckResult, err error) {
+			results++
+		},
+	})
+
+	// a check interrupted by the context stays queued as it was
+	assert.ErrorIs(t, forwarder.Flush(ctx, "DE"), context.Canceled)
+	assert.Zero(t, results)
+	checks, err := queue.Peek("DE")
+	assert.NoError(t, err)
+	assert.Equal(t, []DeferredCheck{check}, checks)
+}
+
+func TestForwarderDeadLetter(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"errorWrappers":[{"error":"MS_UNAVAILABLE","message":"down"}]}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+	assert.NoError(t, err)
+
+	var results []error
+	forwarder := NewForwarder(v, nil, &ForwarderConfig{
+		MaxAttempts: 3,
+		OnResult: func(vat string, result *CheckResult, err error) {
+			results = append(results, err)
+		},
+	})
+
+	ctx := context.Background()
+	_, err = forwarder.Check(ctx, "DE123456789")
+	assert.ErrorIs(t, err, ErrQueued)
+	_, err = forwarder.Check(ctx, "DE987654321")
+	assert.ErrorIs(t, err, ErrQueued)
+
+	// every flush attempts the first queued check only
+	assert.NoError(t, forwarder.Flush(ctx, "DE"))
+	assert.Empty(t, results)
+	assert.NoError(t, forwarder.Flush(ctx, "DE"))
+	assert.Len(t, results, 1)
+	assert.ErrorIs(t, results[0], ErrDeadLetter)
+
+	deadLetters, err := forwarder.DeadLetters()
+	assert.NoError(t, err)
+	assert.Len(t, deadLetters, 1)
+	assert.Equal(t, "DE123456789", deadLetters[0].Vat)
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Equal(t, "MS_UNAVAILABLE: down", deadLetters[0].LastError)
+}
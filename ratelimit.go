@@ -0,0 +1,80 @@
+package vies
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiterInterface throttles requests sent to VIES. Wait blocks until a
+// request may be sent or the context is done.
+type RateLimiterInterface interface {
+	Wait(ctx context.Context) error
+}
+
+// rateLimitError is an error of RateLimiterInterface.Wait, never retried.
+type rateLimitError struct {
+	err error
+}
+
+func (e *rateLimitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rateLimitError) Unwrap() error {
+	return e.err
+}
+
+// RateLimiter allows a steady rate of requests per second with bursts of up
+// to burst requests.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	window   time.Duration
+	next     time.Time
+	clock    ClockInterface
+}
+
+// NewRateLimiter returns a RateLimiter of perSecond requests per second,
+// which doesn't limit requests when perSecond is not positive.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	var interval time.Duration
+	if perSecond > 0 {
+		interval = time.Duration(float64(time.Second) / perSecond)
+	}
+	return &RateLimiter{
+		interval: interval,
+		window:   interval * time.Duration(burst-1),
+		clock:    SystemClock{},
+	}
+}
+
+// SetClock replaces the system clock spacing the requests.
+func (l *RateLimiter) SetClock(clock ClockInterface) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = clockOrSystem(clock)
+}
+
+func (l *RateLimiter) Wait(ctx context.Context) error {
+
+	l.mu.Lock()
+	now := l.clock.Now()
+	clock := l.clock
+	if earliest := now.Add(-l.window); l.next.Before(earliest) {
+		l.next = earliest
+	}
+	at := l.next
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := at.Sub(now)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	return sleepUntil(ctx, clock, at)
+}
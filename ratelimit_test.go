@@ -0,0 +1,53 @@
+package vies
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Run("no rate is unlimited", func(t *testing.T) {
+		for _, rate := range []float64{0, -1} {
+			l := NewRateLimiter(rate, 1)
+			start := time.Now()
+			for range 100 {
+				assert.NoError(t, l.Wait(context.Background()))
+			}
+			assert.Less(t, time.Since(start), 50*time.Millisecond)
+		}
+	})
+
+	t.Run("burst then steady rate", func(t *testing.T) {
+		l := NewRateLimiter(20, 2)
+
+		start := time.Now()
+		for range 4 {
+			assert.NoError(t, l.Wait(context.Background()))
+		}
+		elapsed := time.Since(start)
+		assert.GreaterOrEqual(t, elapsed, 90*time.Millisecond)
+		assert.Less(t, elapsed, 500*time.Millisecond)
+	})
+
+	t.Run("clock", func(t *testing.T) {
+		clock := newTestClock()
+		l := NewRateLimiter(2, 1)
+		l.SetClock(clock)
+		for range 3 {
+			assert.NoError(t, l.Wait(context.Background()))
+		}
+		assert.Equal(t, []time.Duration{500 * time.Millisecond, 500 * time.Millisecond}, clock.Waits())
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		l := NewRateLimiter(1, 1)
+		assert.NoError(t, l.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,75 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Reason tells why ValidDetailed considers a VAT number valid or not.
+type Reason string
+
+const (
+	// ReasonValid is the reason of numbers registered in VIES, under the
+	// expected name and address if any.
+	ReasonValid Reason = "valid"
+	// ReasonFormatInvalid is the reason of numbers rejected for their
+	// format, by the client or by VIES.
+	ReasonFormatInvalid Reason = "format_invalid"
+	// ReasonNotRegistered is the reason of numbers VIES reports invalid.
+	ReasonNotRegistered Reason = "not_registered"
+	// ReasonMismatch is the reason of numbers registered under another
+	// name or address than those given with ExpectTrader.
+	ReasonMismatch Reason = "mismatch"
+	// ReasonUnverified is the reason of numbers which could not be
+	// checked, e.g. when the member state is unavailable. The error of
+	// the check is returned with it.
+	ReasonUnverified Reason = "unverified"
+)
+
+// traderMatchThreshold is the similarity above which the name or address
+// of a trader is taken as the expected one.
+const traderMatchThreshold = 0.8
+
+// ValidDetailed checks a VAT number like Valid, and tells why it is valid
+// or not. valid reports whether the number is registered, a registered
+// number whose name or address differs from those given with ExpectTrader
+// being valid with ReasonMismatch. Names and addresses the member state
+// doesn't disclose match any.
+//
+// Only checks which could not be made return an error, with
+// ReasonUnverified.
+func (client *Client) ValidDetailed(ctx context.Context, vat string, opts ...CheckOption) (bool, Reason, error) {
+
+	result, err := client.Check(ctx, vat, opts...)
+	var inputErr *ErrInvalidInput
+	var apiErr *ApiError
+	switch {
+	case errors.As(err, &inputErr):
+		return false, ReasonFormatInvalid, nil
+	case errors.As(err, &apiErr) && apiErr.Err == "INVALID_INPUT":
+		return false, ReasonFormatInvalid, nil
+	case err != nil:
+		return false, ReasonUnverified, err
+	case !result.Valid:
+		return false, ReasonNotRegistered, nil
+	}
+
+	options := newCheckOptions(opts)
+	if !traderMatches(result.Name, options.name) || !traderMatches(result.Address, options.address) {
+		return true, ReasonMismatch, nil
+	}
+	return true, ReasonValid, nil
+}
+
+// traderMatches compares the name or address VIES returned with the
+// expected one, ignoring case, diacritics and punctuation. Empty and
+// undisclosed values match.
+func traderMatches(got, expected string) bool {
+	a, b := nameTokens(got), nameTokens(expected)
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	return similarity(strings.Join(a, " "), strings.Join(b, " ")) >= traderMatchThreshold ||
+		NameSimilarity(got, expected) >= traderMatchThreshold
+}
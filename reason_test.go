@@ -0,0 +1,53 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidDetailed(t *testing.T) {
+
+	client, err := New(
+		WithPreflightFormat(),
+		WithFakeResponses(map[string]CheckResult{
+			"DE123456789": {Valid: true, Name: "ACME GmbH", Address: "Hauptstraße 1\n10115 Berlin"},
+			"ES12345678Z": {Valid: true, Name: "---", Address: "---"},
+		}),
+		WithFakeErrors(map[string]error{
+			"IT":            &ApiError{Err: "MS_UNAVAILABLE"},
+			"FR12345678901": &ApiError{Err: "INVALID_INPUT"},
+		}),
+	)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		vat    string
+		opts   []CheckOption
+		valid  bool
+		reason Reason
+	}{
+		{"DE123456789", nil, true, ReasonValid},
+		{"DE123456789", []CheckOption{ExpectTrader("Acme", "Hauptstrasse 1, 10115 Berlin")}, true, ReasonValid},
+		{"DE123456789", []CheckOption{ExpectTrader("Globex Corporation", "")}, true, ReasonMismatch},
+		{"DE123456789", []CheckOption{ExpectTrader("ACME", "Marienplatz 8, 80331 München")}, true, ReasonMismatch},
+		{"ES12345678Z", []CheckOption{ExpectTrader("Globex", "Madrid")}, true, ReasonValid},
+		{"NL123456789B01", nil, false, ReasonNotRegistered},
+		{"DE12345", nil, false, ReasonFormatInvalid},
+		{"FR12345678901", nil, false, ReasonFormatInvalid},
+	} {
+		valid, reason, err := client.ValidDetailed(ctx, test.vat, test.opts...)
+		assert.NoError(t, err, test.vat)
+		assert.Equal(t, test.valid, valid, test.vat)
+		assert.Equal(t, test.reason, reason, test.vat)
+	}
+
+	valid, reason, err := client.ValidDetailed(ctx, "IT12345678901")
+	assert.False(t, valid)
+	assert.Equal(t, ReasonUnverified, reason)
+	var apiErr *ApiError
+	assert.True(t, errors.As(err, &apiErr))
+}
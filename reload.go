@@ -0,0 +1,186 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const defaultReloadPollInterval = 5 * time.Second
+
+// ConfigProvider returns the current configuration, it is called by
+// WatchConfig on every reload.
+type ConfigProvider func() (*Config, error)
+
+// FileConfig provides the configuration read from path with LoadConfig.
+func FileConfig(path string) ConfigProvider {
+	return func() (*Config, error) {
+		return LoadConfig(path)
+	}
+}
+
+// Reload replaces at once the endpoints, requester, rate limit, allowed
+// and blocked countries and user agent of the client by those of config, and the TTL
+// of a MemoryCache or FileCache. Cached results, the status cache and the
+// HTTP client are kept, checks in flight finish with the previous
+// settings. Timeout, retries and the cache directory need a new client.
+func (client *Client) Reload(config *Config) error {
+
+	current := client.settings.Load()
+	next := *current
+	reloaded := *current.config
+	next.config = &reloaded
+
+	if config.Endpoint != "" {
+		u, err := url.Parse(config.Endpoint)
+		if err != nil {
+			return err
+		}
+		next.endpoint = u
+		reloaded.EndpointUrl = config.Endpoint
+	}
+	if config.EoriEndpoint != "" {
+		u, err := url.Parse(config.EoriEndpoint)
+		if err != nil {
+			return err
+		}
+		next.eoriEndpoint = u
+		reloaded.EoriEndpointUrl = config.EoriEndpoint
+	}
+
+	next.requester = strings.ToUpper(config.Requester)
+	if next.requester != "" {
+		if err := client.isValidVat(next.requester); err != nil {
+			return err
+		}
+	}
+
+	// The limiter is kept when the rate doesn't change so that reloading
+	// doesn't allow a burst.
+	if config.RateLimit != current.rateLimit {
+		next.rateLimit = config.RateLimit
+		next.rateLimiter = nil
+		reloaded.RateLimit, reloaded.RateLimiter = config.RateLimit, nil
+		if config.RateLimit > 0 {
+			limiter := NewRateLimiter(config.RateLimit, 1)
+			limiter.SetClock(client.clock)
+			next.rateLimiter = limiter
+		}
+	}
+
+	next.allowedCountries = countrySet(config.AllowedCountries)
+	next.blockedCountries = countrySet(config.BlockedCountries)
+	next.userAgent = config.UserAgent
+
+	reloaded.Requester = next.requester
+	reloaded.AllowedCountries = config.AllowedCountries
+	reloaded.BlockedCountries = config.BlockedCountries
+	reloaded.UserAgent = config.UserAgent
+
+	if cache, ok := client.cache.(interface{ SetTTL(ttl time.Duration) }); ok && config.CacheTTL > 0 {
+		cache.SetTTL(time.Duration(config.CacheTTL))
+	}
+
+	client.settings.Store(&next)
+	return nil
+}
+
+type ReloadConfig struct {
+	// Provider returns the configuration to apply, it defaults to reading
+	// File.
+	Provider ConfigProvider
+	// File, when set, is polled every PollInterval (five seconds by
+	// default) and the configuration is reloaded when it is modified.
+	File         string
+	PollInterval time.Duration
+	// Signals trigger a reload, SIGHUP by default.
+	Signals []os.Signal
+	// OnReload is called after every successful reload and OnError when
+	// the configuration can't be read or applied, the previous settings
+	// are kept then.
+	OnReload func(config *Config)
+	OnError  func(err error)
+}
+
+// WatchConfig reloads the client on signals and file changes until the
+// context is done:
+//
+//	go client.WatchConfig(ctx, &vies.ReloadConfig{File: "/etc/vies.yaml"})
+func (client *Client) WatchConfig(ctx context.Context, config *ReloadConfig) error {
+
+	var file string
+	var provider ConfigProvider
+	var onReload func(*Config)
+	var onError func(error)
+
+	pollInterval := defaultReloadPollInterval
+	signals := []os.Signal{syscall.SIGHUP}
+
+	if config != nil {
+		file = config.File
+		provider = config.Provider
+		if config.PollInterval > 0 {
+			pollInterval = config.PollInterval
+		}
+		if len(config.Signals) > 0 {
+			signals = config.Signals
+		}
+		onReload = config.OnReload
+		onError = config.OnError
+	}
+	if provider == nil {
+		if file == "" {
+			return errors.New("no configuration provider or file")
+		}
+		provider = FileConfig(file)
+	}
+
+	reload := func() {
+		c, err := provider()
+		if err == nil {
+			err = client.Reload(c)
+		}
+		switch {
+		case err != nil && onError != nil:
+			onError(err)
+		case err == nil && onReload != nil:
+			onReload(c)
+		}
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, signals...)
+	defer signal.Stop(sig)
+
+	var poll <-chan time.Time
+	var modified time.Time
+	if file != "" {
+		if info, err := os.Stat(file); err == nil {
+			modified = info.ModTime()
+		}
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sig:
+			reload()
+		case <-poll:
+			info, err := os.Stat(file)
+			if err != nil || info.ModTime().Equal(modified) {
+				continue
+			}
+			modified = info.ModTime()
+			reload()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+
+	var mu sync.Mutex
+	var urls []string
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		urls = append(urls, req.URL.String())
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	cache := NewMemoryCache(time.Minute)
+	v, err := NewClient(&ClientConfig{HttpClient: httpClient, EndpointUrl: "https://a.example.com/", Cache: cache})
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+
+	assert.NoError(t, v.Reload(&Config{Endpoint: "https://b.example.com/", RateLimit: 10, AllowedCountries: []string{"EE"}, CacheTTL: Duration(time.Hour)}))
+	limiter := v.settings.Load().rateLimiter
+	assert.NotNil(t, limiter)
+
+	// the cached result survives the reload
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "EE456")
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "DE456")
+	assert.True(t, errors.Is(err, ErrCountryNotAllowed))
+
+	assert.Equal(t, []string{"https://a.example.com/check-vat-number", "https://b.example.com/check-vat-number"}, urls)
+	assert.Equal(t, time.Hour, cache.ttl)
+
+	assert.NoError(t, v.Reload(&Config{Endpoint: "https://b.example.com/", RateLimit: 10}))
+	assert.Same(t, limiter, v.settings.Load().rateLimiter)
+
+	assert.EqualError(t, v.Reload(&Config{Requester: "D"}), "invalid VAT provided D")
+	assert.Equal(t, "https://b.example.com/", v.settings.Load().endpoint.String())
+}
+
+func TestWatchConfig(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "vies.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"endpoint":"https://a.example.com/"}`), 0o600))
+
+	v, err := NewClient(nil)
+	assert.NoError(t, err)
+
+	reloaded := make(chan *Config, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- v.WatchConfig(ctx, &ReloadConfig{
+			File:         path,
+			PollInterval: 10 * time.Millisecond,
+			OnReload:     func(config *Config) { reloaded <- config },
+		})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	later := time.Now().Add(time.Second)
+	assert.NoError(t, os.WriteFile(path, []byte(`{"endpoint":"https://b.example.com/"}`), 0o600))
+	assert.NoError(t, os.Chtimes(path, later, later))
+	c := <-reloaded
+	assert.Equal(t, "https://b.example.com/", c.Endpoint)
+	assert.Equal(t, "https://b.example.com/", v.settings.Load().endpoint.String())
+
+	cancel()
+	assert.NoError(t, <-done)
+
+	assert.EqualError(t, v.WatchConfig(context.Background(), nil), "no configuration provider or file")
+}
@@ -0,0 +1,58 @@
+package vies
+
+// ResultSet is a list of check results, such as those of BatchReport and
+// BulkResults.ResultSet, with helpers for reports.
+type ResultSet []*CheckResult
+
+// AllValid reports whether every result is valid, which an empty set is.
+func (set ResultSet) AllValid() bool {
+	for _, result := range set {
+		if !result.Valid {
+			return false
+		}
+	}
+	return true
+}
+
+// Valid returns the valid results.
+func (set ResultSet) Valid() ResultSet {
+	return set.filter(true)
+}
+
+// Invalid returns the invalid results.
+func (set ResultSet) Invalid() ResultSet {
+	return set.filter(false)
+}
+
+func (set ResultSet) filter(valid bool) ResultSet {
+	var filtered ResultSet
+	for _, result := range set {
+		if result.Valid == valid {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered
+}
+
+// GroupByCountry returns the results of each member state, in the order
+// of the set.
+func (set ResultSet) GroupByCountry() map[CountryCode]ResultSet {
+	groups := make(map[CountryCode]ResultSet)
+	for _, result := range set {
+		groups[result.CountryCode] = append(groups[result.CountryCode], result)
+	}
+	return groups
+}
+
+// ConsultationNumbers returns the consultation numbers issued by VIES,
+// keyed by VAT number, for the results of checks made on behalf of a
+// requester.
+func (set ResultSet) ConsultationNumbers() map[string]string {
+	numbers := make(map[string]string)
+	for _, result := range set {
+		if result.RequestIdentifier != "" {
+			numbers[result.Vat] = result.RequestIdentifier
+		}
+	}
+	return numbers
+}
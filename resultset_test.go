@@ -0,0 +1,26 @@
+package vies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultSet(t *testing.T) {
+
+	de := &CheckResult{CountryCode: "DE", Vat: "DE123456789", Valid: true, RequestIdentifier: "WAPIAAAA00000001"}
+	fr := &CheckResult{CountryCode: "FR", Vat: "FR12345678901", Valid: false, RequestIdentifier: "WAPIAAAA00000002"}
+	de2 := &CheckResult{CountryCode: "DE", Vat: "DE987654321", Valid: true}
+	set := ResultSet{de, fr, de2}
+
+	assert.False(t, set.AllValid())
+	assert.True(t, set.Valid().AllValid())
+	assert.True(t, ResultSet{}.AllValid())
+	assert.Equal(t, ResultSet{fr}, set.Invalid())
+	assert.Equal(t, ResultSet{de, de2}, set.Valid())
+	assert.Equal(t, map[CountryCode]ResultSet{"DE": {de, de2}, "FR": {fr}}, set.GroupByCountry())
+	assert.Equal(t, map[string]string{
+		"DE123456789":   "WAPIAAAA00000001",
+		"FR12345678901": "WAPIAAAA00000002",
+	}, set.ConsultationNumbers())
+}
@@ -0,0 +1,133 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const defaultRetentionInterval = 24 * time.Hour
+
+// RetentionStoreInterface is a store of VAT numbers which takes part in a
+// Retention. MemoryCache, FileCache, FileAuditSink, SQLAuditSink and
+// SQLHistory implement it, RetentionHook adapts other stores.
+type RetentionStoreInterface interface {
+	// Prune removes the entries stored before before and returns how many.
+	Prune(ctx context.Context, before time.Time) (int, error)
+	// Erase removes or anonymizes the entries of vat, returning how many,
+	// so that the store doesn't identify the trader anymore.
+	Erase(ctx context.Context, vat string) (int, error)
+}
+
+// RetentionHook adapts functions to RetentionStoreInterface, either may be
+// nil.
+type RetentionHook struct {
+	OnPrune func(ctx context.Context, before time.Time) (int, error)
+	OnErase func(ctx context.Context, vat string) (int, error)
+}
+
+func (h RetentionHook) Prune(ctx context.Context, before time.Time) (int, error) {
+	if h.OnPrune == nil {
+		return 0, nil
+	}
+	return h.OnPrune(ctx, before)
+}
+
+func (h RetentionHook) Erase(ctx context.Context, vat string) (int, error) {
+	if h.OnErase == nil {
+		return 0, nil
+	}
+	return h.OnErase(ctx, vat)
+}
+
+type RetentionConfig struct {
+	// MaxAge is how long entries are kept, they are never pruned when
+	// zero.
+	MaxAge time.Duration
+	// Interval is the time between two prunes of Run, defaults to a day.
+	Interval time.Duration
+	Stores   []RetentionStoreInterface
+	// Clock tells the age of the entries and spaces the prunes of Run,
+	// the system clock when nil. Make it the clock of the stores.
+	Clock ClockInterface
+}
+
+// Retention prunes old entries from stores and erases the entries of a
+// VAT number from all of them, on a GDPR erasure request for instance.
+type Retention struct {
+	maxAge   time.Duration
+	interval time.Duration
+	stores   []RetentionStoreInterface
+	clock    ClockInterface
+}
+
+func NewRetention(config *RetentionConfig) *Retention {
+
+	interval := defaultRetentionInterval
+	var maxAge time.Duration
+	var stores []RetentionStoreInterface
+	var clock ClockInterface
+
+	if config != nil {
+		if config.Interval > 0 {
+			interval = config.Interval
+		}
+		maxAge = config.MaxAge
+		stores = config.Stores
+		clock = config.Clock
+	}
+
+	return &Retention{
+		maxAge:   maxAge,
+		interval: interval,
+		stores:   stores,
+		clock:    clockOrSystem(clock),
+	}
+}
+
+// Prune removes the entries older than MaxAge from every store and returns
+// how many, the stores which fail don't stop the others.
+func (r *Retention) Prune(ctx context.Context) (int, error) {
+
+	if r.maxAge <= 0 {
+		return 0, nil
+	}
+	before := r.clock.Now().Add(-r.maxAge)
+
+	total := 0
+	var errs []error
+	for _, store := range r.stores {
+		n, err := store.Prune(ctx, before)
+		total += n
+		errs = append(errs, err)
+	}
+	return total, errors.Join(errs...)
+}
+
+// Erase removes or anonymizes the entries of vat in every store and
+// returns how many, the stores which fail don't stop the others.
+func (r *Retention) Erase(ctx context.Context, vat string) (int, error) {
+
+	total := 0
+	var errs []error
+	for _, store := range r.stores {
+		n, err := store.Erase(ctx, vat)
+		total += n
+		errs = append(errs, err)
+	}
+	return total, errors.Join(errs...)
+}
+
+// Run prunes the stores every Interval until the context is done, and
+// returns its error. Failed prunes are retried at the next interval.
+func (r *Retention) Run(ctx context.Context) error {
+
+	for {
+		_, _ = r.Prune(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.clock.After(r.interval):
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionErase(t *testing.T) {
+
+	ctx := context.Background()
+	result := &CheckResult{CountryCode: "DE", VatNumber: "123456789", Valid: true, Name: "ACME"}
+
+	memory := NewMemoryCache(time.Hour)
+	memory.Set("DE123456789", result)
+	memory.Set("FR1", result)
+
+	files, err := NewFileCache(t.TempDir(), time.Hour)
+	assert.NoError(t, err)
+	files.Set("DE123456789", result)
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := NewFileAuditSink(path)
+	assert.NoError(t, err)
+	defer audit.Close()
+	assert.NoError(t, audit.Record(ctx, &AuditRecord{Time: time.Now(), Input: "de 123456789", CountryCode: "DE", VatNumber: "123456789", RequestIdentifier: "WAPI", Result: result}))
+	assert.NoError(t, audit.Record(ctx, &AuditRecord{Time: time.Now(), Input: "FR1", CountryCode: "FR", VatNumber: "1"}))
+
+	var erased []string
+	hook := RetentionHook{OnErase: func(ctx context.Context, vat string) (int, error) {
+		erased = append(erased, vat)
+		return 0, errors.New("store down")
+	}}
+
+	retention := NewRetention(&RetentionConfig{Stores: []RetentionStoreInterface{memory, files, audit, hook}})
+	n, err := retention.Erase(ctx, "DE 123 456 789")
+	assert.EqualError(t, err, "store down")
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{"DE 123 456 789"}, erased)
+
+	_, ok := memory.Get("DE123456789")
+	assert.False(t, ok)
+	_, ok = memory.Get("FR1")
+	assert.True(t, ok)
+	_, ok = files.Get("DE123456789")
+	assert.False(t, ok)
+
+	// the anonymized record is still there, records keep being appended
+	assert.NoError(t, audit.Record(ctx, &AuditRecord{Time: time.Now(), Input: "FR2"}))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 3)
+	assert.NotContains(t, lines[0], "123456789")
+	assert.NotContains(t, lines[0], "ACME")
+	assert.Contains(t, lines[0], `"requestIdentifier":"WAPI"`)
+	assert.Contains(t, lines[1], "FR1")
+
+	// prunes are no-ops without MaxAge
+	n, err = retention.Prune(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestRetentionEraseCheckedResults(t *testing.T) {
+
+	ctx := context.Background()
+	var calls int
+	httpClient := NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"DE","vatNumber":"123456789","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	memory := NewMemoryCache(time.Hour)
+	files, err := NewFileCache(t.TempDir(), time.Hour)
+	assert.NoError(t, err)
+
+	for _, cache := range []interface {
+		CacheInterface
+		RetentionStoreInterface
+	}{memory, files} {
+		calls = 0
+		v, err := New(WithHttpClient(httpClient), WithEndpoint("https://example.com/"), WithCache(cache))
+		assert.NoError(t, err)
+
+		_, err = v.Check(ctx, "DE 123.456.789")
+		assert.NoError(t, err)
+		_, err = v.Check(ctx, "de123456789", WithRequesterOverride("FR333"))
+		assert.NoError(t, err)
+		_, err = v.Check(ctx, "DE-123-456-789")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, calls)
+
+		retention := NewRetention(&RetentionConfig{Stores: []RetentionStoreInterface{cache}})
+		n, err := retention.Erase(ctx, "DE123456789")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+
+		_, err = v.Check(ctx, "DE 123.456.789")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+	}
+}
+
+func TestRetentionPrune(t *testing.T) {
+
+	ctx := context.Background()
+	result := &CheckResult{CountryCode: "DE", VatNumber: "1"}
+
+	memory := NewMemoryCache(time.Hour)
+	memory.Set("DE1", result)
+
+	dir := t.TempDir()
+	files, err := NewFileCache(dir, time.Hour)
+	assert.NoError(t, err)
+	files.Set("DE1", result)
+	// entries of previous versions don't record when they were stored
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "old.json"), []byte(`{"expires":"2100-01-01T00:00:00Z","result":{}}`), 0o600))
+
+	audit, err := NewFileAuditSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+	assert.NoError(t, err)
+	defer audit.Close()
+	assert.NoError(t, audit.Record(ctx, &AuditRecord{Time: time.Now().Add(-48 * time.Hour), Input: "DE1"}))
+	assert.NoError(t, audit.Record(ctx, &AuditRecord{Time: time.Now(), Input: "DE2"}))
+
+	var before time.Time
+	hook := RetentionHook{OnPrune: func(ctx context.Context, at time.Time) (int, error) {
+		before = at
+		return 5, nil
+	}}
+
+	retention := NewRetention(&RetentionConfig{MaxAge: 24 * time.Hour, Stores: []RetentionStoreInterface{memory, files, audit, hook}})
+	n, err := retention.Prune(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 7, n)
+	assert.WithinDuration(t, time.Now().Add(-24*time.Hour), before, time.Second)
+
+	_, ok := memory.Get("DE1")
+	assert.True(t, ok)
+	_, ok = files.Get("DE1")
+	assert.True(t, ok)
+
+	n, err = memory.Prune(ctx, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	n, err = files.Prune(ctx, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestRetentionClock(t *testing.T) {
+
+	ctx := context.Background()
+	clock := newTestClock()
+
+	memory := NewMemoryCache(time.Hour)
+	memory.SetClock(clock)
+	memory.Set("DE1", &CheckResult{CountryCode: "DE", VatNumber: "1"})
+
+	var before []time.Time
+	hook := RetentionHook{OnPrune: func(ctx context.Context, at time.Time) (int, error) {
+		before = append(before, at)
+		return 0, nil
+	}}
+
+	retention := NewRetention(&RetentionConfig{MaxAge: 24 * time.Hour, Stores: []RetentionStoreInterface{memory, hook}, Clock: clock})
+	n, err := retention.Prune(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Equal(t, []time.Time{clock.Now().Add(-24 * time.Hour)}, before)
+
+	clock.Advance(25 * time.Hour)
+	n, err = retention.Prune(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// Run prunes every interval of the clock
+	ctx, cancel := context.WithCancel(ctx)
+	var runs []time.Time
+	hook.OnPrune = func(ctx context.Context, at time.Time) (int, error) {
+		runs = append(runs, at)
+		if len(runs) == 3 {
+			cancel()
+		}
+		return 0, nil
+	}
+	retention = NewRetention(&RetentionConfig{MaxAge: time.Hour, Interval: time.Hour, Stores: []RetentionStoreInterface{hook}, Clock: clock})
+	assert.ErrorIs(t, retention.Run(ctx), context.Canceled)
+	assert.GreaterOrEqual(t, len(runs), 3)
+	assert.Equal(t, time.Hour, runs[1].Sub(runs[0]))
+	assert.Equal(t, time.Hour, runs[2].Sub(runs[1]))
+}
+
+func TestRetentionSQL(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	before := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	audit := NewSQLAuditSink(db, &SQLAuditSinkConfig{Placeholder: DollarPlaceholder})
+	history := NewSQLHistory(db, &SQLHistoryConfig{Placeholder: DollarPlaceholder})
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM vies_audit WHERE checked_at < $1")).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM vies_history WHERE checked_at < $1")).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE vies_audit SET input = '', vat_number = '', result = '', error = '' WHERE country_code = $1 AND UPPER(vat_number) = $2")).
+		WithArgs("DE", "123456789").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM vies_history WHERE vat = $1")).
+		WithArgs("DE123456789").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	n, err := audit.Prune(ctx, before)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	n, err = history.Prune(ctx, before)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	n, err = audit.Erase(ctx, "de123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	n, err = history.Erase(ctx, "DE 123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
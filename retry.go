@@ -0,0 +1,118 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryBackoff  = 500 * time.Millisecond
+	defaultRetryMaxDelay = time.Minute
+)
+
+type RetryConfig struct {
+	// MaxAttempts is the number of times a request is sent, including the
+	// first one, defaults to 3.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for each
+	// following one, defaults to 500ms. A Retry-After header of the
+	// response takes precedence.
+	Backoff time.Duration
+	// MaxDelay caps the delay before a retry, Retry-After included,
+	// defaults to one minute.
+	MaxDelay time.Duration
+}
+
+// retryInterceptor sends a request again after transport errors and 429
+// or 5xx responses.
+func retryInterceptor(config *RetryConfig, clock ClockInterface) Interceptor {
+
+	attempts := defaultRetryAttempts
+	backoff := defaultRetryBackoff
+	maxDelay := defaultRetryMaxDelay
+
+	if config.MaxAttempts > 0 {
+		attempts = config.MaxAttempts
+	}
+	if config.Backoff > 0 {
+		backoff = config.Backoff
+	}
+	if config.MaxDelay > 0 {
+		maxDelay = config.MaxDelay
+	}
+
+	return func(next RoundTripperFunc) RoundTripperFunc {
+		return func(req *http.Request) (*http.Response, error) {
+
+			delay := backoff
+			for attempt := 1; ; attempt++ {
+
+				rsp, err := next(req)
+				if attempt == attempts || !retryable(req.Context(), rsp, err) {
+					return rsp, err
+				}
+				if req.Body != nil && req.GetBody == nil {
+					return rsp, err
+				}
+
+				wait := delay
+				if after, ok := retryAfter(rsp); ok {
+					wait = after
+				}
+				wait = min(wait, maxDelay)
+				if rsp != nil {
+					_, _ = io.Copy(io.Discard, rsp.Body)
+					_ = rsp.Body.Close()
+				}
+
+				select {
+				case <-clock.After(wait):
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				}
+				delay *= 2
+
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					req.Body = body
+				}
+			}
+		}
+	}
+}
+
+func retryable(ctx context.Context, rsp *http.Response, err error) bool {
+	var limitErr *rateLimitError
+	if errors.As(err, &limitErr) {
+		return false
+	}
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch rsp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter reads a Retry-After header given in seconds.
+func retryAfter(rsp *http.Response) (time.Duration, bool) {
+	if rsp == nil {
+		return 0, false
+	}
+	seconds, err := strconv.Atoi(rsp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
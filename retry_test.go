@@ -0,0 +1,139 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry(t *testing.T) {
+
+	newClient := func(t *testing.T, statuses []int, retry *RetryConfig, metrics MetricsInterface) (*Client, *[]string) {
+		var bodies []string
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			body, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(body))
+			status := statuses[min(len(bodies), len(statuses))-1]
+			header := make(http.Header)
+			if status == http.StatusTooManyRequests {
+				header.Set("Retry-After", "0")
+			}
+			return &http.Response{
+				StatusCode: status,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+				Header:     header,
+			}
+		})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Retry: retry, Metrics: metrics})
+		assert.NoError(t, err)
+		return v, &bodies
+	}
+
+	t.Run("retries 5xx and 429", func(t *testing.T) {
+		metrics := &recordingMetrics{}
+		v, bodies := newClient(t, []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}, &RetryConfig{Backoff: time.Millisecond}, metrics)
+
+		result, err := v.Check(context.Background(), "EE123")
+		assert.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Len(t, *bodies, 3)
+		assert.Equal(t, (*bodies)[0], (*bodies)[2])
+		assert.Contains(t, metrics.counters, "retries_total map[country:EE operation:check]")
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		v, bodies := newClient(t, []int{http.StatusBadGateway}, &RetryConfig{MaxAttempts: 2, Backoff: time.Millisecond}, nil)
+
+		_, err := v.Check(context.Background(), "EE123")
+		assert.Error(t, err)
+		assert.Len(t, *bodies, 2)
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		v, bodies := newClient(t, []int{http.StatusBadRequest}, &RetryConfig{Backoff: time.Millisecond}, nil)
+
+		_, err := v.Check(context.Background(), "EE123")
+		assert.Error(t, err)
+		assert.Len(t, *bodies, 1)
+	})
+
+	t.Run("every attempt waits on the rate limiter", func(t *testing.T) {
+		limiter := &countingLimiter{}
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(bytes.NewBufferString(""))}
+		})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Retry: &RetryConfig{Backoff: time.Millisecond}, RateLimiter: limiter})
+		assert.NoError(t, err)
+
+		_, err = v.Check(context.Background(), "EE123")
+		assert.Error(t, err)
+		assert.Equal(t, 3, limiter.waits)
+	})
+
+	t.Run("rate limiter errors are not retried", func(t *testing.T) {
+		var calls int
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			calls++
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(""))}
+		})
+		limiter := &countingLimiter{err: errors.New("limit exceeded")}
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Retry: &RetryConfig{Backoff: time.Millisecond}, RateLimiter: limiter})
+		assert.NoError(t, err)
+
+		_, err = v.Check(context.Background(), "EE123")
+		assert.ErrorIs(t, err, limiter.err)
+		assert.Equal(t, 1, limiter.waits)
+		assert.Zero(t, calls)
+	})
+
+	t.Run("caps Retry-After", func(t *testing.T) {
+		var calls int
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			calls++
+			if calls == 1 {
+				header := make(http.Header)
+				header.Set("Retry-After", "86400")
+				return &http.Response{StatusCode: http.StatusTooManyRequests, Body: io.NopCloser(bytes.NewBufferString("")), Header: header}
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			}
+		})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Retry: &RetryConfig{MaxDelay: time.Millisecond}})
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		result, err := v.Check(ctx, "EE123")
+		assert.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		v, bodies := newClient(t, []int{http.StatusServiceUnavailable}, &RetryConfig{Backoff: time.Hour}, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := v.Check(ctx, "EE123")
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Len(t, *bodies, 1)
+	})
+}
+
+type countingLimiter struct {
+	waits int
+	err   error
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	l.waits++
+	return l.err
+}
@@ -0,0 +1,41 @@
+package vies
+
+import "time"
+
+const defaultInvalidRevalidation = 7 * 24 * time.Hour
+
+// RevalidationPolicy tells when the results of Check should be verified
+// again, it sets CheckResult.CheckedAt and CheckResult.RevalidateAfter.
+type RevalidationPolicy struct {
+	// Valid is how long a valid result holds, 90 days by default.
+	Valid time.Duration
+	// Invalid is how long an invalid result holds, 7 days by default, as
+	// a number may become valid once registered.
+	Invalid time.Duration
+}
+
+// stamp sets the check time and the revalidation date of result.
+func (policy *RevalidationPolicy) stamp(result *CheckResult, now time.Time) {
+
+	after := policy.Valid
+	if after <= 0 {
+		after = defaultRevalidationInterval
+	}
+	if !result.Valid {
+		after = policy.Invalid
+		if after <= 0 {
+			after = defaultInvalidRevalidation
+		}
+	}
+
+	checkedAt := now.UTC().Truncate(time.Second)
+	revalidateAfter := checkedAt.Add(after)
+	result.CheckedAt = &checkedAt
+	result.RevalidateAfter = &revalidateAfter
+}
+
+// Due reports whether the result should be verified again at now. Results
+// without RevalidateAfter are always due.
+func (result *CheckResult) Due(now time.Time) bool {
+	return result.RevalidateAfter == nil || !now.Before(*result.RevalidateAfter)
+}
@@ -0,0 +1,49 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevalidation(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		valid := !strings.Contains(string(body), "000")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(fmt.Sprintf(`{"countryCode":"EE","vatNumber":"123","valid":%t}`, valid))),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := New(WithHttpClient(client), WithRevalidation(30*24*time.Hour, 0))
+	assert.NoError(t, err)
+
+	start := time.Now().Truncate(time.Second)
+	valid, err := v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.WithinRange(t, *valid.CheckedAt, start, time.Now())
+	assert.Equal(t, 30*24*time.Hour, valid.RevalidateAfter.Sub(*valid.CheckedAt))
+	assert.False(t, valid.Due(time.Now()))
+	assert.True(t, valid.Due(time.Now().Add(31*24*time.Hour)))
+
+	invalid, err := v.Check(context.Background(), "EE000")
+	assert.NoError(t, err)
+	assert.Equal(t, 7*24*time.Hour, invalid.RevalidateAfter.Sub(*invalid.CheckedAt))
+
+	plain, err := New(WithHttpClient(client))
+	assert.NoError(t, err)
+	result, err := plain.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	assert.Nil(t, result.CheckedAt)
+	assert.True(t, result.Due(time.Now()))
+}
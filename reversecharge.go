@@ -0,0 +1,89 @@
+package vies
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alytsin/go-vies/viescountries"
+)
+
+// ErrNotMemberState is returned by ReverseCharge when the seller is not
+// established in a VIES member state.
+var ErrNotMemberState = errors.New("not a VIES member state")
+
+// DecisionReason tells why VAT is charged or not.
+type DecisionReason string
+
+const (
+	// ReasonDomestic is a sale within the seller's member state, VAT is
+	// charged.
+	ReasonDomestic DecisionReason = "domestic"
+	// ReasonIntraCommunity is a B2B sale to a buyer with a valid VAT number
+	// in another member state, the buyer accounts for VAT.
+	ReasonIntraCommunity DecisionReason = "intra_community"
+	// ReasonNoValidVat is a sale to another member state without a valid
+	// VAT number, VAT is charged as for a consumer.
+	ReasonNoValidVat DecisionReason = "no_valid_vat"
+	// ReasonOutsideVatArea is a sale to a buyer outside the EU VAT area, no
+	// EU VAT is charged.
+	ReasonOutsideVatArea DecisionReason = "outside_vat_area"
+)
+
+// Decision is the VAT treatment of a B2B sale.
+type Decision struct {
+	// ChargeVat tells whether the seller charges VAT on the invoice.
+	ChargeVat bool `json:"chargeVat"`
+	// ReverseCharge tells whether the invoice must state that the buyer
+	// accounts for VAT.
+	ReverseCharge bool           `json:"reverseCharge"`
+	Reason        DecisionReason `json:"reason"`
+}
+
+// ReverseCharge decides whether a seller charges VAT to a buyer, given
+// their VIES or ISO country codes and whether the buyer's VAT number was
+// found valid. Territories outside the EU VAT area, such as the Canary
+// Islands, are accepted as buyer countries.
+func ReverseCharge(sellerCountry, buyerCountry string, buyerVatValid bool) (Decision, error) {
+
+	seller := viesCountryCode(sellerCountry)
+	if !viescountries.IsMember(seller) {
+		return Decision{}, fmt.Errorf("%w %s", ErrNotMemberState, sellerCountry)
+	}
+
+	buyer := viesCountryCode(buyerCountry)
+	if t, ok := viescountries.LookupTerritory(buyerCountry); ok {
+		buyer = t.CountryCode
+	}
+
+	switch {
+	case !viescountries.IsMember(buyer):
+		return Decision{Reason: ReasonOutsideVatArea}, nil
+	case buyer == seller:
+		return Decision{ChargeVat: true, Reason: ReasonDomestic}, nil
+	case buyerVatValid:
+		return Decision{ReverseCharge: true, Reason: ReasonIntraCommunity}, nil
+	}
+	return Decision{ChargeVat: true, Reason: ReasonNoValidVat}, nil
+}
+
+// ReverseChargeFor decides from the result of a check of the buyer's VAT
+// number.
+func ReverseChargeFor(sellerCountry string, result *CheckResult) (Decision, error) {
+	if result == nil {
+		return Decision{}, fmt.Errorf("%w: no check result", ErrInvalidVat)
+	}
+	return ReverseCharge(sellerCountry, string(result.CountryCode), result.Valid)
+}
+
+// viesCountryCode converts an ISO code to the VIES code of a member state,
+// GR becoming EL.
+func viesCountryCode(country string) string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if !viescountries.IsMember(country) {
+		if code := viescountries.ViesCode(country); code != "" && country != "GB" {
+			return code
+		}
+	}
+	return country
+}
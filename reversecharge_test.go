@@ -0,0 +1,47 @@
+package vies
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseCharge(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		seller   string
+		buyer    string
+		valid    bool
+		decision Decision
+		err      error
+	}{
+		{name: "intra community", seller: "DE", buyer: "FR", valid: true, decision: Decision{ReverseCharge: true, Reason: ReasonIntraCommunity}},
+		{name: "invalid buyer", seller: "DE", buyer: "FR", decision: Decision{ChargeVat: true, Reason: ReasonNoValidVat}},
+		{name: "domestic", seller: "DE", buyer: "de", valid: true, decision: Decision{ChargeVat: true, Reason: ReasonDomestic}},
+		{name: "iso codes", seller: "GR", buyer: "EL", valid: true, decision: Decision{ChargeVat: true, Reason: ReasonDomestic}},
+		{name: "monaco", seller: "FR", buyer: "MC", valid: true, decision: Decision{ChargeVat: true, Reason: ReasonDomestic}},
+		{name: "northern ireland", seller: "IE", buyer: "XI", valid: true, decision: Decision{ReverseCharge: true, Reason: ReasonIntraCommunity}},
+		{name: "canary islands", seller: "ES", buyer: "ES-CN", valid: true, decision: Decision{Reason: ReasonOutsideVatArea}},
+		{name: "outside", seller: "DE", buyer: "CH", decision: Decision{Reason: ReasonOutsideVatArea}},
+		{name: "seller outside", seller: "CH", buyer: "DE", valid: true, err: ErrNotMemberState},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := ReverseCharge(tt.seller, tt.buyer, tt.valid)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Equal(t, tt.decision, decision)
+		})
+	}
+}
+
+func TestReverseChargeFor(t *testing.T) {
+	decision, err := ReverseChargeFor("DE", &CheckResult{CountryCode: "EE", Valid: true})
+	assert.NoError(t, err)
+	assert.True(t, decision.ReverseCharge)
+
+	_, err = ReverseChargeFor("DE", nil)
+	assert.ErrorIs(t, err, ErrInvalidVat)
+}
@@ -0,0 +1,75 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alytsin/go-vies/viescountries"
+)
+
+// ErrNoRegistry is returned by Router.Check for VAT numbers whose prefix
+// has no registry.
+var ErrNoRegistry = errors.New("no registry for VAT number")
+
+// RegistryInterface checks VAT numbers against a register. It is
+// implemented by Client and by the hmrc, uid and brreg clients.
+//
+// Registries other than Client ignore the CheckOptions they have no use
+// for.
+type RegistryInterface interface {
+	Check(ctx context.Context, vat string, opts ...CheckOption) (*CheckResult, error)
+}
+
+// Router dispatches checks to a registry by the prefix of the VAT number,
+// the longest matching prefix wins.
+type Router struct {
+	routes map[string]RegistryInterface
+}
+
+// NewRouter returns a router sending the numbers of every VIES member state
+// to client, other registries are added with Handle:
+//
+//	router := vies.NewRouter(client).
+//		Handle("GB", hmrcClient).
+//		Handle("CHE", uidClient).
+//		Handle("NO", brregClient)
+func NewRouter(client RegistryInterface) *Router {
+	r := &Router{routes: make(map[string]RegistryInterface)}
+	if client != nil {
+		for _, code := range viescountries.Codes() {
+			r.routes[code] = client
+		}
+	}
+	return r
+}
+
+// Handle routes the VAT numbers starting with prefix to the registry.
+func (r *Router) Handle(prefix string, registry RegistryInterface) *Router {
+	r.routes[strings.ToUpper(prefix)] = registry
+	return r
+}
+
+func (r *Router) Check(ctx context.Context, vat string, opts ...CheckOption) (*CheckResult, error) {
+
+	registry, ok := r.route(vat)
+	if !ok {
+		return nil, fmt.Errorf("%w %s", ErrNoRegistry, PrivateVat(vat))
+	}
+	return registry.Check(ctx, vat, opts...)
+}
+
+func (r *Router) route(vat string) (RegistryInterface, bool) {
+
+	vat = strings.ToUpper(strings.TrimSpace(vat))
+
+	var match string
+	var registry RegistryInterface
+	for prefix, reg := range r.routes {
+		if len(prefix) > len(match) && strings.HasPrefix(vat, prefix) {
+			match, registry = prefix, reg
+		}
+	}
+	return registry, registry != nil
+}
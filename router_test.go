@@ -0,0 +1,59 @@
+package vies
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type registryFunc func(ctx context.Context, vat string) (*CheckResult, error)
+
+func (f registryFunc) Check(ctx context.Context, vat string, opts ...CheckOption) (*CheckResult, error) {
+	return f(ctx, vat)
+}
+
+func namedRegistry(name string) RegistryInterface {
+	return registryFunc(func(ctx context.Context, vat string) (*CheckResult, error) {
+		return &CheckResult{Vat: vat, Registry: name}, nil
+	})
+}
+
+func TestRouter(t *testing.T) {
+
+	router := NewRouter(namedRegistry("VIES")).
+		Handle("GB", namedRegistry("HMRC")).
+		Handle("che", namedRegistry("UID")).
+		Handle("CH", namedRegistry("CH")).
+		Handle("NO", namedRegistry("BRREG"))
+
+	cases := []struct {
+		vat      string
+		registry string
+		err      error
+	}{
+		{vat: "EE100354546", registry: "VIES"},
+		{vat: "el123456789", registry: "VIES"},
+		{vat: "XI123456789", registry: "VIES"},
+		{vat: "GB553557881", registry: "HMRC"},
+		{vat: "CHE-116.281.710 MWST", registry: "UID"},
+		{vat: "CH123", registry: "CH"},
+		{vat: " NO 923 609 016 MVA", registry: "BRREG"},
+		{vat: "US123456789", err: ErrNoRegistry},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.vat, func(t *testing.T) {
+			result, err := router.Check(context.Background(), tt.vat)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.registry, result.Registry)
+			assert.Equal(t, tt.vat, result.Vat)
+		})
+	}
+
+	var _ RegistryInterface = &Client{}
+}
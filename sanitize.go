@@ -0,0 +1,45 @@
+package vies
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxInputLength is the length in bytes above which inputs are rejected
+// without being looked at, room enough for the longest VAT number written
+// with separators in full-width characters.
+const maxInputLength = 128
+
+// SanitizeVat prepares untrusted input, such as a form field or an API
+// parameter, for validation. Inputs which are empty, longer than 128 bytes,
+// not valid UTF-8 or which contain newlines or other control characters
+// are rejected with an ErrInvalidInput, others are repaired with CleanVat
+// and trimmed. SanitizeVat never panics.
+func SanitizeVat(s string) (string, error) {
+	if err := checkInput(s); err != nil {
+		return "", err
+	}
+	vat := strings.TrimSpace(CleanVat(s))
+	if vat == "" {
+		return "", &ErrInvalidInput{Input: s, Reason: ReasonEmpty}
+	}
+	return vat, nil
+}
+
+// checkInput rejects the inputs SanitizeVat rejects, but empty ones. It
+// returns nil when the input passes.
+func checkInput(s string) *ErrInvalidInput {
+
+	switch {
+	case len(s) > maxInputLength:
+		return &ErrInvalidInput{Input: s[:maxInputLength], Reason: ReasonTooLong}
+	case !utf8.ValidString(s):
+		return &ErrInvalidInput{Input: strings.ToValidUTF8(s, "\ufffd"), Reason: ReasonBadCharacters}
+	case strings.ContainsAny(s, "\r\n\u0085\u2028\u2029"):
+		return &ErrInvalidInput{Input: s, Reason: ReasonNewline}
+	case strings.ContainsFunc(s, unicode.IsControl):
+		return &ErrInvalidInput{Input: s, Reason: ReasonControlCharacters}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package vies
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeVat(t *testing.T) {
+
+	cases := []struct {
+		name   string
+		input  string
+		vat    string
+		reason InputReason
+	}{
+		{name: "plain", input: " EE100354546\u00a0", vat: "EE100354546"},
+		{name: "repaired", input: "ＥＥ100354546", vat: "EE100354546"},
+		{name: "empty", input: "", reason: ReasonEmpty},
+		{name: "blank", input: " \u200b ", reason: ReasonEmpty},
+		{name: "too long", input: strings.Repeat("1", maxInputLength+1), reason: ReasonTooLong},
+		{name: "newline", input: "EE100354546\nDE123456789", reason: ReasonNewline},
+		{name: "line separator", input: "EE100354546\u2028", reason: ReasonNewline},
+		{name: "control", input: "EE100\x00354546", reason: ReasonControlCharacters},
+		{name: "invalid utf8", input: "EE\xff100354546", reason: ReasonBadCharacters},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			vat, err := SanitizeVat(tt.input)
+			assert.Equal(t, tt.vat, vat)
+			if tt.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var inputErr *ErrInvalidInput
+			assert.True(t, errors.As(err, &inputErr))
+			assert.Equal(t, tt.reason, inputErr.Reason)
+			assert.LessOrEqual(t, len(inputErr.Input), maxInputLength)
+			assert.True(t, utf8.ValidString(inputErr.Input))
+		})
+	}
+}
+
+func FuzzSanitizeVat(f *testing.F) {
+
+	for _, seed := range []string{"", "EE100354546", "ЕЕ１００３５４５４６", "EE\u200b1\n", "\xff\xfe", "A\u0301\u0000"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		vat, err := SanitizeVat(input)
+		if err != nil {
+			var inputErr *ErrInvalidInput
+			if !errors.As(err, &inputErr) || inputErr.Reason == "" {
+				t.Fatalf("untyped error %v", err)
+			}
+			return
+		}
+		if vat == "" || len(input) > maxInputLength || !utf8.ValidString(vat) || strings.ContainsFunc(vat, unicode.IsControl) {
+			t.Fatalf("%q sanitized to %q", input, vat)
+		}
+		_, _ = ParseVatNumber(input)
+	})
+}
@@ -0,0 +1,192 @@
+package vies
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+const defaultRevalidationInterval = 90 * 24 * time.Hour
+
+// VatSourceInterface provides the VAT numbers to re-validate, it is
+// iterated once per round.
+type VatSourceInterface interface {
+	Vats(ctx context.Context) iter.Seq2[string, error]
+}
+
+// VatList is a fixed VatSourceInterface.
+type VatList []string
+
+func (l VatList) Vats(context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for _, vat := range l {
+			if !yield(vat, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ResultSinkInterface receives the result of every re-validation.
+type ResultSinkInterface interface {
+	Report(ctx context.Context, result BulkResult)
+}
+
+// ResultSinkFunc adapts a function to ResultSinkInterface.
+type ResultSinkFunc func(ctx context.Context, result BulkResult)
+
+func (f ResultSinkFunc) Report(ctx context.Context, result BulkResult) {
+	f(ctx, result)
+}
+
+// ValidityChange is detected by the Scheduler when a VAT number became
+// invalid, valid again or changed its name since its previous validation.
+type ValidityChange struct {
+	Vat      string       `json:"vat"`
+	Previous *CheckResult `json:"previous"`
+	Current  *CheckResult `json:"current"`
+}
+
+type SchedulerConfig struct {
+	// Interval is the time between two re-validations of the same VAT
+	// number, defaults to 90 days. The checks of a round are spread
+	// evenly over it.
+	Interval time.Duration
+	// OnChange, when set, is called with every ValidityChange.
+	OnChange func(ctx context.Context, change ValidityChange)
+	// Results keeps the previous result of every VAT number to detect
+	// changes, defaults to a MemoryCache. Use a persistent cache with a
+	// TTL above Interval to detect changes across restarts.
+	Results CacheInterface
+	// History, when set, replaces Results: the previous result is its
+	// last check and every re-validation is added to it. Don't make it
+	// the ClientConfig.Audit of the client too, which adds checks already.
+	History HistoryInterface
+	// Clock, when set, replaces the clock of the client to space the
+	// checks and date the history.
+	Clock ClockInterface
+}
+
+// Scheduler periodically re-validates the VAT numbers of a source. The
+// client should not have a Cache, or one with a TTL well below Interval.
+type Scheduler struct {
+	client   *Client
+	source   VatSourceInterface
+	sink     ResultSinkInterface
+	interval time.Duration
+	onChange func(ctx context.Context, change ValidityChange)
+	results  CacheInterface
+	history  HistoryInterface
+	clock    ClockInterface
+}
+
+func NewScheduler(client *Client, source VatSourceInterface, sink ResultSinkInterface, config *SchedulerConfig) *Scheduler {
+
+	interval := defaultRevalidationInterval
+	var onChange func(ctx context.Context, change ValidityChange)
+	var results CacheInterface
+	var history HistoryInterface
+	clock := client.clock
+
+	if config != nil {
+		if config.Interval > 0 {
+			interval = config.Interval
+		}
+		onChange = config.OnChange
+		results = config.Results
+		history = config.History
+		if config.Clock != nil {
+			clock = config.Clock
+		}
+	}
+	if results == nil {
+		results = NewMemoryCache(2 * interval)
+	}
+
+	return &Scheduler{
+		client:   client,
+		source:   source,
+		sink:     sink,
+		interval: interval,
+		onChange: onChange,
+		results:  results,
+		history:  history,
+		clock:    clock,
+	}
+}
+
+// Run re-validates the source round after round until the context is done
+// or the source fails, and returns that error.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		if err := s.round(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Scheduler) round(ctx context.Context) error {
+
+	start := s.clock.Now()
+
+	var vats []string
+	for vat, err := range s.source.Vats(ctx) {
+		if err != nil {
+			return err
+		}
+		vats = append(vats, vat)
+	}
+
+	var spacing time.Duration
+	if len(vats) > 0 {
+		spacing = s.interval / time.Duration(len(vats))
+	}
+
+	for i, vat := range vats {
+		if err := sleepUntil(ctx, s.clock, start.Add(time.Duration(i)*spacing)); err != nil {
+			return err
+		}
+		result, err := s.client.Check(ctx, vat)
+		s.sink.Report(ctx, BulkResult{Vat: vat, Result: result, Err: err})
+		if err == nil {
+			s.compare(ctx, vat, result)
+		}
+	}
+
+	return sleepUntil(ctx, s.clock, start.Add(s.interval))
+}
+
+func (s *Scheduler) compare(ctx context.Context, vat string, result *CheckResult) {
+
+	key := vatKey(vat)
+	previous, ok := s.previous(ctx, key, result)
+
+	if !ok || s.onChange == nil {
+		return
+	}
+	if previous.Valid != result.Valid || previous.Name != result.Name {
+		s.onChange(ctx, ValidityChange{Vat: key, Previous: previous, Current: result})
+	}
+}
+
+// previous returns the result before result and keeps result for the next
+// round.
+func (s *Scheduler) previous(ctx context.Context, key string, result *CheckResult) (*CheckResult, bool) {
+
+	if s.history == nil {
+		previous, ok := s.results.Get(key)
+		s.results.Set(key, result)
+		return previous, ok
+	}
+
+	var previous *CheckResult
+	if entry, err := s.history.Last(ctx, key); err == nil {
+		previous = entry.Result
+	}
+	checkedAt := s.clock.Now()
+	if result.CheckedAt != nil {
+		checkedAt = *result.CheckedAt
+	}
+	_ = s.history.Add(ctx, checkedAt, result)
+	return previous, previous != nil
+}
@@ -0,0 +1,109 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingSource struct{}
+
+func (failingSource) Vats(context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		yield("", errors.New("source down"))
+	}
+}
+
+func TestScheduler(t *testing.T) {
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var vats []string
+	var times []time.Time
+	sink := ResultSinkFunc(func(ctx context.Context, result BulkResult) {
+		assert.NoError(t, result.Err)
+		assert.True(t, result.Result.Valid)
+		vats = append(vats, result.Vat)
+		times = append(times, time.Now())
+		if len(vats) == 4 {
+			cancel()
+		}
+	})
+
+	scheduler := NewScheduler(v, VatList{"DE1234567890", "FR12345678901", "EE123456789"}, sink, &SchedulerConfig{Interval: 60 * time.Millisecond})
+	assert.ErrorIs(t, scheduler.Run(ctx), context.Canceled)
+
+	assert.Equal(t, []string{"DE1234567890", "FR12345678901", "EE123456789", "DE1234567890"}, vats)
+	for i := 1; i < len(times); i++ {
+		assert.GreaterOrEqual(t, times[i].Sub(times[i-1]), 15*time.Millisecond)
+	}
+
+	scheduler = NewScheduler(v, failingSource{}, sink, nil)
+	assert.EqualError(t, scheduler.Run(context.Background()), "source down")
+	assert.Equal(t, defaultRevalidationInterval, scheduler.interval)
+}
+
+func TestSchedulerChanges(t *testing.T) {
+	responses := []string{
+		`{"countryCode":"DE","vatNumber":"1234567890","valid":true,"name":"ACME"}`,
+		`{"countryCode":"DE","vatNumber":"1234567890","valid":true,"name":"ACME"}`,
+		`{"countryCode":"DE","vatNumber":"1234567890","valid":true,"name":"ACME GmbH"}`,
+		`{"countryCode":"DE","vatNumber":"1234567890","valid":false}`,
+	}
+	calls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body := responses[min(calls, len(responses)-1)]
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var changes []ValidityChange
+	sink := ResultSinkFunc(func(ctx context.Context, result BulkResult) {
+		if calls == len(responses) {
+			cancel()
+		}
+	})
+
+	scheduler := NewScheduler(v, VatList{"de1234567890\u200b"}, sink, &SchedulerConfig{
+		Interval: time.Millisecond,
+		OnChange: func(ctx context.Context, change ValidityChange) {
+			changes = append(changes, change)
+		},
+	})
+	assert.ErrorIs(t, scheduler.Run(ctx), context.Canceled)
+
+	assert.Len(t, changes, 2)
+	assert.Equal(t, "DE1234567890", changes[0].Vat)
+	assert.Equal(t, "ACME", changes[0].Previous.Name)
+	assert.Equal(t, "ACME GmbH", changes[0].Current.Name)
+	assert.True(t, changes[1].Previous.Valid)
+	assert.False(t, changes[1].Current.Valid)
+}
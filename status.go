@@ -0,0 +1,309 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultStatusCacheTTL = time.Minute
+
+// ErrCountryUnavailable is returned by Check when ClientConfig.PreflightAvailability
+// is set and the member state is reported as not available.
+var ErrCountryUnavailable = errors.New("member state unavailable")
+
+// ErrVowUnavailable is returned by Healthy when VIES reports itself as not
+// available.
+var ErrVowUnavailable = errors.New("VIES reports VoW unavailable")
+
+// Availability of VIES or of a member state as reported by Status.
+type Availability string
+
+const (
+	AvailabilityAvailable          Availability = "Available"
+	AvailabilityUnavailable        Availability = "Unavailable"
+	AvailabilityMonitoringDisabled Availability = "Monitoring Disabled"
+)
+
+// VowCountryCode identifies VIES itself in a CountryChange.
+const VowCountryCode = "VoW"
+
+type ChangeKind string
+
+const (
+	BecameAvailable    ChangeKind = "became_available"
+	BecameUnavailable  ChangeKind = "became_unavailable"
+	MonitoringDisabled ChangeKind = "monitoring_disabled"
+)
+
+// CountryChange describes the availability change of a member state, or of
+// VIES itself under VowCountryCode, between two statuses.
+type CountryChange struct {
+	CountryCode string       `json:"countryCode"`
+	Kind        ChangeKind   `json:"kind"`
+	Previous    Availability `json:"previous"`
+	Current     Availability `json:"current"`
+}
+
+// Diff returns the changes from previous to status. Member states which are
+// new in status are reported as well, those which disappeared are not.
+// Nothing is reported when previous is nil.
+func (status *Status) Diff(previous *Status) []CountryChange {
+
+	if previous == nil {
+		return nil
+	}
+
+	var changes []CountryChange
+
+	if previous.Vow.Available != status.Vow.Available {
+		changes = append(changes, newCountryChange(
+			VowCountryCode,
+			vowAvailability(previous.Vow.Available),
+			vowAvailability(status.Vow.Available),
+		))
+	}
+
+	before := make(map[CountryCode]Availability, len(previous.Countries))
+	for _, country := range previous.Countries {
+		before[country.CountryCode] = country.Availability
+	}
+	for _, country := range status.Countries {
+		if was, ok := before[country.CountryCode]; !ok || was != country.Availability {
+			changes = append(changes, newCountryChange(string(country.CountryCode), was, country.Availability))
+		}
+	}
+
+	return changes
+}
+
+func newCountryChange(countryCode string, previous, current Availability) CountryChange {
+
+	kind := BecameUnavailable
+	switch current {
+	case AvailabilityAvailable:
+		kind = BecameAvailable
+	case AvailabilityMonitoringDisabled:
+		kind = MonitoringDisabled
+	}
+
+	return CountryChange{
+		CountryCode: countryCode,
+		Kind:        kind,
+		Previous:    previous,
+		Current:     current,
+	}
+}
+
+func vowAvailability(available bool) Availability {
+	if available {
+		return AvailabilityAvailable
+	}
+	return AvailabilityUnavailable
+}
+
+// Country returns the availability of a member state, false if the status
+// does not list it.
+func (status *Status) Country(countryCode string) (Availability, bool) {
+	for _, country := range status.Countries {
+		if strings.EqualFold(string(country.CountryCode), countryCode) {
+			return country.Availability, true
+		}
+	}
+	return "", false
+}
+
+// AvailableCountries returns the codes of the member states reported
+// Available.
+func (status *Status) AvailableCountries() []string {
+	return status.countries(AvailabilityAvailable)
+}
+
+// UnavailableCountries returns the codes of the member states reported
+// Unavailable. Member states with monitoring disabled are in neither list.
+func (status *Status) UnavailableCountries() []string {
+	return status.countries(AvailabilityUnavailable)
+}
+
+func (status *Status) countries(availability Availability) []string {
+	var codes []string
+	for _, country := range status.Countries {
+		if country.Availability == availability {
+			codes = append(codes, string(country.CountryCode))
+		}
+	}
+	return codes
+}
+
+// Summary counts the member states per availability.
+func (status *Status) Summary() map[Availability]int {
+	summary := make(map[Availability]int)
+	for _, country := range status.Countries {
+		summary[country.Availability]++
+	}
+	return summary
+}
+
+// WaitForCountry polls Status every pollInterval until the member state
+// reports Available. Failed polls are retried, when the context is done its
+// error is returned together with the last poll error, if any.
+func (client *Client) WaitForCountry(ctx context.Context, countryCode string, pollInterval time.Duration) error {
+
+	var delay time.Duration
+	var lastErr error
+	for {
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return fmt.Errorf("%w: %w", ctx.Err(), lastErr)
+			}
+			return ctx.Err()
+		case <-client.clock.After(delay):
+		}
+
+		status, err := client.Status(ctx)
+		if err == nil {
+			availability, ok := status.Country(countryCode)
+			if !ok {
+				return errCountryNotListed(countryCode)
+			}
+			if availability == AvailabilityAvailable {
+				return nil
+			}
+		}
+		lastErr = err
+		delay = pollInterval
+	}
+}
+
+// Healthy returns nil when the endpoint is reachable and VIES reports
+// itself available, for use in readiness probes. The status is reused for
+// ClientConfig.StatusCacheTTL.
+func (client *Client) Healthy(ctx context.Context) error {
+
+	status, err := client.cachedStatus(ctx)
+	if err != nil {
+		return err
+	}
+	if !status.Vow.Available {
+		return ErrVowUnavailable
+	}
+	return nil
+}
+
+// CountryAvailable returns the availability of a single member state. The
+// status is reused for ClientConfig.StatusCacheTTL.
+func (client *Client) CountryAvailable(ctx context.Context, countryCode string) (Availability, error) {
+
+	status, err := client.cachedStatus(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	availability, ok := status.Country(countryCode)
+	if !ok {
+		return "", errCountryNotListed(countryCode)
+	}
+	return availability, nil
+}
+
+type statusCache struct {
+	ttl          time.Duration
+	refreshAhead bool
+
+	mu      sync.Mutex
+	status  *Status
+	fetched time.Time
+	// fetch is the fetch in progress, shared by the callers
+	fetch *statusFetch
+}
+
+// statusFetch is a fetch of the status, done once its channel is closed.
+type statusFetch struct {
+	done   chan struct{}
+	status *Status
+	err    error
+}
+
+func (client *Client) cachedStatus(ctx context.Context) (*Status, error) {
+
+	cache := client.status
+	if cache.ttl <= 0 {
+		return client.Status(ctx)
+	}
+
+	cache.mu.Lock()
+	age := client.clock.Now().Sub(cache.fetched)
+	if status := cache.status; status != nil && age < cache.ttl {
+		// refresh in the background once three quarters of the TTL passed
+		// so frequent callers never wait for an expired status
+		if cache.refreshAhead && age >= cache.ttl*3/4 {
+			client.startStatusFetch(ctx)
+		}
+		cache.mu.Unlock()
+		return status, nil
+	}
+	fetch := client.startStatusFetch(ctx)
+	cache.mu.Unlock()
+
+	// the fetch isn't bound to the context of the caller which started it,
+	// each caller stops waiting when its own context is done
+	select {
+	case <-fetch.done:
+		return fetch.status, fetch.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// startStatusFetch returns the fetch in progress, or starts one, with
+// cache.mu held.
+func (client *Client) startStatusFetch(ctx context.Context) *statusFetch {
+
+	cache := client.status
+	if cache.fetch != nil {
+		return cache.fetch
+	}
+	fetch := &statusFetch{done: make(chan struct{})}
+	cache.fetch = fetch
+
+	go func() {
+		status, err := client.Status(context.WithoutCancel(ctx))
+
+		cache.mu.Lock()
+		cache.fetch = nil
+		if err == nil {
+			cache.status = status
+			cache.fetched = client.clock.Now()
+		}
+		cache.mu.Unlock()
+
+		fetch.status, fetch.err = status, err
+		close(fetch.done)
+	}()
+	return fetch
+}
+
+func errCountryNotListed(countryCode string) error {
+	return fmt.Errorf("country %s is not listed in the VIES status", countryCode)
+}
+
+// preflightCheck fails when the status reports the member state as
+// unavailable. Any other availability, such as monitoring disabled, failing
+// to fetch the status, or a member state missing from it lets the check go
+// ahead.
+func (client *Client) preflightCheck(ctx context.Context, countryCode string) error {
+
+	if !client.preflight {
+		return nil
+	}
+
+	availability, err := client.CountryAvailable(ctx, countryCode)
+	if err != nil || availability != AvailabilityUnavailable {
+		return nil
+	}
+	return fmt.Errorf("%w %s: %s", ErrCountryUnavailable, countryCode, availability)
+}
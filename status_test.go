@@ -0,0 +1,492 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusDiff(t *testing.T) {
+
+	previous := &Status{
+		Vow: StatusVow{Available: true},
+		Countries: []CountryStatus{
+			{CountryCode: "DE", Availability: AvailabilityAvailable},
+			{CountryCode: "FR", Availability: AvailabilityUnavailable},
+			{CountryCode: "IT", Availability: AvailabilityAvailable},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		previous *Status
+		current  *Status
+		changes  []CountryChange
+	}{
+		{
+			name:     "no previous",
+			previous: nil,
+			current:  previous,
+			changes:  nil,
+		},
+		{
+			name:     "unchanged",
+			previous: previous,
+			current:  previous,
+			changes:  nil,
+		},
+		{
+			name:     "countries",
+			previous: previous,
+			current: &Status{
+				Vow: StatusVow{Available: true},
+				Countries: []CountryStatus{
+					{CountryCode: "DE", Availability: AvailabilityUnavailable},
+					{CountryCode: "FR", Availability: AvailabilityAvailable},
+					{CountryCode: "IT", Availability: AvailabilityMonitoringDisabled},
+					{CountryCode: "XI", Availability: AvailabilityAvailable},
+				},
+			},
+			changes: []CountryChange{
+				{CountryCode: "DE", Kind: BecameUnavailable, Previous: AvailabilityAvailable, Current: AvailabilityUnavailable},
+				{CountryCode: "FR", Kind: BecameAvailable, Previous: AvailabilityUnavailable, Current: AvailabilityAvailable},
+				{CountryCode: "IT", Kind: MonitoringDisabled, Previous: AvailabilityAvailable, Current: AvailabilityMonitoringDisabled},
+				{CountryCode: "XI", Kind: BecameAvailable, Previous: "", Current: AvailabilityAvailable},
+			},
+		},
+		{
+			name:     "vow",
+			previous: previous,
+			current:  &Status{Vow: StatusVow{Available: false}, Countries: previous.Countries},
+			changes: []CountryChange{
+				{CountryCode: VowCountryCode, Kind: BecameUnavailable, Previous: AvailabilityAvailable, Current: AvailabilityUnavailable},
+			},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.changes, tt.current.Diff(tt.previous))
+		})
+	}
+}
+
+func TestStatusSummary(t *testing.T) {
+
+	status := &Status{
+		Countries: []CountryStatus{
+			{CountryCode: "AT", Availability: AvailabilityAvailable},
+			{CountryCode: "DE", Availability: AvailabilityUnavailable},
+			{CountryCode: "FR", Availability: AvailabilityAvailable},
+			{CountryCode: "IT", Availability: AvailabilityMonitoringDisabled},
+		},
+	}
+
+	assert.Equal(t, []string{"AT", "FR"}, status.AvailableCountries())
+	assert.Equal(t, []string{"DE"}, status.UnavailableCountries())
+	assert.Equal(t, map[Availability]int{
+		AvailabilityAvailable:          2,
+		AvailabilityUnavailable:        1,
+		AvailabilityMonitoringDisabled: 1,
+	}, status.Summary())
+
+	availability, ok := status.Country("it")
+	assert.True(t, ok)
+	assert.Equal(t, AvailabilityMonitoringDisabled, availability)
+
+	empty := &Status{}
+	assert.Empty(t, empty.AvailableCountries())
+	assert.Empty(t, empty.Summary())
+}
+
+func TestWaitForCountry(t *testing.T) {
+
+	cases := []struct {
+		name      string
+		responses []string
+		timeout   time.Duration
+		err       string
+		calls     int
+	}{
+		{
+			name: "available",
+			responses: []string{
+				`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Unavailable"}]}`,
+				`!`,
+				`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"}]}`,
+			},
+			timeout: time.Second,
+			calls:   3,
+		},
+		{
+			name: "unknown country",
+			responses: []string{
+				`{"vow":{"available":true},"countries":[{"countryCode":"FR","availability":"Available"},{"countryCode":"IT","availability":"Monitoring Disabled"}]}`,
+			},
+			timeout: time.Second,
+			err:     "country de is not listed in the VIES status",
+			calls:   1,
+		},
+		{
+			name: "deadline",
+			responses: []string{
+				`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Unavailable"}]}`,
+			},
+			timeout: 20 * time.Millisecond,
+			err:     context.DeadlineExceeded.Error(),
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				body := tt.responses[min(calls, len(tt.responses)-1)]
+				calls++
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString(body)),
+					Header:     make(http.Header),
+				}
+			})
+
+			v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+			assert.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			err = v.WaitForCountry(ctx, "de", time.Millisecond)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+			if tt.calls > 0 {
+				assert.Equal(t, tt.calls, calls)
+			}
+		})
+	}
+}
+
+func TestStatusClock(t *testing.T) {
+
+	calls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls++
+		availability := "Unavailable"
+		if calls > 2 {
+			availability = "Available"
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"` + availability + `"}]}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	clock := newTestClock()
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", StatusCacheTTL: time.Minute, Clock: clock})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	assert.NoError(t, v.WaitForCountry(ctx, "DE", time.Hour))
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{time.Hour, time.Hour}, clock.Waits())
+
+	// the cached status expires with the clock of the client
+	_, err = v.CountryAvailable(ctx, "DE")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls)
+	clock.Advance(59 * time.Second)
+	_, err = v.CountryAvailable(ctx, "DE")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, calls)
+	clock.Advance(time.Second)
+	_, err = v.CountryAvailable(ctx, "DE")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, calls)
+}
+
+func TestCountryAvailable(t *testing.T) {
+
+	cases := []struct {
+		name         string
+		ttl          time.Duration
+		countryCode  string
+		availability Availability
+		err          string
+		calls        int
+	}{
+		{
+			name:         "cached",
+			ttl:          time.Minute,
+			countryCode:  "DE",
+			availability: AvailabilityUnavailable,
+			calls:        1,
+		},
+		{
+			name:         "not cached",
+			countryCode:  "fr",
+			availability: AvailabilityMonitoringDisabled,
+			calls:        2,
+		},
+		{
+			name:        "not listed",
+			ttl:         time.Minute,
+			countryCode: "XX",
+			err:         "country XX is not listed in the VIES status",
+			calls:       1,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				calls++
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Unavailable"},{"countryCode":"FR","availability":"Monitoring Disabled"}]}`)),
+					Header:     make(http.Header),
+				}
+			})
+
+			v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", StatusCacheTTL: tt.ttl})
+			assert.NoError(t, err)
+
+			for range 2 {
+				availability, err := v.CountryAvailable(context.Background(), tt.countryCode)
+				if tt.err == "" {
+					assert.NoError(t, err)
+				} else {
+					assert.EqualError(t, err, tt.err)
+				}
+				assert.Equal(t, tt.availability, availability)
+			}
+			assert.Equal(t, tt.calls, calls)
+		})
+	}
+}
+
+func TestPreflightAvailability(t *testing.T) {
+
+	cases := []struct {
+		name   string
+		vat    string
+		status int
+		err    error
+		paths  []string
+	}{
+		{
+			name:   "unavailable",
+			vat:    "DE123456789",
+			status: http.StatusOK,
+			err:    ErrCountryUnavailable,
+			paths:  []string{"/check-status"},
+		},
+		{
+			name:   "available",
+			vat:    "FR12345678901",
+			status: http.StatusOK,
+			paths:  []string{"/check-status", "/check-vat-number"},
+		},
+		{
+			name:   "monitoring disabled",
+			vat:    "IT12345678901",
+			status: http.StatusOK,
+			paths:  []string{"/check-status", "/check-vat-number"},
+		},
+		{
+			name:   "status failed",
+			vat:    "DE123456789",
+			status: http.StatusInternalServerError,
+			paths:  []string{"/check-status", "/check-vat-number"},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				paths = append(paths, req.URL.Path)
+				if req.URL.Path == "/check-status" {
+					return &http.Response{
+						StatusCode: tt.status,
+						Body:       io.NopCloser(bytes.NewBufferString(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Unavailable"},{"countryCode":"FR","availability":"Available"}]}`)),
+						Header:     make(http.Header),
+					}
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"valid":true}`)),
+					Header:     make(http.Header),
+				}
+			})
+
+			v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", PreflightAvailability: true})
+			assert.NoError(t, err)
+
+			_, err = v.Check(context.Background(), tt.vat)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.paths, paths)
+		})
+	}
+}
+
+func TestHealthy(t *testing.T) {
+
+	cases := []struct {
+		name string
+		code int
+		body string
+		err  string
+	}{
+		{
+			name: "healthy",
+			code: http.StatusOK,
+			body: `{"vow":{"available":true},"countries":[]}`,
+		},
+		{
+			name: "vow unavailable",
+			code: http.StatusOK,
+			body: `{"vow":{"available":false},"countries":[]}`,
+			err:  "VIES reports VoW unavailable",
+		},
+		{
+			name: "unreachable",
+			code: http.StatusInternalServerError,
+			body: `{"errorWrappers":[{"error":"SERVICE_UNAVAILABLE","message":"down"}]}`,
+			err:  "SERVICE_UNAVAILABLE: down",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewTestClient(func(req *http.Request) *http.Response {
+				return &http.Response{
+					StatusCode: tt.code,
+					Body:       io.NopCloser(bytes.NewBufferString(tt.body)),
+					Header:     make(http.Header),
+				}
+			})
+
+			v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+			assert.NoError(t, err)
+
+			err = v.Healthy(context.Background())
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestStatusRefreshAhead(t *testing.T) {
+
+	var mu sync.Mutex
+	calls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		availability := "Unavailable"
+		if calls > 1 {
+			availability = "Available"
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"` + availability + `"}]}`)),
+			Header:     make(http.Header),
+		}
+	})
+	callCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls
+	}
+
+	v, err := NewClient(&ClientConfig{
+		HttpClient:         client,
+		EndpointUrl:        "https://example.com/",
+		StatusCacheTTL:     200 * time.Millisecond,
+		StatusRefreshAhead: true,
+	})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	availability, err := v.CountryAvailable(ctx, "DE")
+	assert.NoError(t, err)
+	assert.Equal(t, AvailabilityUnavailable, availability)
+
+	// past three quarters of the TTL the cached status is still returned
+	// while a refresh runs in the background
+	time.Sleep(160 * time.Millisecond)
+	availability, err = v.CountryAvailable(ctx, "DE")
+	assert.NoError(t, err)
+	assert.Equal(t, AvailabilityUnavailable, availability)
+
+	assert.Eventually(t, func() bool {
+		availability, err := v.CountryAvailable(ctx, "DE")
+		return err == nil && availability == AvailabilityAvailable
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 2, callCount())
+	assert.NoError(t, v.Healthy(ctx))
+	assert.Equal(t, 2, callCount())
+}
+
+func TestCachedStatusConcurrent(t *testing.T) {
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		calls.Add(1)
+		<-release
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"}]}`)),
+			Header:     make(http.Header),
+		}
+	})
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", StatusCacheTTL: time.Minute})
+	assert.NoError(t, err)
+
+	// the caller starting the fetch gives up, the others still get the status
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := v.CountryAvailable(ctx, "DE")
+		first <- err
+	}()
+	assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			availability, err := v.CountryAvailable(context.Background(), "DE")
+			assert.NoError(t, err)
+			assert.Equal(t, AvailabilityAvailable, availability)
+		}()
+	}
+	cancel()
+	assert.ErrorIs(t, <-first, context.Canceled)
+
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), calls.Load())
+}
@@ -1,15 +1,40 @@
 package vies
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 )
 
-//const (
-//	AvailabilityAvailable          Availability = "available"
-//	AvailabilityUnavailable        Availability = "unavailable"
-//	AvailabilityMonitoringDisabled Availability = "monitoring disabled"
-//)
+// ErrInvalidVat is returned when a VAT number is rejected before any request
+// is sent to VIES.
+var ErrInvalidVat = errors.New("invalid VAT provided")
+
+// ErrUnexpectedResponse is returned with StrictDecoding when a response
+// doesn't match the expected schema.
+var ErrUnexpectedResponse = errors.New("unexpected VIES response")
+
+// ErrResponseTooLarge is returned when a response body exceeds
+// ClientConfig.MaxResponseSize.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ErrCountryNotAllowed is returned by Check for member states outside
+// ClientConfig.AllowedCountries or in ClientConfig.BlockedCountries.
+var ErrCountryNotAllowed = errors.New("member state not allowed")
+
+// ClientInterface is the part of Client applications use to check VAT
+// numbers, for them to take a fake in tests, such as viesmock.Client.
+type ClientInterface interface {
+	Check(ctx context.Context, vat string, opts ...CheckOption) (*CheckResult, error)
+	CheckNumber(ctx context.Context, countryCode, vatNumber string, opts ...CheckOption) (*CheckResult, error)
+	Valid(ctx context.Context, vat string, opts ...CheckOption) (bool, error)
+	Status(ctx context.Context) (*Status, error)
+}
+
+var _ ClientInterface = (*Client)(nil)
 
 type HttpClientInterface interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -22,6 +47,9 @@ type BatchResponseHandlerInterface interface {
 type ApiError struct {
 	Err     string
 	Message string
+	// CountryCode is the member state of the number Check failed for,
+	// used by Explain.
+	CountryCode string
 }
 
 func (e *ApiError) Error() string {
@@ -29,12 +57,36 @@ func (e *ApiError) Error() string {
 }
 
 type CheckResult struct {
-	CountryCode string `json:"countryCode"`
-	Address     string `json:"address"`
-	VatNumber   string `json:"vatNumber"`
-	Vat         string `json:"vat"`
-	Valid       bool   `json:"valid"`
-	Name        string `json:"name"`
+	CountryCode CountryCode `json:"countryCode"`
+	Address     string      `json:"address"`
+	VatNumber   string      `json:"vatNumber"`
+	Vat         string      `json:"vat"`
+	Valid       bool        `json:"valid"`
+	Name        string      `json:"name"`
+	RequestDate string      `json:"requestDate,omitempty"`
+	// RequestIdentifier is the consultation number issued by VIES when the
+	// check was made on behalf of a requester.
+	RequestIdentifier string `json:"requestIdentifier,omitempty"`
+	// Registry names the register which produced the result, it is empty
+	// for VIES.
+	Registry string `json:"registry,omitempty"`
+	// Extra holds the fields of the VIES response CheckResult doesn't
+	// know, when ClientConfig.CaptureExtraFields is set.
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
+	// Raw is the body of the VIES response, when
+	// ClientConfig.KeepRawResponse is set.
+	Raw json.RawMessage `json:"raw,omitempty"`
+	// ResponseHeaders holds the headers of the VIES response listed in
+	// ClientConfig.ResponseHeaders.
+	ResponseHeaders http.Header `json:"responseHeaders,omitempty"`
+	// CheckedAt is when VIES was consulted and RevalidateAfter when the
+	// result should be verified again, both set with
+	// ClientConfig.Revalidation.
+	CheckedAt       *time.Time `json:"checkedAt,omitempty"`
+	RevalidateAfter *time.Time `json:"revalidateAfter,omitempty"`
+	// Proof signs the fields VIES returned, set with
+	// ClientConfig.ProofSigner, see VerifyProof.
+	Proof *Proof `json:"proof,omitempty"`
 	//Error       string `json:"error,omitempty"`
 }
 
@@ -55,6 +107,15 @@ type Configuration struct {
 type Status struct {
 	Vow       StatusVow       `json:"vow"`
 	Countries []CountryStatus `json:"countries"`
+	// Extra holds the top-level fields of the VIES response Status
+	// doesn't know, when ClientConfig.CaptureExtraFields is set.
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
+	// Raw is the body of the VIES response, when
+	// ClientConfig.KeepRawResponse is set.
+	Raw json.RawMessage `json:"raw,omitempty"`
+	// ResponseHeaders holds the headers of the VIES response listed in
+	// ClientConfig.ResponseHeaders.
+	ResponseHeaders http.Header `json:"responseHeaders,omitempty"`
 }
 
 type StatusVow struct {
@@ -62,8 +123,8 @@ type StatusVow struct {
 }
 
 type CountryStatus struct {
-	CountryCode  string `json:"countryCode"`
-	Availability string `json:"availability"`
+	CountryCode  CountryCode  `json:"countryCode"`
+	Availability Availability `json:"availability"`
 }
 
 type statusErrorResponse struct {
@@ -77,6 +138,8 @@ type statusErrorWrapper struct {
 }
 
 type checkRequest struct {
-	CountryCode string `json:"countryCode"`
-	VatNumber   string `json:"vatNumber"`
+	CountryCode              CountryCode `json:"countryCode"`
+	VatNumber                string      `json:"vatNumber"`
+	RequesterMemberStateCode CountryCode `json:"requesterMemberStateCode,omitempty"`
+	RequesterNumber          string      `json:"requesterNumber,omitempty"`
 }
@@ -0,0 +1,106 @@
+package vies
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+// testVatTemplates are the shapes of the numbers of GenerateTestVat, each
+// # being a digit, which fit vatFormats and numberRules.
+var testVatTemplates = map[string]string{
+	"AT": "U########",
+	"BE": "0#########",
+	"BG": "#########",
+	"CY": "########L",
+	"CZ": "########",
+	"DE": "#########",
+	"DK": "########",
+	"EE": "#########",
+	"EL": "#########",
+	"ES": "########Z",
+	"FI": "########",
+	"FR": "###########",
+	"HR": "###########",
+	"HU": "########",
+	"IE": "#######W",
+	"IT": "###########",
+	"LT": "#########",
+	"LU": "########",
+	"LV": "###########",
+	"MT": "########",
+	"NL": "#########B01",
+	"PL": "##########",
+	"PT": "#########",
+	"RO": "##########",
+	"SE": "##########01",
+	"SI": "########",
+	"SK": "##########",
+	"XI": "#########",
+}
+
+// testVatMarker are the first digits of the numbers of GenerateTestVat, whose
+// digits taken as an integer are also 1 modulo 97.
+const testVatMarker = "99"
+
+// GenerateTestVat returns a random VAT number of the member state country
+// which passes the offline checks of ValidFormat and
+// ClientConfig.PreflightFormat, for fixtures and demos. The numbers are
+// marked as synthetic, IsTestVat recognizes them, which makes it unlikely,
+// though not impossible, that one is a real VAT number. It returns an empty
+// string for unknown country codes.
+func GenerateTestVat(country CountryCode) string {
+
+	template, ok := testVatTemplates[string(country)]
+	if !ok {
+		return ""
+	}
+	number := []byte(template)
+	var digits []int
+	for i, c := range number {
+		if c == '#' {
+			digits = append(digits, i)
+			number[i] = byte('0' + rand.IntN(10))
+		}
+	}
+	copy(number[digits[0]:], testVatMarker)
+
+	// the last two digits make the number 1 modulo 97
+	a, b := digits[len(digits)-2], digits[len(digits)-1]
+	for check := range 100 {
+		number[a], number[b] = byte('0'+check/10), byte('0'+check%10)
+		if testVatChecksum(string(number)) {
+			break
+		}
+	}
+	return string(country) + string(number)
+}
+
+// IsTestVat reports whether vat is a number of GenerateTestVat.
+func IsTestVat(vat string) bool {
+	v, err := ParseVatNumber(vat)
+	if err != nil {
+		return false
+	}
+	template, ok := testVatTemplates[v.CountryCode()]
+	if !ok || len(v.Number()) != len(template) {
+		return false
+	}
+	number := v.Number()
+	return strings.HasPrefix(number[strings.IndexByte(template, '#'):], testVatMarker) && testVatChecksum(number)
+}
+
+// testVatChecksum reports whether the digits of number, taken as an
+// integer, are 1 modulo 97.
+func testVatChecksum(number string) bool {
+	remainder := 0
+	for _, c := range number {
+		if isDigit(c) {
+			remainder = (remainder*10 + int(c-'0')) % 97
+		}
+	}
+	return remainder == 1
+}
+
+func isDigit(c rune) bool {
+	return c >= '0' && c <= '9'
+}
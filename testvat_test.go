@@ -0,0 +1,25 @@
+package vies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateTestVat(t *testing.T) {
+
+	assert.Len(t, testVatTemplates, len(vatFormats))
+	for code := range vatFormats {
+		for range 50 {
+			vat := GenerateTestVat(CountryCode(code))
+			assert.True(t, ValidFormat(vat), vat)
+			assert.Nil(t, checkNumber(vat, code, vat[2:]), vat)
+			assert.True(t, IsTestVat(vat), vat)
+		}
+	}
+
+	assert.Equal(t, "", GenerateTestVat("US"))
+	assert.False(t, IsTestVat("EE100354546"))
+	assert.False(t, IsTestVat("DE123456789"))
+	assert.False(t, IsTestVat("not a number"))
+}
@@ -0,0 +1,60 @@
+package vies
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	attributeCountryCode = "vies.country_code"
+	attributeValid       = "vies.valid"
+	attributeRetryCount  = "vies.retry_count"
+	attributeErrorCode   = "vies.error_code"
+	attributeHttpStatus  = "http.response.status_code"
+)
+
+// TracerInterface creates the spans of a client and propagates their trace
+// context to VIES. The viesotel module provides an OpenTelemetry
+// implementation.
+type TracerInterface interface {
+	// Start starts the span of a client call, with attributes holding
+	// strings, bools and ints, and returns the context carrying it.
+	Start(ctx context.Context, name string, attributes map[string]any) (context.Context, SpanInterface)
+	// Inject adds the trace context of ctx to the headers of a request.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// SpanInterface is a span started by TracerInterface.
+type SpanInterface interface {
+	// End sets attributes on the span and ends it, as failed when err
+	// isn't nil.
+	End(attributes map[string]any, err error)
+}
+
+func (client *Client) startSpan(ctx context.Context, name string, attributes map[string]any) (context.Context, SpanInterface) {
+	if client.tracer == nil {
+		return ctx, nil
+	}
+	return client.tracer.Start(ctx, name, attributes)
+}
+
+func (client *Client) endSpan(span SpanInterface, obs *observation, err error, attributes map[string]any) {
+	if span == nil {
+		return
+	}
+
+	if attributes == nil {
+		attributes = make(map[string]any)
+	}
+	attributes[attributeRetryCount] = max(obs.attempts-1, 0)
+	if obs.statusCode != 0 {
+		attributes[attributeHttpStatus] = obs.statusCode
+	}
+
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		attributes[attributeErrorCode] = apiErr.Err
+	}
+	span.End(attributes, err)
+}
@@ -0,0 +1,89 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordedSpan struct {
+	name       string
+	attributes map[string]any
+	err        error
+}
+
+type spanKey struct{}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attributes map[string]any) (context.Context, SpanInterface) {
+	span := &recordedSpan{name: name, attributes: make(map[string]any)}
+	for k, v := range attributes {
+		span.attributes[k] = v
+	}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, name), span
+}
+
+func (t *recordingTracer) Inject(ctx context.Context, header http.Header) {
+	header.Set("X-Span", ctx.Value(spanKey{}).(string))
+}
+
+func (s *recordedSpan) End(attributes map[string]any, err error) {
+	for k, v := range attributes {
+		s.attributes[k] = v
+	}
+	s.err = err
+}
+
+func TestTracing(t *testing.T) {
+	var spans []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		spans = append(spans, req.Header.Get("X-Span"))
+		if req.URL.Path == "/check-status" {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"errorWrappers":[{"error":"SERVICE_UNAVAILABLE","message":"down"}]}`)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	tracer := &recordingTracer{}
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", Tracer: tracer})
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	_, err = v.Status(context.Background())
+	assert.Error(t, err)
+
+	assert.Len(t, tracer.spans, 2)
+	assert.Equal(t, []string{"vies.Check", "vies.Status"}, spans)
+
+	check := tracer.spans[0]
+	assert.Equal(t, "vies.Check", check.name)
+	assert.Equal(t, map[string]any{
+		attributeCountryCode: "EE",
+		attributeValid:       true,
+		attributeRetryCount:  0,
+		attributeHttpStatus:  200,
+	}, check.attributes)
+	assert.NoError(t, check.err)
+
+	status := tracer.spans[1]
+	assert.Equal(t, "vies.Status", status.name)
+	assert.Error(t, status.err)
+	assert.Equal(t, "SERVICE_UNAVAILABLE", status.attributes[attributeErrorCode])
+}
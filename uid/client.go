@@ -0,0 +1,197 @@
+// Package uid checks Swiss UID/VAT numbers (CHE-123.456.789 MWST) against
+// the public services of the Swiss UID register and returns
+// vies.CheckResult.
+package uid
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/alytsin/go-vies"
+)
+
+const (
+	apiEndpointUrl = "https://www.uid-wse.admin.ch/V5.0/PublicServices.svc"
+	soapAction     = "http://www.uid.admin.ch/xmlns/uid-wse/IPublicServices/GetByUID"
+
+	countryCode = "CH"
+	uidPrefix   = "CHE"
+
+	// vatStatusActive is the vatStatus of an organisation registered for VAT.
+	vatStatusActive = "2"
+
+	// Registry marks the results of this package in vies.CheckResult.
+	Registry = "UID"
+)
+
+const getByUIDRequest = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:uid="http://www.uid.admin.ch/xmlns/uid-wse" xmlns:ech="http://www.ech.ch/xmlns/eCH-0097/5">
+<soap:Body><uid:GetByUID><uid:uid><ech:uidOrganisationIdCategorie>CHE</ech:uidOrganisationIdCategorie><ech:uidOrganisationId>%s</ech:uidOrganisationId></uid:uid></uid:GetByUID></soap:Body>
+</soap:Envelope>`
+
+type Config struct {
+	HttpClient  vies.HttpClientInterface
+	EndpointUrl string
+}
+
+type Client struct {
+	endpoint   *url.URL
+	httpClient vies.HttpClientInterface
+}
+
+type envelope struct {
+	Body struct {
+		Fault *struct {
+			Code   string `xml:"faultcode"`
+			String string `xml:"faultstring"`
+		} `xml:"Fault"`
+		Organisations []organisation `xml:"GetByUIDResponse>GetByUIDResult>organisationType"`
+	} `xml:"Body"`
+}
+
+type organisation struct {
+	Name    string `xml:"organisation>organisationIdentification>organisationName"`
+	Address struct {
+		Street      string `xml:"street"`
+		HouseNumber string `xml:"houseNumber"`
+		ZipCode     string `xml:"swissZipCode"`
+		Town        string `xml:"town"`
+	} `xml:"organisation>address"`
+	VatStatus string `xml:"vatRegisterInformation>vatStatus"`
+}
+
+func NewClient(config *Config) (*Client, error) {
+
+	var client vies.HttpClientInterface
+
+	endpoint := apiEndpointUrl
+	client = vies.NewHttpClient()
+
+	if config != nil {
+		if config.EndpointUrl != "" {
+			endpoint = config.EndpointUrl
+		}
+		if config.HttpClient != nil {
+			client = config.HttpClient
+		}
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		endpoint:   u,
+		httpClient: client,
+	}, nil
+}
+
+// Check looks up a UID in any of its usual notations. The result is valid
+// when the organisation is registered for VAT.
+func (client *Client) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+
+	number, err := normalize(vat)
+	if err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf(getByUIDRequest, number)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.endpoint.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("SOAPAction", soapAction)
+
+	rsp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+
+	content, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var env envelope
+	if err := xml.NewDecoder(bytes.NewReader(content)).Decode(&env); err != nil {
+		return nil, fmt.Errorf("unexpected response structure: %w", err)
+	}
+	if fault := env.Body.Fault; fault != nil {
+		return nil, &vies.ApiError{Err: fault.Code, Message: fault.String}
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %d", rsp.StatusCode)
+	}
+
+	result := &vies.CheckResult{
+		CountryCode: countryCode,
+		VatNumber:   uidPrefix + number,
+		Vat:         uidPrefix + number,
+		Registry:    Registry,
+	}
+	if len(env.Body.Organisations) > 0 {
+		org := env.Body.Organisations[0]
+		result.Valid = org.VatStatus == vatStatusActive
+		result.Name = org.Name
+		result.Address = strings.TrimSpace(strings.Join([]string{
+			strings.TrimSpace(org.Address.Street + " " + org.Address.HouseNumber),
+			strings.TrimSpace(org.Address.ZipCode + " " + org.Address.Town),
+		}, "\n"))
+	}
+	return result, nil
+}
+
+// normalize returns the nine digits of a UID written as CHE-123.456.789,
+// CHE123456789 or with an MWST, TVA or IVA suffix, and verifies its check
+// digit.
+func normalize(vat string) (string, error) {
+
+	var b strings.Builder
+	for _, c := range strings.ToUpper(vat) {
+		if (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+		}
+	}
+	s := b.String()
+	for _, suffix := range []string{"MWST", "TVA", "IVA"} {
+		s = strings.TrimSuffix(s, suffix)
+	}
+
+	number, ok := strings.CutPrefix(s, uidPrefix)
+	if !ok || len(number) != 9 || !validCheckDigit(number) {
+		return "", &vies.ErrInvalidInput{Input: vat, CountryCode: countryCode, Reason: vies.ReasonBadFormat}
+	}
+	return number, nil
+}
+
+// validCheckDigit verifies the modulo 11 check digit of a UID.
+func validCheckDigit(number string) bool {
+
+	weights := []int{5, 4, 3, 2, 7, 6, 5, 4}
+	sum := 0
+	for i, w := range weights {
+		c := number[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		sum += int(c-'0') * w
+	}
+
+	check := 11 - sum%11
+	switch check {
+	case 11:
+		check = 0
+	case 10:
+		return false
+	}
+	return number[8] == byte('0'+check)
+}
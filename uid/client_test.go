@@ -0,0 +1,136 @@
+package uid
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+const organisationResponse = `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/">
+<s:Body>
+<GetByUIDResponse xmlns="http://www.uid.admin.ch/xmlns/uid-wse">
+<GetByUIDResult xmlns:a="http://www.uid.admin.ch/xmlns/uid-wse-shared/2">
+<a:organisationType>
+<a:organisation>
+<b:organisationIdentification xmlns:b="http://www.ech.ch/xmlns/eCH-0098/5">
+<c:uid xmlns:c="http://www.ech.ch/xmlns/eCH-0097/5"><c:uidOrganisationIdCategorie>CHE</c:uidOrganisationIdCategorie><c:uidOrganisationId>116281710</c:uidOrganisationId></c:uid>
+<c:organisationName xmlns:c="http://www.ech.ch/xmlns/eCH-0097/5">Bundesamt für Statistik</c:organisationName>
+</b:organisationIdentification>
+<b:address xmlns:b="http://www.ech.ch/xmlns/eCH-0098/5">
+<c:street xmlns:c="http://www.ech.ch/xmlns/eCH-0010/6">Espace de l'Europe</c:street>
+<c:houseNumber xmlns:c="http://www.ech.ch/xmlns/eCH-0010/6">10</c:houseNumber>
+<c:town xmlns:c="http://www.ech.ch/xmlns/eCH-0010/6">Neuchâtel</c:town>
+<c:swissZipCode xmlns:c="http://www.ech.ch/xmlns/eCH-0010/6">2010</c:swissZipCode>
+</b:address>
+</a:organisation>
+<a:vatRegisterInformation><a:vatStatus>%s</a:vatStatus></a:vatRegisterInformation>
+</a:organisationType>
+</GetByUIDResult>
+</GetByUIDResponse>
+</s:Body>
+</s:Envelope>`
+
+const emptyResponse = `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>
+<GetByUIDResponse xmlns="http://www.uid.admin.ch/xmlns/uid-wse"><GetByUIDResult/></GetByUIDResponse>
+</s:Body></s:Envelope>`
+
+const faultResponse = `<s:Envelope xmlns:s="http://schemas.xmlsoap.org/soap/envelope/"><s:Body>
+<s:Fault><faultcode>s:Client</faultcode><faultstring>Request_limit_exceeded</faultstring></s:Fault>
+</s:Body></s:Envelope>`
+
+func TestCheck(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		vat      string
+		response string
+		code     int
+		result   *vies.CheckResult
+		err      string
+	}{
+		{
+			name:     "registered",
+			vat:      "CHE-116.281.710 MWST",
+			response: strings.Replace(organisationResponse, "%s", "2", 1),
+			code:     http.StatusOK,
+			result: &vies.CheckResult{
+				CountryCode: "CH",
+				VatNumber:   "CHE116281710",
+				Vat:         "CHE116281710",
+				Valid:       true,
+				Name:        "Bundesamt für Statistik",
+				Address:     "Espace de l'Europe 10\n2010 Neuchâtel",
+				Registry:    "UID",
+			},
+		},
+		{
+			name:     "not registered for VAT",
+			vat:      "CHE116281710",
+			response: strings.Replace(organisationResponse, "%s", "3", 1),
+			code:     http.StatusOK,
+			result: &vies.CheckResult{
+				CountryCode: "CH",
+				VatNumber:   "CHE116281710",
+				Vat:         "CHE116281710",
+				Name:        "Bundesamt für Statistik",
+				Address:     "Espace de l'Europe 10\n2010 Neuchâtel",
+				Registry:    "UID",
+			},
+		},
+		{
+			name:     "unknown",
+			vat:      "che116281710",
+			response: emptyResponse,
+			code:     http.StatusOK,
+			result:   &vies.CheckResult{CountryCode: "CH", VatNumber: "CHE116281710", Vat: "CHE116281710", Registry: "UID"},
+		},
+		{
+			name:     "fault",
+			vat:      "CHE116281710",
+			response: faultResponse,
+			code:     http.StatusInternalServerError,
+			err:      "s:Client: Request_limit_exceeded",
+		},
+		{
+			name: "check digit",
+			vat:  "CHE-116.281.711",
+			err:  "invalid VAT provided CHE-116.281.711",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Contains(t, string(body), "<ech:uidOrganisationId>116281710</ech:uidOrganisationId>")
+				assert.Equal(t, soapAction, r.Header.Get("SOAPAction"))
+				w.WriteHeader(tt.code)
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client, err := NewClient(&Config{EndpointUrl: server.URL})
+			assert.NoError(t, err)
+
+			result, err := client.Check(context.Background(), tt.vat)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+			assert.Equal(t, tt.result, result)
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	client, err := NewClient(nil)
+	assert.NoError(t, err)
+	assert.NotZero(t, client.httpClient.(*http.Client).Timeout)
+}
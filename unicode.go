@@ -0,0 +1,62 @@
+package vies
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// lookAlikes maps the Cyrillic letters looking like the Latin letters of
+// VAT numbers to them.
+var lookAlikes = map[rune]rune{
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O',
+	'Р': 'P', 'С': 'C', 'Т': 'T', 'У': 'Y', 'Х': 'X', 'І': 'I', 'Ј': 'J',
+	'Ѕ': 'S', 'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y',
+	'х': 'x', 'і': 'i', 'ј': 'j', 'ѕ': 's',
+}
+
+// CleanVat repairs a VAT number pasted from an invoice or a PDF: it is NFC
+// normalized, zero-width characters are removed and full-width digits and
+// letters and Cyrillic look-alike letters are replaced by their ASCII
+// counterparts. ASCII input is returned as is.
+func CleanVat(s string) string {
+	cleaned, _ := cleanVat(s)
+	return cleaned
+}
+
+// cleanVat returns CleanVat(s) and whether s was changed.
+func cleanVat(s string) (string, bool) {
+
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s, false
+	}
+
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u200b', r == '\u200c', r == '\u200d', r == '\u2060', r == '\ufeff', r == '\u00ad':
+			// zero-width spaces and joiners, byte order marks, soft hyphens
+			return -1
+		case r >= '０' && r <= '９':
+			return '0' + r - '０'
+		case r >= 'Ａ' && r <= 'Ｚ':
+			return 'A' + r - 'Ａ'
+		case r >= 'ａ' && r <= 'ｚ':
+			return 'a' + r - 'ａ'
+		case unicode.IsSpace(r):
+			return ' '
+		}
+		if latin, ok := lookAlikes[r]; ok {
+			return latin
+		}
+		return r
+	}, norm.NFC.String(s))
+	return cleaned, cleaned != s
+}
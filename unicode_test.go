@@ -0,0 +1,71 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanVat(t *testing.T) {
+
+	cases := []struct {
+		name  string
+		input string
+		vat   string
+	}{
+		{name: "ascii", input: "EE100354546", vat: "EE100354546"},
+		{name: "zero width", input: "\ufeffEE100\u200b354\u200d546", vat: "EE100354546"},
+		{name: "full width", input: "ＥＥ１００３５４５４６", vat: "EE100354546"},
+		{name: "cyrillic", input: "ЕЕ100354546", vat: "EE100354546"},
+		{name: "no-break space", input: "EE\u00a0100354546", vat: "EE 100354546"},
+		{name: "nfc", input: "DEA\u030a", vat: "DE\u00c5"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.vat, CleanVat(tt.input))
+		})
+	}
+
+	vat, err := ParseVatNumber("ＥＥ １００ ３５４ ５４６")
+	assert.NoError(t, err)
+	assert.Equal(t, VatNumber("EE100354546"), vat)
+}
+
+func TestCheckCleanInput(t *testing.T) {
+
+	var sent []string
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		body, _ := io.ReadAll(req.Body)
+		sent = append(sent, string(body))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"100354546","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := New(WithHttpClient(client))
+	assert.NoError(t, err)
+	_, err = v.Check(context.Background(), "ЕЕ１００３５４５４６\u200b")
+	assert.NoError(t, err)
+	_, err = v.CheckNumber(context.Background(), "ＥＥ", "100354546")
+	assert.NoError(t, err)
+	assert.Len(t, sent, 2)
+	for _, body := range sent {
+		assert.Contains(t, body, `"countryCode":"EE","vatNumber":"100354546"`)
+	}
+
+	strict, err := New(WithHttpClient(client), WithStrictInput())
+	assert.NoError(t, err)
+	_, err = strict.Check(context.Background(), "EE100354546\u200b")
+	var inputErr *ErrInvalidInput
+	assert.True(t, errors.As(err, &inputErr))
+	assert.Equal(t, ReasonBadCharacters, inputErr.Reason)
+	assert.Len(t, sent, 2)
+}
@@ -5,12 +5,17 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -20,6 +25,7 @@ const (
 	apiCheckVatPath      = "check-vat-number"
 	apiBatchStatusPath   = "vat-validation"
 	apiBatchReportPath   = "vat-validation-report"
+	eoriEndpointUrl      = "https://ec.europa.eu/taxation_customs/dds2/eos/validation/services/validation"
 )
 
 var (
@@ -40,53 +46,346 @@ var (
 )
 
 type Client struct {
-	endpoint             *url.URL
+	settings             atomic.Pointer[settings]
 	httpClient           HttpClientInterface
 	batchResponseHandler BatchResponseHandlerInterface
+	cache                CacheInterface
+	metrics              MetricsInterface
+	tracer               TracerInterface
+	logger               *slog.Logger
+	redactVat            bool
+	interceptors         []Interceptor
+	retry                *RetryConfig
+	debug                io.Writer
+	send                 RoundTripperFunc
+	correlationID        func(ctx context.Context) string
+	correlationHeader    string
+	status               *statusCache
+	preflight            bool
+	preflightFormat      bool
+	strictInput          bool
+	revalidation         *RevalidationPolicy
+	proofSigner          ProofSignerInterface
+	audit                AuditSinkInterface
+	fake                 *fakeResponder
+	clock                ClockInterface
+	captureExtra         bool
+	strict               bool
+	keepRaw              bool
+	responseHeaders      []string
+	maxResponseSize      int64
+	requestTimeout       time.Duration
+}
+
+// settings are the part of a client replaced at once by Reload.
+type settings struct {
+	endpoint         *url.URL
+	eoriEndpoint     *url.URL
+	requester        string
+	rateLimit        float64
+	rateLimiter      RateLimiterInterface
+	userAgent        string
+	headers          http.Header
+	allowedCountries map[string]bool
+	blockedCountries map[string]bool
+	// config is the configuration the client was built from, updated by
+	// Reload, which Clone starts from.
+	config *ClientConfig
 }
 
 type ClientConfig struct {
-	HttpClient           HttpClientInterface
+	HttpClient HttpClientInterface
+	// Timeout, when HttpClient is nil, is the timeout of the client of
+	// NewHttpClient sending the requests, 30s by default.
+	Timeout              time.Duration
 	EndpointUrl          string
 	BatchResponseHandler BatchResponseHandlerInterface
+	// EoriEndpointUrl overrides the URL of the EORI validation service
+	// used by CheckEori.
+	EoriEndpointUrl string
+	// Requester is the VAT number of the party on whose behalf checks are
+	// made. When set, VIES returns a consultation number with each result.
+	Requester string
+	// Cache, when set, is consulted before and filled after each Check.
+	Cache CacheInterface
+	// RateLimiter, when set, throttles every request sent to VIES, each
+	// retry included.
+	RateLimiter RateLimiterInterface
+	// RateLimit, when RateLimiter is nil, throttles the requests to that
+	// many per second, with bursts of one. Reload keeps the limiter as long
+	// as the rate doesn't change.
+	RateLimit float64
+	// Metrics, when set, is updated with request and cache statistics.
+	Metrics MetricsInterface
+	// Tracer, when set, creates a span for each Check, CheckEori and Status
+	// call and propagates its trace context to VIES.
+	Tracer TracerInterface
+	// Logger, when set, receives a debug record for each request and a
+	// warning for each failed one. With RedactVat the logged VAT numbers
+	// are masked with MaskVat.
+	Logger    *slog.Logger
+	RedactVat bool
+	// Interceptors wrap every request sent to VIES, the first one being
+	// the outermost.
+	Interceptors []Interceptor
+	// Debug, when set, receives a dump of every request and response.
+	// Credential headers are masked, and VAT numbers too with RedactVat.
+	Debug io.Writer
+	// CorrelationID extracts the ID of the originating request from the
+	// context of a call, CorrelationIDFromContext by default. The ID is
+	// sent in CorrelationHeader (X-Correlation-ID by default) and added
+	// to logs and errors.
+	CorrelationID     func(ctx context.Context) string
+	CorrelationHeader string
+	// StatusCacheTTL is how long CountryAvailable, Healthy and the
+	// pre-flight check reuse a fetched Status, zero fetches it on every
+	// call. With StatusRefreshAhead the status is refreshed in the
+	// background before it expires.
+	StatusCacheTTL     time.Duration
+	StatusRefreshAhead bool
+	// PreflightAvailability makes Check fail with ErrCountryUnavailable,
+	// without calling VIES, when the cached status reports the member
+	// state as unavailable. StatusCacheTTL defaults to one minute then.
+	PreflightAvailability bool
+	// PreflightFormat makes Check fail with ErrInvalidInput, without
+	// calling VIES, when the length or the characters of the number don't
+	// fit its member state.
+	PreflightFormat bool
+	// StrictInput makes Check fail with ErrInvalidInput on numbers with
+	// characters CleanVat would repair, such as zero-width spaces or
+	// full-width digits, instead of repairing them.
+	StrictInput bool
+	// Revalidation, when set, stamps the results of Check with the time
+	// of the check and the date after which to verify them again.
+	Revalidation *RevalidationPolicy
+	// ProofSigner, when set, signs the results of Check so that stored
+	// results can't be altered unnoticed, see VerifyProof.
+	ProofSigner ProofSignerInterface
+	// Audit, when set, receives a record of every call to Check and
+	// CheckNumber, successful or not.
+	Audit AuditSinkInterface
+	// FakeResponses, when set, makes Check answer with these results,
+	// keyed by VAT number, without calling VIES, e.g. for demos and load
+	// tests. Other numbers are invalid, and those of FakeErrors, or of
+	// the member states whose code is a key of FakeErrors, fail with its
+	// error. Status then reports these member states unavailable.
+	FakeResponses map[string]CheckResult
+	FakeErrors    map[string]error
+	// Clock, when set, replaces the system clock for the times results
+	// are checked, signed and audited at and for the retry backoff.
+	Clock ClockInterface
+	// Retry, when set, sends requests again after transport errors and 429
+	// or 5xx responses.
+	Retry *RetryConfig
+	// UserAgent, when set, is sent in the User-Agent header of every
+	// request, and Headers, such as the API key of a corporate gateway,
+	// are added to every request.
+	UserAgent string
+	Headers   http.Header
+	// AllowedCountries, when set, restricts Check to VAT numbers of these
+	// member states, and Check rejects those of BlockedCountries. Both
+	// fail with ErrCountryNotAllowed.
+	AllowedCountries []string
+	BlockedCountries []string
+	// CaptureExtraFields keeps the fields VIES adds to its responses in
+	// CheckResult.Extra and Status.Extra instead of dropping them.
+	CaptureExtraFields bool
+	// StrictDecoding fails calls with ErrUnexpectedResponse when a
+	// response has fields the result type doesn't know or values of
+	// another type, instead of decoding what matches.
+	StrictDecoding bool
+	// KeepRawResponse keeps the body of the VIES response in
+	// CheckResult.Raw and Status.Raw, e.g. to archive it for audits.
+	KeepRawResponse bool
+	// ResponseHeaders names the headers of the VIES response copied to
+	// CheckResult.ResponseHeaders and Status.ResponseHeaders, such as
+	// request IDs and dates asked for by the EU helpdesk.
+	ResponseHeaders []string
+	// MaxResponseSize caps the size of a response body, larger ones fail
+	// with ErrResponseTooLarge. Defaults to 4 MiB.
+	MaxResponseSize int64
+	// RequestTimeout, when set, is the deadline of calls whose context has
+	// none, rate limiting and retries included.
+	RequestTimeout time.Duration
 }
 
 func NewClient(config *ClientConfig) (*Client, error) {
 
 	var client HttpClientInterface
+	var timeout time.Duration
 	var batchHandler BatchResponseHandlerInterface
+	var requester string
+	var cache CacheInterface
+	var rateLimiter RateLimiterInterface
+	var rateLimit float64
+	var metrics MetricsInterface
+	var tracer TracerInterface
+	var logger *slog.Logger
+	var redactVat bool
+	var interceptors []Interceptor
+	var retry *RetryConfig
+	var debug io.Writer
+	var statusCacheTTL time.Duration
+	var statusRefreshAhead bool
+	var preflight bool
+	var preflightFormat bool
+	var strictInput bool
+	var revalidation *RevalidationPolicy
+	var proofSigner ProofSignerInterface
+	var audit AuditSinkInterface
+	var fake *fakeResponder
+	var clock ClockInterface
+	var userAgent string
+	var headers http.Header
+	var allowedCountries map[string]bool
+	var blockedCountries map[string]bool
+	var captureExtra bool
+	var strict bool
+	var keepRaw bool
+	var responseHeaders []string
+	var requestTimeout time.Duration
+
+	correlationID := CorrelationIDFromContext
+	correlationHeader := defaultCorrelationHeader
+	maxResponseSize := int64(defaultMaxResponseSize)
 
 	endpoint := apiEndpointUrl
-	client = http.DefaultClient
+	eoriEndpoint := eoriEndpointUrl
 	batchHandler = &SpreadsheetMlReader{}
 
 	if config != nil {
+		client = config.HttpClient
+		timeout = config.Timeout
 		if config.EndpointUrl != "" {
 			endpoint = config.EndpointUrl
 		}
-		if config.HttpClient != nil {
-			client = config.HttpClient
+		if config.EoriEndpointUrl != "" {
+			eoriEndpoint = config.EoriEndpointUrl
 		}
 		if config.BatchResponseHandler != nil {
 			batchHandler = config.BatchResponseHandler
 		}
+		requester = strings.ToUpper(config.Requester)
+		cache = config.Cache
+		rateLimiter = config.RateLimiter
+		rateLimit = config.RateLimit
+		metrics = config.Metrics
+		tracer = config.Tracer
+		logger = config.Logger
+		redactVat = config.RedactVat
+		clock = config.Clock
+		interceptors = config.Interceptors
+		retry = config.Retry
+		debug = config.Debug
+		if config.CorrelationID != nil {
+			correlationID = config.CorrelationID
+		}
+		if config.CorrelationHeader != "" {
+			correlationHeader = config.CorrelationHeader
+		}
+		statusCacheTTL = config.StatusCacheTTL
+		statusRefreshAhead = config.StatusRefreshAhead
+		preflight = config.PreflightAvailability
+		preflightFormat = config.PreflightFormat
+		strictInput = config.StrictInput
+		revalidation = config.Revalidation
+		proofSigner = config.ProofSigner
+		audit = config.Audit
+		fake = newFakeResponder(config.FakeResponses, config.FakeErrors)
+		userAgent = config.UserAgent
+		headers = config.Headers.Clone()
+		allowedCountries = countrySet(config.AllowedCountries)
+		blockedCountries = countrySet(config.BlockedCountries)
+		captureExtra = config.CaptureExtraFields
+		strict = config.StrictDecoding
+		keepRaw = config.KeepRawResponse
+		responseHeaders = slices.Clone(config.ResponseHeaders)
+		if config.MaxResponseSize > 0 {
+			maxResponseSize = config.MaxResponseSize
+		}
+		requestTimeout = config.RequestTimeout
+		if preflight && statusCacheTTL == 0 {
+			statusCacheTTL = defaultStatusCacheTTL
+		}
+	}
+
+	if client == nil {
+		client = defaultHttpClient(timeout)
+	}
+	if rateLimiter == nil && rateLimit > 0 {
+		limiter := NewRateLimiter(rateLimit, 1)
+		limiter.SetClock(clockOrSystem(clock))
+		rateLimiter = limiter
 	}
 
 	u, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
 	}
+	eoriUrl, err := url.Parse(eoriEndpoint)
+	if err != nil {
+		return nil, err
+	}
 
-	return &Client{
-		endpoint:             u,
+	c := &Client{
 		httpClient:           client,
 		batchResponseHandler: batchHandler,
-	}, nil
+		cache:                cache,
+		metrics:              metrics,
+		tracer:               tracer,
+		logger:               logger,
+		redactVat:            redactVat,
+		correlationID:        correlationID,
+		correlationHeader:    correlationHeader,
+		status:               &statusCache{ttl: statusCacheTTL, refreshAhead: statusRefreshAhead},
+		preflight:            preflight,
+		preflightFormat:      preflightFormat,
+		strictInput:          strictInput,
+		revalidation:         revalidation,
+		proofSigner:          proofSigner,
+		audit:                audit,
+		fake:                 fake,
+		clock:                clockOrSystem(clock),
+		captureExtra:         captureExtra,
+		strict:               strict,
+		keepRaw:              keepRaw,
+		responseHeaders:      responseHeaders,
+		maxResponseSize:      maxResponseSize,
+		requestTimeout:       requestTimeout,
+		interceptors:         interceptors,
+		retry:                retry,
+		debug:                debug,
+	}
+	var copied ClientConfig
+	if config != nil {
+		copied = *config
+	}
+	c.settings.Store(&settings{
+		config:           &copied,
+		endpoint:         u,
+		eoriEndpoint:     eoriUrl,
+		requester:        requester,
+		rateLimit:        rateLimit,
+		rateLimiter:      rateLimiter,
+		userAgent:        userAgent,
+		headers:          headers,
+		allowedCountries: allowedCountries,
+		blockedCountries: blockedCountries,
+	})
+	c.buildSend()
+
+	if requester != "" {
+		if err := c.isValidVat(requester); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
 }
 
 func (client *Client) Configuration(ctx context.Context) (*Configuration, error) {
 	var result Configuration
-	if err := client.doJSON(ctx, http.MethodGet, apiConfigurationPath, nil, &result); err != nil {
+	if err := client.Do(ctx, http.MethodGet, apiConfigurationPath, nil, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -120,19 +419,22 @@ func (client *Client) Batch(ctx context.Context, vats []string) (string, error)
 	csvWriter.Flush()
 	_ = multipartWriter.Close()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.endpoint.JoinPath(apiBatchStatusPath).String(), &requestBody)
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.settings.Load().endpoint.JoinPath(apiBatchStatusPath).String(), &requestBody)
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Type", multipartWriter.FormDataContentType())
 
-	rsp, err := client.httpClient.Do(req)
+	rsp, err := client.do(req)
 	if err != nil {
-		return "", err
+		return "", client.correlate(ctx, err)
 	}
 	defer rsp.Body.Close()
 
-	rspBody, err := io.ReadAll(rsp.Body)
+	rspBody, err := client.readBody(rsp)
 	if err != nil {
 		return "", err
 	}
@@ -145,7 +447,7 @@ func (client *Client) Batch(ctx context.Context, vats []string) (string, error)
 		return token.Token, nil
 	}
 
-	return "", client.doError(&rspBody)
+	return "", client.correlate(ctx, client.doError(&rspBody))
 }
 
 func (client *Client) BatchStatus(ctx context.Context, token string) (*BatchStatus, error) {
@@ -156,44 +458,47 @@ func (client *Client) BatchStatus(ctx context.Context, token string) (*BatchStat
 	}
 
 	path := fmt.Sprintf("%s/%s", apiBatchStatusPath, token)
-	if err := client.doJSON(ctx, http.MethodGet, path, nil, &result); err != nil {
+	if err := client.Do(ctx, http.MethodGet, path, nil, &result); err != nil {
 		return nil, err
 	}
 
 	return &result, nil
 }
 
-func (client *Client) BatchReport(ctx context.Context, token string) ([]CheckResult, error) {
+func (client *Client) BatchReport(ctx context.Context, token string) (ResultSet, error) {
 
 	if token == "" {
 		return nil, fmt.Errorf("empty token provided")
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.endpoint.JoinPath(fmt.Sprintf("%s/%s", apiBatchReportPath, token)).String(), nil)
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, client.settings.Load().endpoint.JoinPath(fmt.Sprintf("%s/%s", apiBatchReportPath, token)).String(), nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	rsp, err := client.httpClient.Do(req)
+	rsp, err := client.do(req)
 	if err != nil {
-		return nil, err
+		return nil, client.correlate(ctx, err)
 	}
 	defer rsp.Body.Close()
 
 	if rsp.StatusCode != http.StatusOK {
-		body, err := io.ReadAll(rsp.Body)
+		body, err := client.readBody(rsp)
 		if err != nil {
 			return nil, err
 		}
-		return nil, client.doError(&body)
+		return nil, client.correlate(ctx, client.doError(&body))
 	}
 
 	contentType := rsp.Header.Get("Content-Type")
 	if !strings.Contains(contentType, "spreadsheetml.sheet") {
 		return nil, fmt.Errorf("unexpected response type:  %s", contentType)
 	}
-	body, err := io.ReadAll(rsp.Body)
+	body, err := client.readBody(rsp)
 	if err != nil {
 		return nil, err
 	}
@@ -220,12 +525,12 @@ func (client *Client) BatchReport(ctx context.Context, token string) ([]CheckRes
 		return nil, fmt.Errorf("invalid response structure")
 	}
 
-	var results []CheckResult
+	var results ResultSet
 	for _, row := range data[1:] {
 
 		valid := strings.ToUpper(row[headerMap["valid"]]) == "YES"
-		rec := CheckResult{
-			CountryCode: row[headerMap["countryCode"]],
+		rec := &CheckResult{
+			CountryCode: CountryCode(row[headerMap["countryCode"]]),
 			VatNumber:   row[headerMap["vatNumber"]],
 			Valid:       valid,
 			//Error:       row[headerMap["error"]],
@@ -244,38 +549,218 @@ func (client *Client) BatchReport(ctx context.Context, token string) ([]CheckRes
 
 }
 
-func (client *Client) Valid(ctx context.Context, vat string) (bool, error) {
-	result, err := client.Check(ctx, vat)
+func (client *Client) Valid(ctx context.Context, vat string, opts ...CheckOption) (bool, error) {
+	result, err := client.Check(ctx, vat, opts...)
 	if err != nil {
 		return false, err
 	}
 	return result.Valid, nil
 }
 
+// cleanInput rejects the inputs SanitizeVat rejects, but empty ones, and
+// repairs the others with CleanVat, or rejects them too with
+// ClientConfig.StrictInput.
+func (client *Client) cleanInput(input string) (string, error) {
+	if err := checkInput(input); err != nil {
+		return "", err
+	}
+	cleaned, changed := cleanVat(input)
+	if changed && client.strictInput {
+		return "", &ErrInvalidInput{Input: input, Reason: ReasonBadCharacters}
+	}
+	return strings.TrimSpace(cleaned), nil
+}
+
+// countryAllowed tells whether Check accepts numbers of countryCode.
+func (s *settings) countryAllowed(countryCode string) bool {
+	if s.allowedCountries != nil && !s.allowedCountries[countryCode] {
+		return false
+	}
+	return !s.blockedCountries[countryCode]
+}
+
+// countrySet returns the upper-cased codes as a set, nil when empty.
+func countrySet(codes []string) map[string]bool {
+	if len(codes) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[strings.ToUpper(code)] = true
+	}
+	return set
+}
+
 func (client *Client) isValidVat(vat string) error {
-	if len(vat) < 3 {
-		return fmt.Errorf("invalid VAT provided %s", vat)
+	if len(vat) < 3 || !isLetters(strings.ToUpper(vat[0:2])) {
+		return invalidInput(vat)
 	}
 	return nil
 }
 
-func (client *Client) Check(ctx context.Context, vat string) (*CheckResult, error) {
+func (client *Client) Check(ctx context.Context, vat string, opts ...CheckOption) (*CheckResult, error) {
+	return client.audited(ctx, vat, opts, func(ctx context.Context) (*CheckResult, error) {
+		return client.checkVat(ctx, vat, opts, nil)
+	})
+}
+
+// checkVat checks the VAT number vat as typed, the response is also decoded
+// into into when not nil.
+func (client *Client) checkVat(ctx context.Context, vat string, opts []CheckOption, into any) (*CheckResult, error) {
 
+	cleaned, err := client.cleanInput(vat)
+	if err != nil {
+		return nil, err
+	}
+	if cleaned == "" {
+		return nil, &ErrInvalidInput{Input: vat, Reason: ReasonEmpty}
+	}
+	vat = cleaned
 	if err := client.isValidVat(vat); err != nil {
 		return nil, err
 	}
+	return client.check(ctx, strings.ToUpper(vat[0:2]), vat[2:], opts, into)
+}
+
+// CheckNumber checks a VAT number stored apart from its country code, the
+// number is sent as is, without guessing where the prefix ends.
+func (client *Client) CheckNumber(ctx context.Context, countryCode, vatNumber string, opts ...CheckOption) (*CheckResult, error) {
+	return client.audited(ctx, countryCode+vatNumber, opts, func(ctx context.Context) (*CheckResult, error) {
+		return client.checkCountryNumber(ctx, countryCode, vatNumber, opts)
+	})
+}
+
+func (client *Client) checkCountryNumber(ctx context.Context, countryCode, vatNumber string, opts []CheckOption) (*CheckResult, error) {
+
+	countryCode, err := client.cleanInput(countryCode)
+	if err != nil {
+		return nil, err
+	}
+	if vatNumber, err = client.cleanInput(vatNumber); err != nil {
+		return nil, err
+	}
+	countryCode = strings.ToUpper(countryCode)
+	if len(countryCode) != 2 || !isLetters(countryCode) {
+		return nil, &ErrInvalidInput{Input: countryCode + vatNumber, Reason: ReasonBadCountryCode}
+	}
+	if vatNumber == "" {
+		return nil, &ErrInvalidInput{Input: countryCode, CountryCode: countryCode, Reason: ReasonTooShort}
+	}
+	return client.check(ctx, countryCode, vatNumber, opts, nil)
+}
+
+// check runs Check, the response is also decoded into into when not nil,
+// without using the cache then.
+func (client *Client) check(ctx context.Context, countryCode, vatNumber string, opts []CheckOption, into any) (*CheckResult, error) {
+
+	if client.preflightFormat {
+		if err := checkNumber(countryCode+vatNumber, countryCode, strings.ToUpper(vatNumber)); err != nil {
+			return nil, err
+		}
+	}
+	settings := client.settings.Load()
+	if !settings.countryAllowed(countryCode) {
+		return nil, fmt.Errorf("%w %s", ErrCountryNotAllowed, countryCode)
+	}
+
+	options := newCheckOptions(opts)
+	requester := settings.requester
+	if options.requester != "" {
+		if err := client.isValidVat(options.requester); err != nil {
+			return nil, err
+		}
+		requester = options.requester
+	}
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	key := vatKey(countryCode + vatNumber)
+	// results carry the consultation number issued to their requester,
+	// they are cached per requester
+	cacheKey := resultCacheKey(key, requester)
+	useCache := client.cache != nil && !options.skipCache && into == nil
+	if useCache && !options.forceRefresh {
+		result, ok := client.cache.Get(cacheKey)
+		client.observeCache(ok)
+		if ok {
+			client.logCacheHit(ctx, key)
+			auditCacheHit(ctx)
+			return result, nil
+		}
+	}
+
+	if err := client.preflightCheck(ctx, countryCode); err != nil {
+		return nil, err
+	}
 
 	var status CheckResult
 	reqBody := &checkRequest{
-		CountryCode: strings.ToUpper(vat[0:2]),
-		VatNumber:   vat[2:],
+		CountryCode: CountryCode(countryCode),
+		VatNumber:   vatNumber,
+	}
+	if requester != "" {
+		reqBody.RequesterMemberStateCode = CountryCode(requester[0:2])
+		reqBody.RequesterNumber = requester[2:]
+	}
+
+	ctx, span := client.startSpan(ctx, "vies.Check", map[string]any{attributeCountryCode: countryCode})
+	ctx, obs := observe(ctx)
+	start := time.Now()
+	out := client.resultDecoder(&status, &status.Extra, &status.Raw)
+	if into != nil {
+		// into is the type the caller expects, it is the one checked by
+		// strict decoding
+		(*out)[0] = lenient{&status}
+		*out = append(*out, into)
+	}
+	var err error
+	if client.fake != nil {
+		err = client.fake.check(reqBody, &status, into, client.clock.Now())
+	} else {
+		err = client.Do(ctx, http.MethodPost, apiCheckVatPath, reqBody, out)
+	}
+
+	outcome := outcomeInvalid
+	switch {
+	case err != nil:
+		outcome = outcomeError
+	case status.Valid:
+		outcome = outcomeValid
+	}
+	duration := time.Since(start)
+	client.observeRequest(operationCheck, countryCode, outcome, obs, duration, err)
+	client.logRequest(ctx, operationCheck, key, outcome, obs, duration, err)
+	if err != nil {
+		client.endSpan(span, obs, err, nil)
+	} else {
+		client.endSpan(span, obs, nil, map[string]any{attributeValid: status.Valid})
 	}
 
-	if err := client.doJSON(ctx, http.MethodPost, apiCheckVatPath, reqBody, &status); err != nil {
+	if err != nil {
+		var apiErr *ApiError
+		if errors.As(err, &apiErr) && apiErr.CountryCode == "" {
+			apiErr.CountryCode = countryCode
+		}
 		return nil, err
 	}
 
 	status.Vat = fmt.Sprintf("%s%s", status.CountryCode, status.VatNumber)
+	status.ResponseHeaders = client.pickHeaders(obs.header)
+	if client.revalidation != nil {
+		client.revalidation.stamp(&status, client.clock.Now())
+	}
+	if client.proofSigner != nil {
+		if err := SignResult(&status, client.proofSigner, client.clock.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	if useCache {
+		client.cache.Set(cacheKey, &status)
+	}
 
 	return &status, nil
 }
@@ -299,46 +784,166 @@ func (client *Client) doError(body *[]byte) error {
 }
 
 func (client *Client) Status(ctx context.Context) (*Status, error) {
+	if client.fake != nil {
+		return client.fake.status(), nil
+	}
+
 	var status Status
-	if err := client.doJSON(ctx, http.MethodGet, apiCheckStatusPath, nil, &status); err != nil {
+
+	ctx, span := client.startSpan(ctx, "vies.Status", nil)
+	ctx, obs := observe(ctx)
+	start := time.Now()
+	err := client.Do(ctx, http.MethodGet, apiCheckStatusPath, nil, client.resultDecoder(&status, &status.Extra, &status.Raw))
+
+	outcome := outcomeSuccess
+	if err != nil {
+		outcome = outcomeError
+	}
+	duration := time.Since(start)
+	client.observeRequest(operationStatus, "", outcome, obs, duration, err)
+	client.logRequest(ctx, operationStatus, "", outcome, obs, duration, err)
+	client.endSpan(span, obs, err, nil)
+
+	if err != nil {
 		return nil, err
 	}
+	status.ResponseHeaders = client.pickHeaders(obs.header)
 	return &status, nil
 }
 
-func (client *Client) doJSON(ctx context.Context, method, path string, reqBody any, out any) error {
-	var body io.Reader
-	if reqBody != nil {
-		reqBytes, err := json.Marshal(reqBody)
-		if err != nil {
-			return err
-		}
-		body = bytes.NewReader(reqBytes)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, method, client.endpoint.JoinPath(path).String(), body)
+// Do calls an endpoint of the VIES REST API, path being relative to
+// ClientConfig.EndpointUrl, through the configured HTTP client, rate
+// limiter, headers and interceptors. reqBody, when not nil, is sent as
+// JSON and a 200 response is decoded into out, when not nil. Other
+// responses are returned as *ApiError.
+//
+//	var config vies.Configuration
+//	err := client.Do(ctx, http.MethodGet, "configurations", nil, &config)
+func (client *Client) Do(ctx context.Context, method, path string, reqBody any, out any) error {
+	ctx, cancel := client.withRequestTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, client.settings.Load().endpoint.JoinPath(path).String(), nil)
 	if err != nil {
 		return err
 	}
 	if reqBody != nil {
+		body := getBody()
+		defer body.release()
+		if err := json.NewEncoder(&body.buf).Encode(reqBody); err != nil {
+			return err
+		}
+		body.buf.Truncate(body.buf.Len() - 1) // the newline added by Encode
+
+		req.Body = body.open()
+		req.GetBody = body.reader
+		req.ContentLength = int64(body.buf.Len())
 		req.Header.Set("Content-Type", "application/json")
 	}
 	req.Header.Set("Accept", "application/json")
 
-	rsp, err := client.httpClient.Do(req)
+	rsp, err := client.do(req)
 	if err != nil {
-		return err
+		return client.correlate(ctx, err)
 	}
 	defer rsp.Body.Close()
 
-	rspBody, err := io.ReadAll(rsp.Body)
-	if err != nil {
-		return err
+	if rsp.StatusCode == http.StatusOK {
+		if out == nil {
+			return nil
+		}
+		return client.correlate(ctx, client.decode(client.limitBody(rsp), out))
 	}
 
-	if rsp.StatusCode == http.StatusOK {
-		return json.Unmarshal(rspBody, out)
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if _, err := buf.ReadFrom(client.limitBody(rsp)); err != nil {
+		return client.correlate(ctx, err)
+	}
+	rspBody := buf.Bytes()
+	return client.correlate(ctx, client.doError(&rspBody))
+}
+
+func (client *Client) do(req *http.Request) (*http.Response, error) {
+	settings := client.settings.Load()
+	for name, values := range settings.headers {
+		req.Header[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+	}
+	if settings.userAgent != "" {
+		req.Header.Set("User-Agent", settings.userAgent)
+	}
+	if client.tracer != nil {
+		client.tracer.Inject(req.Context(), req.Header)
+	}
+	if id := client.correlationID(req.Context()); id != "" {
+		req.Header.Set(client.correlationHeader, id)
+	}
+	acceptGzip(req)
+
+	return client.send(req)
+}
+
+// withRequestTimeout applies ClientConfig.RequestTimeout to ctx when it
+// has no deadline.
+func (client *Client) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if client.requestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, client.requestTimeout)
+}
+
+const defaultMaxResponseSize = 4 << 20
+
+// limitBody returns the body of a response failing with
+// ErrResponseTooLarge after maxResponseSize bytes.
+func (client *Client) limitBody(rsp *http.Response) io.Reader {
+	return &limitReader{r: rsp.Body, limit: client.maxResponseSize, remaining: client.maxResponseSize}
+}
+
+// readBody reads a response body of at most maxResponseSize bytes.
+func (client *Client) readBody(rsp *http.Response) ([]byte, error) {
+	return io.ReadAll(client.limitBody(rsp))
+}
+
+// pickHeaders returns the headers of ClientConfig.ResponseHeaders found in
+// header, nil when there are none.
+func (client *Client) pickHeaders(header http.Header) http.Header {
+	var picked http.Header
+	for _, name := range client.responseHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			if picked == nil {
+				picked = make(http.Header)
+			}
+			picked[http.CanonicalHeaderKey(name)] = slices.Clone(values)
+		}
+	}
+	return picked
+}
+
+// roundTrip sends one attempt of a request, once the rate limiter allows
+// it, so that retries are throttled too.
+func (client *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	if limiter := client.settings.Load().rateLimiter; limiter != nil {
+		if err := limiter.Wait(req.Context()); err != nil {
+			return nil, &rateLimitError{err: err}
+		}
+	}
+
+	obs := observationFrom(req.Context())
+	if obs != nil {
+		obs.attempts++
 	}
 
-	return client.doError(&rspBody)
+	rsp, err := client.httpClient.Do(req)
+	if rsp != nil {
+		decompress(rsp)
+	}
+	if obs != nil && rsp != nil {
+		obs.statusCode = rsp.StatusCode
+		obs.header = rsp.Header
+	}
+	return rsp, err
 }
@@ -3,10 +3,12 @@ package vies
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"log"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -142,8 +144,8 @@ func TestNewValidator(t *testing.T) {
 		v, err := NewClient(nil)
 		assert.NoError(t, err)
 		assert.NotNil(t, v)
-		assert.Equal(t, http.DefaultClient, v.httpClient)
-		assert.Equal(t, apiEndpointUrl, v.endpoint.String())
+		assert.Equal(t, defaultHttpTimeout, v.httpClient.(*http.Client).Timeout)
+		assert.Equal(t, apiEndpointUrl, v.settings.Load().endpoint.String())
 	})
 
 	t.Run("custom endpoint and client", func(t *testing.T) {
@@ -153,7 +155,14 @@ func TestNewValidator(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, v)
 		assert.Equal(t, client, v.httpClient)
-		assert.Equal(t, endpoint, v.endpoint.String())
+		assert.Equal(t, endpoint, v.settings.Load().endpoint.String())
+	})
+
+	t.Run("invalid requester", func(t *testing.T) {
+		v, err := NewClient(&ClientConfig{Requester: "E"})
+		assert.Nil(t, v)
+		assert.Error(t, err)
+		assert.Equal(t, "invalid VAT provided E", err.Error())
 	})
 
 	t.Run("invalid endpoint", func(t *testing.T) {
@@ -190,11 +199,11 @@ func TestValidatorDoJSON(t *testing.T) {
 			}
 		})
 
-		v, err := NewClient(&ClientConfig{client, "https://example.com/api/", nil})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		var out responsePayload
-		err = v.doJSON(context.Background(), http.MethodPost, "do", requestPayload{A: "value", B: 42}, &out)
+		err = v.Do(context.Background(), http.MethodPost, "do", requestPayload{A: "value", B: 42}, &out)
 		assert.NoError(t, err)
 		assert.Equal(t, responsePayload{OK: true}, out)
 	})
@@ -218,11 +227,11 @@ func TestValidatorDoJSON(t *testing.T) {
 			}
 		})
 
-		v, err := NewClient(&ClientConfig{client, "https://example.com/api/", nil})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		var out responsePayload
-		err = v.doJSON(context.Background(), http.MethodGet, "ping", nil, &out)
+		err = v.Do(context.Background(), http.MethodGet, "ping", nil, &out)
 		assert.NoError(t, err)
 		assert.Equal(t, responsePayload{OK: true}, out)
 	})
@@ -238,11 +247,11 @@ func TestValidatorDoJSON(t *testing.T) {
 			}
 		})
 
-		v, err := NewClient(&ClientConfig{client, "https://example.com/api/", nil})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		var out responsePayload
-		err = v.doJSON(context.Background(), http.MethodGet, "ping", nil, &out)
+		err = v.Do(context.Background(), http.MethodGet, "ping", nil, &out)
 		assert.Error(t, err)
 		assert.Equal(t, "err: msg", err.Error())
 
@@ -259,11 +268,11 @@ func TestValidatorDoJSON(t *testing.T) {
 			}
 		})
 
-		v, err := NewClient(&ClientConfig{client, "https://example.com/api/", nil})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		var out responsePayload
-		err = v.doJSON(context.Background(), http.MethodGet, "ping", nil, &out)
+		err = v.Do(context.Background(), http.MethodGet, "ping", nil, &out)
 		assert.Error(t, err)
 		assert.Equal(t, "invalid character '!' looking for beginning of value", err.Error())
 	})
@@ -274,14 +283,29 @@ func TestValidatorDoJSON(t *testing.T) {
 			return nil
 		})
 
-		v, err := NewClient(&ClientConfig{client, "https://example.com/api/", nil})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		var out responsePayload
-		err = v.doJSON(context.Background(), http.MethodPost, "do", func() {}, &out)
+		err = v.Do(context.Background(), http.MethodPost, "do", func() {}, &out)
 		assert.Error(t, err)
 		assert.Equal(t, "json: unsupported type: func()", err.Error())
 	})
+
+	t.Run("nil output ignores body", func(t *testing.T) {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			assert.Equal(t, "https://example.com/api/vat-validation/token", req.URL.String())
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBufferString(`not json`)),
+				Header:     make(http.Header),
+			}
+		})
+
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
+		assert.NoError(t, err)
+		assert.NoError(t, v.Do(context.Background(), http.MethodDelete, "vat-validation/token", nil, nil))
+	})
 }
 
 func TestValidatorCheck(t *testing.T) {
@@ -304,7 +328,7 @@ func TestValidatorCheck(t *testing.T) {
 			}
 		})
 
-		v, err := NewClient(&ClientConfig{client, "https://example.com/api/", nil})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		result, err := v.Check(context.Background(), "ee123")
@@ -318,17 +342,41 @@ func TestValidatorCheck(t *testing.T) {
 		}, result)
 	})
 
+	t.Run("with requester", func(t *testing.T) {
+		client := NewTestClient(func(req *http.Request) *http.Response {
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"countryCode":"EE","vatNumber":"123","requesterMemberStateCode":"DE","requesterNumber":"456"}`, string(body))
+
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(bytes.NewBufferString(
+					`{"countryCode":"EE","vatNumber":"123","valid":true,"requestIdentifier":"WAPIAAAAW1"}`,
+				)),
+				Header: make(http.Header),
+			}
+		})
+
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/", Requester: "de456"})
+		assert.NoError(t, err)
+
+		result, err := v.Check(context.Background(), "EE123")
+		assert.NoError(t, err)
+		assert.Equal(t, "WAPIAAAAW1", result.RequestIdentifier)
+	})
+
 	t.Run("invalid vat length", func(t *testing.T) {
-		v, err := NewClient(&ClientConfig{NewTestClient(func(req *http.Request) *http.Response {
+		v, err := NewClient(&ClientConfig{HttpClient: NewTestClient(func(req *http.Request) *http.Response {
 			t.Fatalf("unexpected request: %v", req)
 			return nil
-		}), "https://example.com/api/", nil})
+		}), EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		result, err := v.Check(context.Background(), "E")
 		assert.Nil(t, result)
 		assert.Error(t, err)
 		assert.Equal(t, "invalid VAT provided E", err.Error())
+		assert.ErrorIs(t, err, ErrInvalidVat)
 	})
 
 	t.Run("server error", func(t *testing.T) {
@@ -342,7 +390,7 @@ func TestValidatorCheck(t *testing.T) {
 			}
 		})
 
-		v, err := NewClient(&ClientConfig{client, "https://example.com/api/", nil})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		result, err := v.Check(context.Background(), "EE123")
@@ -364,7 +412,7 @@ func TestValidatorValid(t *testing.T) {
 			}
 		})
 
-		v, err := NewClient(&ClientConfig{client, "https://example.com/api/", nil})
+		v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		ok, err := v.Valid(context.Background(), "EE123")
@@ -373,10 +421,10 @@ func TestValidatorValid(t *testing.T) {
 	})
 
 	t.Run("returns error from check", func(t *testing.T) {
-		v, err := NewClient(&ClientConfig{NewTestClient(func(req *http.Request) *http.Response {
+		v, err := NewClient(&ClientConfig{HttpClient: NewTestClient(func(req *http.Request) *http.Response {
 			t.Fatalf("unexpected request: %v", req)
 			return nil
-		}), "https://example.com/api/", nil})
+		}), EndpointUrl: "https://example.com/api/"})
 		assert.NoError(t, err)
 
 		ok, err := v.Valid(context.Background(), "E")
@@ -386,6 +434,41 @@ func TestValidatorValid(t *testing.T) {
 	})
 }
 
+func TestValidatorCheckNumber(t *testing.T) {
+
+	var body checkRequest
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		assert.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EL","vatNumber":"094014201","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	cache := NewMemoryCache(time.Minute)
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/api/", Cache: cache})
+	assert.NoError(t, err)
+
+	result, err := v.CheckNumber(context.Background(), " el", "094014201 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "EL094014201", result.Vat)
+	assert.Equal(t, checkRequest{CountryCode: "EL", VatNumber: "094014201"}, body)
+
+	_, ok := cache.Get("EL094014201")
+	assert.True(t, ok)
+
+	tests := []struct{ country, number, err string }{
+		{"E", "123", "invalid VAT provided E123"},
+		{"EE", " ", "invalid VAT provided EE"},
+		{"EEX", "123", "invalid VAT provided EEX123"},
+	}
+	for _, tt := range tests {
+		_, err := v.CheckNumber(context.Background(), tt.country, tt.number)
+		assert.EqualError(t, err, tt.err)
+	}
+}
+
 func TestViesCheck(t *testing.T) {
 
 	cases := []struct {
@@ -444,3 +527,27 @@ func TestViesCheck(t *testing.T) {
 	}
 
 }
+
+func TestMaxResponseSize(t *testing.T) {
+
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"countryCode":"EE","vatNumber":"123","valid":true,"name":"` + strings.Repeat("x", 100) + `"}`)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/", MaxResponseSize: 64})
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+	assert.EqualError(t, err, "response too large: more than 64 bytes")
+
+	v, err = NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(defaultMaxResponseSize), v.maxResponseSize)
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,175 @@
+// Package viesconformance verifies that an implementation of
+// vies.ClientInterface, such as a SOAP client, a proxy or a mock, behaves
+// like the REST client:
+//
+//	func TestConformance(t *testing.T) {
+//		viesconformance.Run(t, NewSoapClient(testServiceUrl))
+//	}
+//
+// The implementation must answer the numbers of the VIES test service, as
+// VIES does at its test endpoint and viestest.Server does, and must not
+// retry failures.
+package viesconformance
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+)
+
+const (
+	validNumber   = "100"
+	invalidNumber = "200"
+)
+
+// faults are the numbers of the VIES test service failing with an error
+// code.
+var faults = []struct {
+	number, code string
+}{
+	{"201", "INVALID_INPUT"},
+	{"202", "INVALID_REQUESTER_INFO"},
+	{"300", "SERVICE_UNAVAILABLE"},
+	{"301", "MS_UNAVAILABLE"},
+	{"302", "TIMEOUT"},
+	{"400", "VAT_BLOCKED"},
+	{"401", "IP_BLOCKED"},
+	{"500", "GLOBAL_MAX_CONCURRENT_REQ"},
+	{"501", "GLOBAL_MAX_CONCURRENT_REQ_TIME"},
+	{"600", "MS_MAX_CONCURRENT_REQ"},
+	{"601", "MS_MAX_CONCURRENT_REQ_TIME"},
+}
+
+var countryCode = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Run runs the conformance suite against v, each part of it as a subtest
+// of t.
+func Run(t *testing.T, v vies.ClientInterface) {
+	t.Run("Valid", func(t *testing.T) { testValid(t, v) })
+	t.Run("Invalid", func(t *testing.T) { testInvalid(t, v) })
+	t.Run("CheckNumber", func(t *testing.T) { testCheckNumber(t, v) })
+	t.Run("Faults", func(t *testing.T) { testFaults(t, v) })
+	t.Run("InvalidInput", func(t *testing.T) { testInvalidInput(t, v) })
+	t.Run("Canceled", func(t *testing.T) { testCanceled(t, v) })
+	t.Run("Status", func(t *testing.T) { testStatus(t, v) })
+}
+
+func testValid(t *testing.T, v vies.ClientInterface) {
+
+	ctx := context.Background()
+	result, err := v.Check(ctx, "DE"+validNumber)
+	if err != nil {
+		t.Fatalf("Check(DE%s): %v", validNumber, err)
+	}
+	if !result.Valid {
+		t.Errorf("Check(DE%s): valid = false, want true", validNumber)
+	}
+	if result.CountryCode != "DE" || result.VatNumber != validNumber || result.Vat != "DE"+validNumber {
+		t.Errorf("Check(DE%s): got %s, %s, %s", validNumber, result.CountryCode, result.VatNumber, result.Vat)
+	}
+
+	// input is cleaned as by the REST client
+	result, err = v.Check(ctx, " de "+validNumber+" ")
+	if err != nil {
+		t.Fatalf("Check(de %s): %v", validNumber, err)
+	}
+	if !result.Valid || result.CountryCode != "DE" {
+		t.Errorf("Check(de %s): got %s, valid %t", validNumber, result.CountryCode, result.Valid)
+	}
+
+	valid, err := v.Valid(ctx, "FR"+validNumber)
+	if err != nil || !valid {
+		t.Errorf("Valid(FR%s) = %t, %v, want true, nil", validNumber, valid, err)
+	}
+}
+
+func testInvalid(t *testing.T, v vies.ClientInterface) {
+
+	ctx := context.Background()
+	result, err := v.Check(ctx, "DE"+invalidNumber)
+	if err != nil {
+		t.Fatalf("Check(DE%s): %v", invalidNumber, err)
+	}
+	if result.Valid {
+		t.Errorf("Check(DE%s): valid = true, want false", invalidNumber)
+	}
+	if result.CountryCode != "DE" || result.VatNumber != invalidNumber {
+		t.Errorf("Check(DE%s): got %s, %s", invalidNumber, result.CountryCode, result.VatNumber)
+	}
+
+	valid, err := v.Valid(ctx, "FR"+invalidNumber)
+	if err != nil || valid {
+		t.Errorf("Valid(FR%s) = %t, %v, want false, nil", invalidNumber, valid, err)
+	}
+}
+
+func testCheckNumber(t *testing.T, v vies.ClientInterface) {
+
+	result, err := v.CheckNumber(context.Background(), "IT", validNumber)
+	if err != nil {
+		t.Fatalf("CheckNumber(IT, %s): %v", validNumber, err)
+	}
+	if !result.Valid || result.CountryCode != "IT" || result.VatNumber != validNumber {
+		t.Errorf("CheckNumber(IT, %s): got %s, %s, valid %t", validNumber, result.CountryCode, result.VatNumber, result.Valid)
+	}
+}
+
+func testFaults(t *testing.T, v vies.ClientInterface) {
+
+	for _, fault := range faults {
+		t.Run(fault.code, func(t *testing.T) {
+			_, err := v.Check(context.Background(), "DE"+fault.number)
+			var apiErr *vies.ApiError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Check(DE%s): got %v, want *vies.ApiError %s", fault.number, err, fault.code)
+			}
+			if apiErr.Err != fault.code {
+				t.Errorf("Check(DE%s): got %s, want %s", fault.number, apiErr.Err, fault.code)
+			}
+		})
+	}
+}
+
+func testInvalidInput(t *testing.T, v vies.ClientInterface) {
+
+	for _, input := range []string{"", "D", "12345678"} {
+		_, err := v.Check(context.Background(), input)
+		var inputErr *vies.ErrInvalidInput
+		if !errors.As(err, &inputErr) {
+			t.Errorf("Check(%q): got %v, want *vies.ErrInvalidInput", input, err)
+		}
+	}
+}
+
+func testCanceled(t *testing.T, v vies.ClientInterface) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := v.Check(ctx, "DE"+validNumber); !errors.Is(err, context.Canceled) {
+		t.Errorf("Check with a canceled context: got %v, want context.Canceled", err)
+	}
+}
+
+func testStatus(t *testing.T, v vies.ClientInterface) {
+
+	status, err := v.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if len(status.Countries) == 0 {
+		t.Fatal("Status: no member state")
+	}
+	for _, country := range status.Countries {
+		if !countryCode.MatchString(string(country.CountryCode)) {
+			t.Errorf("Status: bad country code %q", country.CountryCode)
+		}
+		switch country.Availability {
+		case vies.AvailabilityAvailable, vies.AvailabilityUnavailable, vies.AvailabilityMonitoringDisabled:
+		default:
+			t.Errorf("Status: %s has unknown availability %q", country.CountryCode, country.Availability)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package viesconformance
+
+import (
+	"testing"
+
+	"github.com/alytsin/go-vies/viestest"
+)
+
+func TestRestClient(t *testing.T) {
+
+	s := viestest.NewServer()
+	defer s.Close()
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	Run(t, client)
+}
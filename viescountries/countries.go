@@ -0,0 +1,115 @@
+// Package viescountries lists the countries taking part in VIES with their
+// names, VIES and ISO 3166 codes and the date they joined the EU VAT area.
+package viescountries
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Country is a VIES member state. Code is the VAT number prefix used by
+// VIES, which differs from the ISO 3166 alpha-2 code for Greece (EL) and
+// Northern Ireland (XI).
+type Country struct {
+	Code    string `json:"code"`
+	IsoCode string `json:"isoCode"`
+	// Numeric is the ISO 3166 numeric code, used in IOSS and OSS numbers.
+	Numeric   string    `json:"numeric,omitempty"`
+	Name      string    `json:"name"`
+	Accession time.Time `json:"accession"`
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+var countries = []Country{
+	{Code: "AT", IsoCode: "AT", Numeric: "040", Name: "Austria", Accession: date(1995, time.January, 1)},
+	{Code: "BE", IsoCode: "BE", Numeric: "056", Name: "Belgium", Accession: date(1958, time.January, 1)},
+	{Code: "BG", IsoCode: "BG", Numeric: "100", Name: "Bulgaria", Accession: date(2007, time.January, 1)},
+	{Code: "CY", IsoCode: "CY", Numeric: "196", Name: "Cyprus", Accession: date(2004, time.May, 1)},
+	{Code: "CZ", IsoCode: "CZ", Numeric: "203", Name: "Czechia", Accession: date(2004, time.May, 1)},
+	{Code: "DE", IsoCode: "DE", Numeric: "276", Name: "Germany", Accession: date(1958, time.January, 1)},
+	{Code: "DK", IsoCode: "DK", Numeric: "208", Name: "Denmark", Accession: date(1973, time.January, 1)},
+	{Code: "EE", IsoCode: "EE", Numeric: "233", Name: "Estonia", Accession: date(2004, time.May, 1)},
+	{Code: "EL", IsoCode: "GR", Numeric: "300", Name: "Greece", Accession: date(1981, time.January, 1)},
+	{Code: "ES", IsoCode: "ES", Numeric: "724", Name: "Spain", Accession: date(1986, time.January, 1)},
+	{Code: "FI", IsoCode: "FI", Numeric: "246", Name: "Finland", Accession: date(1995, time.January, 1)},
+	{Code: "FR", IsoCode: "FR", Numeric: "250", Name: "France", Accession: date(1958, time.January, 1)},
+	{Code: "HR", IsoCode: "HR", Numeric: "191", Name: "Croatia", Accession: date(2013, time.July, 1)},
+	{Code: "HU", IsoCode: "HU", Numeric: "348", Name: "Hungary", Accession: date(2004, time.May, 1)},
+	{Code: "IE", IsoCode: "IE", Numeric: "372", Name: "Ireland", Accession: date(1973, time.January, 1)},
+	{Code: "IT", IsoCode: "IT", Numeric: "380", Name: "Italy", Accession: date(1958, time.January, 1)},
+	{Code: "LT", IsoCode: "LT", Numeric: "440", Name: "Lithuania", Accession: date(2004, time.May, 1)},
+	{Code: "LU", IsoCode: "LU", Numeric: "442", Name: "Luxembourg", Accession: date(1958, time.January, 1)},
+	{Code: "LV", IsoCode: "LV", Numeric: "428", Name: "Latvia", Accession: date(2004, time.May, 1)},
+	{Code: "MT", IsoCode: "MT", Numeric: "470", Name: "Malta", Accession: date(2004, time.May, 1)},
+	{Code: "NL", IsoCode: "NL", Numeric: "528", Name: "Netherlands", Accession: date(1958, time.January, 1)},
+	{Code: "PL", IsoCode: "PL", Numeric: "616", Name: "Poland", Accession: date(2004, time.May, 1)},
+	{Code: "PT", IsoCode: "PT", Numeric: "620", Name: "Portugal", Accession: date(1986, time.January, 1)},
+	{Code: "RO", IsoCode: "RO", Numeric: "642", Name: "Romania", Accession: date(2007, time.January, 1)},
+	{Code: "SE", IsoCode: "SE", Numeric: "752", Name: "Sweden", Accession: date(1995, time.January, 1)},
+	{Code: "SI", IsoCode: "SI", Numeric: "705", Name: "Slovenia", Accession: date(2004, time.May, 1)},
+	{Code: "SK", IsoCode: "SK", Numeric: "703", Name: "Slovakia", Accession: date(2004, time.May, 1)},
+	// Northern Ireland stays in VIES for goods under the Windsor Framework.
+	{Code: "XI", IsoCode: "GB", Name: "Northern Ireland", Accession: date(2021, time.January, 1)},
+}
+
+// All returns the VIES member states ordered by VIES code.
+func All() []Country {
+	return slices.Clone(countries)
+}
+
+// Codes returns the VIES codes of all member states.
+func Codes() []string {
+	codes := make([]string, len(countries))
+	for i, c := range countries {
+		codes[i] = c.Code
+	}
+	return codes
+}
+
+// ByCode looks a member state up by its VIES code.
+func ByCode(code string) (Country, bool) {
+	return find(func(c Country) bool { return c.Code == strings.ToUpper(code) })
+}
+
+// ByIsoCode looks a member state up by its ISO 3166 alpha-2 code. GB
+// resolves to Northern Ireland, the only part of it in VIES.
+func ByIsoCode(iso string) (Country, bool) {
+	return find(func(c Country) bool { return c.IsoCode == strings.ToUpper(iso) })
+}
+
+// ByNumeric looks a member state up by its ISO 3166 numeric code.
+func ByNumeric(numeric string) (Country, bool) {
+	return find(func(c Country) bool { return c.Numeric != "" && c.Numeric == numeric })
+}
+
+// IsMember reports whether code is the VIES code of a member state.
+func IsMember(code string) bool {
+	_, ok := ByCode(code)
+	return ok
+}
+
+// ViesCode converts an ISO 3166 alpha-2 code to the VIES code, returning
+// an empty string for countries outside VIES.
+func ViesCode(iso string) string {
+	c, _ := ByIsoCode(iso)
+	return c.Code
+}
+
+// IsoCode converts a VIES code to the ISO 3166 alpha-2 code, returning an
+// empty string for codes outside VIES.
+func IsoCode(code string) string {
+	c, _ := ByCode(code)
+	return c.IsoCode
+}
+
+func find(match func(Country) bool) (Country, bool) {
+	i := slices.IndexFunc(countries, match)
+	if i < 0 {
+		return Country{}, false
+	}
+	return countries[i], true
+}
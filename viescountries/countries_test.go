@@ -0,0 +1,57 @@
+package viescountries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+
+	cases := []struct {
+		name    string
+		country Country
+		ok      bool
+	}{
+		{name: "de", country: Country{Code: "DE", IsoCode: "DE", Numeric: "276", Name: "Germany"}, ok: true},
+		{name: "EL", country: Country{Code: "EL", IsoCode: "GR", Numeric: "300", Name: "Greece"}, ok: true},
+		{name: "XI", country: Country{Code: "XI", IsoCode: "GB", Name: "Northern Ireland"}, ok: true},
+		{name: "GR"},
+		{name: "GB"},
+		{name: "CH"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := ByCode(tt.name)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.ok, IsMember(tt.name))
+			c.Accession = tt.country.Accession
+			assert.Equal(t, tt.country, c)
+		})
+	}
+}
+
+func TestCodes(t *testing.T) {
+	assert.Equal(t, "EL", ViesCode("GR"))
+	assert.Equal(t, "XI", ViesCode("gb"))
+	assert.Equal(t, "", ViesCode("CH"))
+	assert.Equal(t, "GR", IsoCode("EL"))
+	assert.Equal(t, "", IsoCode("GR"))
+
+	c, ok := ByNumeric("372")
+	assert.True(t, ok)
+	assert.Equal(t, "IE", c.Code)
+	_, ok = ByNumeric("")
+	assert.False(t, ok)
+
+	assert.Len(t, Codes(), 28)
+	assert.Equal(t, 2013, All()[12].Accession.Year())
+}
+
+func TestAllIsCopy(t *testing.T) {
+	all := All()
+	all[0].Name = "changed"
+	c, _ := ByCode("AT")
+	assert.Equal(t, "Austria", c.Name)
+}
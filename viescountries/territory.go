@@ -0,0 +1,93 @@
+package viescountries
+
+import (
+	"slices"
+	"strings"
+)
+
+// Territory is a country or a part of one with its VAT treatment. Code is
+// an ISO 3166-1 alpha-2 or ISO 3166-2 subdivision code, empty for the
+// territories which have none.
+type Territory struct {
+	Code string `json:"code,omitempty"`
+	Name string `json:"name"`
+	// CountryCode is the VIES code of the member state whose VAT applies,
+	// empty outside the EU VAT area.
+	CountryCode string `json:"countryCode,omitempty"`
+	InVatArea   bool   `json:"inVatArea"`
+}
+
+// territories are the special cases of articles 6 and 7 of the VAT
+// Directive, plus the neighbours treated as part of a member state.
+var territories = []Territory{
+	// treated as part of a member state
+	{Code: "MC", Name: "Monaco", CountryCode: "FR", InVatArea: true},
+	{Code: "GB-NIR", Name: "Northern Ireland", CountryCode: "XI", InVatArea: true},
+	{Name: "Akrotiri and Dhekelia", CountryCode: "CY", InVatArea: true},
+	{Code: "PT-20", Name: "Azores", CountryCode: "PT", InVatArea: true},
+	{Code: "PT-30", Name: "Madeira", CountryCode: "PT", InVatArea: true},
+	{Name: "Jungholz", CountryCode: "AT", InVatArea: true},
+	{Name: "Mittelberg", CountryCode: "AT", InVatArea: true},
+
+	// excluded from the EU VAT area
+	{Code: "ES-CN", Name: "Canary Islands"},
+	{Code: "IC", Name: "Canary Islands"},
+	{Code: "ES-CE", Name: "Ceuta"},
+	{Code: "ES-ML", Name: "Melilla"},
+	{Code: "EA", Name: "Ceuta and Melilla"},
+	{Code: "AX", Name: "Åland Islands"},
+	{Code: "FI-01", Name: "Åland Islands"},
+	{Code: "GR-69", Name: "Mount Athos"},
+	{Name: "Heligoland"},
+	{Name: "Büsingen am Hochrhein"},
+	{Name: "Livigno"},
+	{Name: "Campione d'Italia"},
+	{Code: "GP", Name: "Guadeloupe"},
+	{Code: "GF", Name: "French Guiana"},
+	{Code: "MQ", Name: "Martinique"},
+	{Code: "RE", Name: "Réunion"},
+	{Code: "YT", Name: "Mayotte"},
+	{Code: "MF", Name: "Saint Martin"},
+	{Code: "FO", Name: "Faroe Islands"},
+	{Code: "GL", Name: "Greenland"},
+	{Code: "GI", Name: "Gibraltar"},
+	{Code: "SM", Name: "San Marino"},
+	{Code: "VA", Name: "Vatican City"},
+	{Code: "AD", Name: "Andorra"},
+	{Code: "GB", Name: "United Kingdom"},
+}
+
+// Territories returns the special territories known to LookupTerritory.
+func Territories() []Territory {
+	return slices.Clone(territories)
+}
+
+// LookupTerritory returns the VAT treatment of a territory given by its
+// ISO 3166 code, or by name for the territories without one. Member states
+// are found by ISO and by VIES code.
+func LookupTerritory(code string) (Territory, bool) {
+
+	code = strings.TrimSpace(code)
+	i := slices.IndexFunc(territories, func(t Territory) bool {
+		return strings.EqualFold(t.Code, code) || strings.EqualFold(t.Name, code)
+	})
+	if i >= 0 {
+		return territories[i], true
+	}
+
+	c, ok := ByIsoCode(code)
+	if !ok {
+		c, ok = ByCode(code)
+	}
+	if !ok {
+		return Territory{}, false
+	}
+	return Territory{Code: c.IsoCode, Name: c.Name, CountryCode: c.Code, InVatArea: true}, true
+}
+
+// InVatArea reports whether a territory is inside the EU VAT area, unknown
+// territories are outside.
+func InVatArea(code string) bool {
+	t, _ := LookupTerritory(code)
+	return t.InVatArea
+}
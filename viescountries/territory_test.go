@@ -0,0 +1,35 @@
+package viescountries
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupTerritory(t *testing.T) {
+
+	cases := []struct {
+		code      string
+		territory Territory
+		ok        bool
+	}{
+		{code: "MC", territory: Territory{Code: "MC", Name: "Monaco", CountryCode: "FR", InVatArea: true}, ok: true},
+		{code: "gb-nir", territory: Territory{Code: "GB-NIR", Name: "Northern Ireland", CountryCode: "XI", InVatArea: true}, ok: true},
+		{code: "GB", territory: Territory{Code: "GB", Name: "United Kingdom"}, ok: true},
+		{code: "ES-CN", territory: Territory{Code: "ES-CN", Name: "Canary Islands"}, ok: true},
+		{code: "AX", territory: Territory{Code: "AX", Name: "Åland Islands"}, ok: true},
+		{code: "Livigno", territory: Territory{Name: "Livigno"}, ok: true},
+		{code: "GR", territory: Territory{Code: "GR", Name: "Greece", CountryCode: "EL", InVatArea: true}, ok: true},
+		{code: "EL", territory: Territory{Code: "GR", Name: "Greece", CountryCode: "EL", InVatArea: true}, ok: true},
+		{code: "US"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.code, func(t *testing.T) {
+			territory, ok := LookupTerritory(tt.code)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.territory, territory)
+			assert.Equal(t, tt.territory.InVatArea, InVatArea(tt.code))
+		})
+	}
+}
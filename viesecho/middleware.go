@@ -0,0 +1,123 @@
+// Package viesecho provides an Echo middleware requiring a valid VAT number
+// on the requests it guards.
+package viesecho
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+const (
+	// ResultKey is the key of the *vies.CheckResult in the echo.Context.
+	ResultKey = "vies.result"
+
+	defaultHeader   = "X-Vat-Number"
+	defaultCacheTTL = 24 * time.Hour
+)
+
+type Config struct {
+	// Registry checks the numbers, usually a *vies.Client or *vies.Router.
+	Registry vies.RegistryInterface
+	// Extract returns the VAT number of a request, read from the
+	// X-Vat-Number header by default.
+	Extract func(c echo.Context) string
+	// Skipper lets requests through without a check when it returns true.
+	Skipper middleware.Skipper
+	// Cache keeps the results between requests, in memory for a day by
+	// default.
+	Cache vies.CacheInterface
+}
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// Middleware checks the VAT number of each request and fails with an
+// *echo.HTTPError, 422 when the number is missing, malformed or invalid and
+// 503 when it could not be checked. The result of a valid number is
+// available to the handlers through Result.
+func Middleware(config *Config) echo.MiddlewareFunc {
+
+	var registry vies.RegistryInterface
+	var cache vies.CacheInterface
+
+	extract := func(c echo.Context) string {
+		return c.Request().Header.Get(defaultHeader)
+	}
+	skipper := middleware.DefaultSkipper
+
+	if config != nil {
+		registry = config.Registry
+		if config.Extract != nil {
+			extract = config.Extract
+		}
+		if config.Skipper != nil {
+			skipper = config.Skipper
+		}
+		cache = config.Cache
+	}
+	if cache == nil {
+		cache = vies.NewMemoryCache(defaultCacheTTL)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+
+			if skipper(c) {
+				return next(c)
+			}
+
+			vat := extract(c)
+			if vat == "" {
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, errorResponse{Error: "MISSING_VAT", Message: "no VAT number provided"})
+			}
+
+			key := strings.ToUpper(vat)
+			result, ok := cache.Get(key)
+			if !ok {
+				var err error
+				if result, err = registry.Check(c.Request().Context(), vat); err != nil {
+					return httpError(err)
+				}
+				cache.Set(key, result)
+			}
+
+			if !result.Valid {
+				return echo.NewHTTPError(http.StatusUnprocessableEntity, errorResponse{Error: "INVALID_VAT", Message: "VAT number is not valid"})
+			}
+
+			c.Set(ResultKey, result)
+			return next(c)
+		}
+	}
+}
+
+// Result returns the check result stored by Middleware.
+func Result(c echo.Context) (*vies.CheckResult, bool) {
+	result, ok := c.Get(ResultKey).(*vies.CheckResult)
+	return result, ok
+}
+
+func httpError(err error) *echo.HTTPError {
+
+	var apiErr *vies.ApiError
+
+	switch {
+	case errors.Is(err, vies.ErrInvalidVat):
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, errorResponse{Error: "INVALID_INPUT", Message: err.Error()}).SetInternal(err)
+	case errors.Is(err, vies.ErrCountryNotAllowed):
+		return echo.NewHTTPError(http.StatusForbidden, errorResponse{Error: "COUNTRY_NOT_ALLOWED", Message: err.Error()}).SetInternal(err)
+	case errors.As(err, &apiErr) && apiErr.Err == "INVALID_INPUT":
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, errorResponse{Error: apiErr.Err, Message: apiErr.Message}).SetInternal(err)
+	case errors.As(err, &apiErr):
+		return echo.NewHTTPError(http.StatusServiceUnavailable, errorResponse{Error: apiErr.Err, Message: apiErr.Message}).SetInternal(err)
+	}
+	return echo.NewHTTPError(http.StatusServiceUnavailable, errorResponse{Error: "UPSTREAM_ERROR", Message: err.Error()}).SetInternal(err)
+}
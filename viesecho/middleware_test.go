@@ -0,0 +1,84 @@
+package viesecho
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type registryFunc func(ctx context.Context, vat string) (*vies.CheckResult, error)
+
+func (f registryFunc) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+	return f(ctx, vat)
+}
+
+func TestMiddleware(t *testing.T) {
+
+	calls := 0
+	registry := registryFunc(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+		calls++
+		switch vat {
+		case "EE100354546":
+			return &vies.CheckResult{Vat: vat, Valid: true, Name: "ACME"}, nil
+		case "DE123456789":
+			return nil, &vies.ApiError{Err: "MS_UNAVAILABLE", Message: "down"}
+		case "E":
+			return nil, vies.ErrInvalidVat
+		case "FR40303265045":
+			return nil, fmt.Errorf("%w %s", vies.ErrCountryNotAllowed, "FR")
+		}
+		return &vies.CheckResult{Vat: vat}, nil
+	})
+
+	e := echo.New()
+	e.Use(Middleware(&Config{
+		Registry: registry,
+		Extract: func(c echo.Context) string {
+			return c.QueryParam("vat")
+		},
+		Skipper: func(c echo.Context) bool {
+			return c.Path() == "/health"
+		},
+	}))
+	e.GET("/order", func(c echo.Context) error {
+		result, ok := Result(c)
+		assert.True(t, ok)
+		return c.String(http.StatusOK, result.Name)
+	})
+	e.GET("/health", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	cases := []struct {
+		name string
+		url  string
+		code int
+		body string
+	}{
+		{name: "valid", url: "/order?vat=EE100354546", code: http.StatusOK, body: "ACME"},
+		{name: "cached", url: "/order?vat=ee100354546", code: http.StatusOK, body: "ACME"},
+		{name: "skipped", url: "/health", code: http.StatusOK, body: "ok"},
+		{name: "missing", url: "/order", code: http.StatusUnprocessableEntity, body: `{"error":"MISSING_VAT","message":"no VAT number provided"}`},
+		{name: "invalid", url: "/order?vat=EE100354547", code: http.StatusUnprocessableEntity, body: `{"error":"INVALID_VAT","message":"VAT number is not valid"}`},
+		{name: "malformed", url: "/order?vat=E", code: http.StatusUnprocessableEntity, body: `{"error":"INVALID_INPUT","message":"invalid VAT provided"}`},
+		{name: "unavailable", url: "/order?vat=DE123456789", code: http.StatusServiceUnavailable, body: `{"error":"MS_UNAVAILABLE","message":"down"}`},
+		{name: "not allowed", url: "/order?vat=FR40303265045", code: http.StatusForbidden, body: `{"error":"COUNTRY_NOT_ALLOWED","message":"member state not allowed FR"}`},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.body, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+	assert.Equal(t, 5, calls)
+}
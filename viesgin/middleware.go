@@ -0,0 +1,123 @@
+// Package viesgin provides a Gin middleware requiring a valid VAT number on
+// the requests it guards.
+package viesgin
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ResultKey is the key of the *vies.CheckResult in the gin.Context.
+	ResultKey = "vies.result"
+
+	defaultHeader   = "X-Vat-Number"
+	defaultCacheTTL = 24 * time.Hour
+)
+
+type Config struct {
+	// Registry checks the numbers, usually a *vies.Client or *vies.Router.
+	Registry vies.RegistryInterface
+	// Header the VAT number is read from, X-Vat-Number by default.
+	Header string
+	// Param is the path parameter the VAT number is read from, it takes
+	// precedence over Header when set.
+	Param string
+	// Cache keeps the results between requests, in memory for a day by
+	// default.
+	Cache vies.CacheInterface
+}
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// Middleware checks the VAT number of each request and aborts with 422
+// when it is missing, malformed or invalid, and with 503 when it could not
+// be checked. The result of a valid number is available to the handlers
+// through Result.
+func Middleware(config *Config) gin.HandlerFunc {
+
+	var registry vies.RegistryInterface
+	var param string
+	var cache vies.CacheInterface
+
+	header := defaultHeader
+
+	if config != nil {
+		registry = config.Registry
+		param = config.Param
+		if config.Header != "" {
+			header = config.Header
+		}
+		cache = config.Cache
+	}
+	if cache == nil {
+		cache = vies.NewMemoryCache(defaultCacheTTL)
+	}
+
+	return func(c *gin.Context) {
+
+		vat := c.GetHeader(header)
+		if param != "" {
+			vat = c.Param(param)
+		}
+		if vat == "" {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse{Error: "MISSING_VAT", Message: "no VAT number provided"})
+			return
+		}
+
+		key := strings.ToUpper(vat)
+		result, ok := cache.Get(key)
+		if !ok {
+			var err error
+			if result, err = registry.Check(c.Request.Context(), vat); err != nil {
+				abortWithError(c, err)
+				return
+			}
+			cache.Set(key, result)
+		}
+
+		if !result.Valid {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse{Error: "INVALID_VAT", Message: "VAT number is not valid"})
+			return
+		}
+
+		c.Set(ResultKey, result)
+		c.Next()
+	}
+}
+
+// Result returns the check result stored by Middleware.
+func Result(c *gin.Context) (*vies.CheckResult, bool) {
+	result, ok := c.Get(ResultKey)
+	if !ok {
+		return nil, false
+	}
+	r, ok := result.(*vies.CheckResult)
+	return r, ok
+}
+
+func abortWithError(c *gin.Context, err error) {
+
+	var apiErr *vies.ApiError
+
+	switch {
+	case errors.Is(err, vies.ErrInvalidVat):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse{Error: "INVALID_INPUT", Message: err.Error()})
+	case errors.Is(err, vies.ErrCountryNotAllowed):
+		c.AbortWithStatusJSON(http.StatusForbidden, errorResponse{Error: "COUNTRY_NOT_ALLOWED", Message: err.Error()})
+	case errors.As(err, &apiErr) && apiErr.Err == "INVALID_INPUT":
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, errorResponse{Error: apiErr.Err, Message: apiErr.Message})
+	case errors.As(err, &apiErr):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, errorResponse{Error: apiErr.Err, Message: apiErr.Message})
+	default:
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, errorResponse{Error: "UPSTREAM_ERROR", Message: err.Error()})
+	}
+}
@@ -0,0 +1,97 @@
+package viesgin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type registryFunc func(ctx context.Context, vat string) (*vies.CheckResult, error)
+
+func (f registryFunc) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+	return f(ctx, vat)
+}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestMiddleware(t *testing.T) {
+
+	calls := 0
+	registry := registryFunc(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+		calls++
+		switch vat {
+		case "EE100354546":
+			return &vies.CheckResult{Vat: vat, Valid: true, Name: "ACME"}, nil
+		case "DE123456789":
+			return nil, &vies.ApiError{Err: "MS_UNAVAILABLE", Message: "down"}
+		case "E":
+			return nil, vies.ErrInvalidVat
+		case "FR40303265045":
+			return nil, fmt.Errorf("%w %s", vies.ErrCountryNotAllowed, "FR")
+		}
+		return &vies.CheckResult{Vat: vat}, nil
+	})
+
+	router := gin.New()
+	router.Use(Middleware(&Config{Registry: registry}))
+	router.GET("/order", func(c *gin.Context) {
+		result, ok := Result(c)
+		assert.True(t, ok)
+		c.String(http.StatusOK, result.Name)
+	})
+
+	cases := []struct {
+		name string
+		vat  string
+		code int
+		body string
+	}{
+		{name: "valid", vat: "EE100354546", code: http.StatusOK, body: "ACME"},
+		{name: "cached", vat: "ee100354546", code: http.StatusOK, body: "ACME"},
+		{name: "missing", code: http.StatusUnprocessableEntity, body: `{"error":"MISSING_VAT","message":"no VAT number provided"}`},
+		{name: "invalid", vat: "EE100354547", code: http.StatusUnprocessableEntity, body: `{"error":"INVALID_VAT","message":"VAT number is not valid"}`},
+		{name: "malformed", vat: "E", code: http.StatusUnprocessableEntity, body: `{"error":"INVALID_INPUT","message":"invalid VAT provided"}`},
+		{name: "unavailable", vat: "DE123456789", code: http.StatusServiceUnavailable, body: `{"error":"MS_UNAVAILABLE","message":"down"}`},
+		{name: "not allowed", vat: "FR40303265045", code: http.StatusForbidden, body: `{"error":"COUNTRY_NOT_ALLOWED","message":"member state not allowed FR"}`},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/order", nil)
+			if tt.vat != "" {
+				req.Header.Set("X-Vat-Number", tt.vat)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+	assert.Equal(t, 5, calls)
+}
+
+func TestMiddlewareParam(t *testing.T) {
+
+	registry := registryFunc(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+		return &vies.CheckResult{Vat: vat, Valid: true}, nil
+	})
+
+	router := gin.New()
+	router.GET("/customers/:vat", Middleware(&Config{Registry: registry, Param: "vat"}), func(c *gin.Context) {
+		result, _ := Result(c)
+		c.String(http.StatusOK, result.Vat)
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/customers/EE100354546", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "EE100354546", rec.Body.String())
+}
@@ -0,0 +1,300 @@
+// Package viesgorm is a GORM plugin validating the VAT numbers of models
+// on create and update.
+//
+// Fields of type vies.VatNumber, and string fields tagged vies:"vat", are
+// normalized and their format checked offline, a malformed number failing
+// the write with vies.ErrInvalidVat. Empty numbers are left alone and
+// fields tagged vies:"-" are skipped.
+//
+// With a registry configured the numbers are also checked online and the
+// result is written to companion fields named after the VAT field, when the
+// model has them:
+//
+//	type Customer struct {
+//		ID           uint
+//		Vat          vies.VatNumber
+//		VatValid     bool
+//		VatName      string
+//		VatCheckedAt time.Time
+//	}
+package viesgorm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	tagName  = "vies"
+	tagVat   = "vat"
+	tagSkip  = "-"
+	callback = "vies:vat"
+
+	suffixValid     = "Valid"
+	suffixName      = "Name"
+	suffixCheckedAt = "CheckedAt"
+)
+
+var vatNumberType = reflect.TypeOf(vies.VatNumber(""))
+
+type Config struct {
+	// Registry checks the numbers online, only their format is checked
+	// when it is nil.
+	Registry vies.RegistryInterface
+	// Async checks the numbers online after the write is committed and
+	// updates the companion columns, instead of checking them before the
+	// write.
+	Async bool
+	// OnError receives the errors of online checks, which never fail a
+	// write.
+	OnError func(error)
+}
+
+type Plugin struct {
+	registry vies.RegistryInterface
+	async    bool
+	onError  func(error)
+	wg       sync.WaitGroup
+}
+
+// New returns the plugin, to be registered with db.Use.
+func New(config *Config) *Plugin {
+
+	p := &Plugin{onError: func(error) {}}
+
+	if config != nil {
+		p.registry = config.Registry
+		p.async = config.Async
+		if config.OnError != nil {
+			p.onError = config.OnError
+		}
+	}
+
+	return p
+}
+
+func (p *Plugin) Name() string {
+	return "vies"
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+
+	if err := db.Callback().Create().Before("gorm:create").Register(callback, p.before); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register(callback, p.before); err != nil {
+		return err
+	}
+	if !p.async || p.registry == nil {
+		return nil
+	}
+	if err := db.Callback().Create().After("gorm:commit_or_rollback_transaction").Register(callback+"_async", p.after); err != nil {
+		return err
+	}
+	return db.Callback().Update().After("gorm:commit_or_rollback_transaction").Register(callback+"_async", p.after)
+}
+
+// Wait blocks until the asynchronous checks started so far are done.
+func (p *Plugin) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Plugin) before(db *gorm.DB) {
+
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	fields := vatFields(db.Statement.Schema)
+	if len(fields) == 0 {
+		return
+	}
+
+	if values, ok := db.Statement.Dest.(map[string]any); ok {
+		for _, field := range fields {
+			for _, key := range []string{field.Name, field.DBName} {
+				if v, ok := values[key]; ok && v != nil && !reflect.ValueOf(v).IsZero() {
+					vat, err := parse(v)
+					if err != nil {
+						_ = db.AddError(fmt.Errorf("%s: %w", field.Name, err))
+						return
+					}
+					values[key] = convert(vat, field)
+				}
+			}
+		}
+		return
+	}
+
+	ctx := db.Statement.Context
+	for _, value := range models(db.Statement) {
+		for _, field := range fields {
+
+			v, zero := field.ValueOf(ctx, value)
+			if zero {
+				continue
+			}
+			vat, err := parse(v)
+			if err != nil {
+				_ = db.AddError(fmt.Errorf("%s: %w", field.Name, err))
+				return
+			}
+			if err := field.Set(ctx, value, convert(vat, field)); err != nil {
+				_ = db.AddError(err)
+				return
+			}
+
+			if p.registry != nil && !p.async {
+				p.check(ctx, db.Statement.Schema, field, vat, func(column *schema.Field, v any) {
+					_ = db.AddError(column.Set(ctx, value, v))
+				})
+			}
+		}
+	}
+}
+
+func (p *Plugin) after(db *gorm.DB) {
+
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	sch := db.Statement.Schema
+	fields := vatFields(sch)
+	if len(fields) == 0 || sch.PrioritizedPrimaryField == nil {
+		return
+	}
+
+	ctx := db.Statement.Context
+	session := db.Session(&gorm.Session{NewDB: true, Context: context.WithoutCancel(ctx)})
+
+	for _, value := range models(db.Statement) {
+
+		key, zero := sch.PrioritizedPrimaryField.ValueOf(ctx, value)
+		if zero {
+			continue
+		}
+
+		for _, field := range fields {
+			v, zero := field.ValueOf(ctx, value)
+			if zero {
+				continue
+			}
+			vat, err := parse(v)
+			if err != nil {
+				continue
+			}
+
+			p.wg.Add(1)
+			go func() {
+				defer p.wg.Done()
+				columns := map[string]any{}
+				p.check(context.WithoutCancel(ctx), sch, field, vat, func(column *schema.Field, v any) {
+					columns[column.DBName] = v
+				})
+				if len(columns) == 0 {
+					return
+				}
+				err := session.Table(sch.Table).
+					Where(map[string]any{sch.PrioritizedPrimaryField.DBName: key}).
+					UpdateColumns(columns).Error
+				if err != nil {
+					p.onError(err)
+				}
+			}()
+		}
+	}
+}
+
+// check verifies vat online and passes the companion fields of field with
+// their new values to set.
+func (p *Plugin) check(ctx context.Context, sch *schema.Schema, field *schema.Field, vat vies.VatNumber, set func(*schema.Field, any)) {
+
+	result, err := p.registry.Check(ctx, vat.String())
+	if err != nil {
+		p.onError(fmt.Errorf("%s: %w", field.Name, err))
+		return
+	}
+
+	if column := sch.LookUpField(field.Name + suffixValid); column != nil {
+		set(column, result.Valid)
+	}
+	if column := sch.LookUpField(field.Name + suffixName); column != nil {
+		set(column, result.Name)
+	}
+	if column := sch.LookUpField(field.Name + suffixCheckedAt); column != nil {
+		set(column, time.Now())
+	}
+}
+
+func vatFields(sch *schema.Schema) []*schema.Field {
+	var fields []*schema.Field
+	for _, field := range sch.Fields {
+		switch field.Tag.Get(tagName) {
+		case tagSkip:
+			continue
+		case tagVat:
+			fields = append(fields, field)
+			continue
+		}
+		if field.FieldType == vatNumberType {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// models returns the structs written by the statement, taken from Dest
+// when it holds the values of an update of a model.
+func models(stmt *gorm.Statement) []reflect.Value {
+
+	value := stmt.ReflectValue
+	dest := reflect.ValueOf(stmt.Dest)
+	for dest.Kind() == reflect.Ptr {
+		dest = dest.Elem()
+	}
+	if dest.Kind() == reflect.Struct && dest.CanAddr() && dest.Type() == stmt.Schema.ModelType {
+		value = dest
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return []reflect.Value{value}
+	case reflect.Slice, reflect.Array:
+		values := make([]reflect.Value, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			v := reflect.Indirect(value.Index(i))
+			if v.Kind() == reflect.Struct {
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func parse(v any) (vies.VatNumber, error) {
+	switch v := v.(type) {
+	case vies.VatNumber:
+		return vies.ParseVatNumber(string(v))
+	case string:
+		return vies.ParseVatNumber(v)
+	case *string:
+		if v != nil {
+			return vies.ParseVatNumber(*v)
+		}
+	}
+	return "", fmt.Errorf("%w: unsupported type %T", vies.ErrInvalidVat, v)
+}
+
+func convert(vat vies.VatNumber, field *schema.Field) any {
+	if field.FieldType == vatNumberType {
+		return vat
+	}
+	return string(vat)
+}
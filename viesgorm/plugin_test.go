@@ -0,0 +1,110 @@
+package viesgorm
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type registryFunc func(ctx context.Context, vat string) (*vies.CheckResult, error)
+
+func (f registryFunc) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+	return f(ctx, vat)
+}
+
+type customer struct {
+	ID           uint
+	Vat          vies.VatNumber
+	VatValid     bool
+	VatName      string
+	VatCheckedAt time.Time
+	Billing      string         `vies:"vat"`
+	Note         vies.VatNumber `vies:"-"`
+}
+
+var registry = registryFunc(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+	if vat == "DE123456789" {
+		return nil, vies.ErrCountryUnavailable
+	}
+	return &vies.CheckResult{Vat: vat, Valid: vat == "EE100354546", Name: "ACME"}, nil
+})
+
+func open(t *testing.T, plugin *Plugin) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	assert.NoError(t, err)
+	// every connection to :memory: opens its own database
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	assert.NoError(t, db.Use(plugin))
+	assert.NoError(t, db.AutoMigrate(&customer{}))
+	return db
+}
+
+func TestOffline(t *testing.T) {
+
+	db := open(t, New(nil))
+
+	c := customer{Vat: "ee 100 354 546", Billing: "atu12345678", Note: "anything"}
+	assert.NoError(t, db.Create(&c).Error)
+	assert.Equal(t, vies.VatNumber("EE100354546"), c.Vat)
+	assert.Equal(t, "ATU12345678", c.Billing)
+	assert.False(t, c.VatValid)
+
+	err := db.Create(&customer{Vat: "EE1"}).Error
+	assert.True(t, errors.Is(err, vies.ErrInvalidVat))
+
+	err = db.Model(&c).Update("billing", "AT1").Error
+	assert.True(t, errors.Is(err, vies.ErrInvalidVat))
+	assert.NoError(t, db.Model(&c).Update("billing", "").Error)
+
+	assert.NoError(t, db.Create(&[]customer{{Vat: "DE 123456789"}, {}}).Error)
+	var stored customer
+	assert.NoError(t, db.Where("vat = ?", "DE123456789").First(&stored).Error)
+}
+
+func TestOnline(t *testing.T) {
+
+	var failures atomic.Int32
+	db := open(t, New(&Config{Registry: registry, OnError: func(error) { failures.Add(1) }}))
+
+	c := customer{Vat: "EE100354546"}
+	assert.NoError(t, db.Create(&c).Error)
+	assert.True(t, c.VatValid)
+	assert.Equal(t, "ACME", c.VatName)
+	assert.False(t, c.VatCheckedAt.IsZero())
+
+	c.Vat = "EE100354547"
+	assert.NoError(t, db.Save(&c).Error)
+	var stored customer
+	assert.NoError(t, db.First(&stored, c.ID).Error)
+	assert.False(t, stored.VatValid)
+
+	assert.NoError(t, db.Create(&customer{Vat: "DE123456789"}).Error)
+	assert.Equal(t, int32(1), failures.Load())
+}
+
+func TestAsync(t *testing.T) {
+
+	plugin := New(&Config{Registry: registry, Async: true})
+	db := open(t, plugin)
+
+	c := customer{Vat: "EE100354546"}
+	assert.NoError(t, db.Create(&c).Error)
+	assert.False(t, c.VatValid)
+
+	plugin.Wait()
+	var stored customer
+	assert.NoError(t, db.First(&stored, c.ID).Error)
+	assert.True(t, stored.VatValid)
+	assert.Equal(t, "ACME", stored.VatName)
+	assert.False(t, stored.VatCheckedAt.IsZero())
+}
@@ -0,0 +1,87 @@
+// Package viesgrpc exposes a VIES client as a gRPC service. The protobuf
+// definitions live in vies.proto.
+package viesgrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative vies.proto
+
+import (
+	"context"
+	"errors"
+
+	"github.com/alytsin/go-vies"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Server struct {
+	UnimplementedVatServiceServer
+	client *vies.Client
+}
+
+// NewServer returns a VatServiceServer backed by the client, register it
+// with RegisterVatServiceServer.
+func NewServer(client *vies.Client) *Server {
+	return &Server{client: client}
+}
+
+func (s *Server) Check(ctx context.Context, req *CheckRequest) (*CheckResult, error) {
+	result, err := s.client.Check(ctx, req.GetVat())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &CheckResult{
+		CountryCode:       string(result.CountryCode),
+		VatNumber:         result.VatNumber,
+		Vat:               result.Vat,
+		Valid:             result.Valid,
+		Name:              result.Name,
+		Address:           result.Address,
+		RequestDate:       result.RequestDate,
+		RequestIdentifier: result.RequestIdentifier,
+	}, nil
+}
+
+func (s *Server) Status(ctx context.Context, _ *StatusRequest) (*StatusResponse, error) {
+	result, err := s.client.Status(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	rsp := &StatusResponse{VowAvailable: result.Vow.Available}
+	for _, country := range result.Countries {
+		rsp.Countries = append(rsp.Countries, &CountryStatus{
+			CountryCode:  string(country.CountryCode),
+			Availability: string(country.Availability),
+		})
+	}
+	return rsp, nil
+}
+
+// toStatus maps client errors to gRPC status codes, VIES error codes are
+// kept in the message.
+func toStatus(err error) error {
+
+	var apiErr *vies.ApiError
+
+	switch {
+	case errors.Is(err, vies.ErrInvalidVat):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, vies.ErrCountryNotAllowed):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.As(err, &apiErr):
+		switch apiErr.Err {
+		case "INVALID_INPUT":
+			return status.Error(codes.InvalidArgument, err.Error())
+		case "MS_MAX_CONCURRENT_REQ", "GLOBAL_MAX_CONCURRENT_REQ":
+			return status.Error(codes.ResourceExhausted, err.Error())
+		case "SERVICE_UNAVAILABLE", "MS_UNAVAILABLE", "TIMEOUT":
+			return status.Error(codes.Unavailable, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
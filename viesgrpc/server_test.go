@@ -0,0 +1,91 @@
+package viesgrpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServer(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/check-vat-number":
+			_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"123","valid":true,"name":"Acme"}`))
+		case "/check-status":
+			_, _ = w.Write([]byte(`{"vow":{"available":true},"countries":[{"countryCode":"EE","availability":"Available"}]}`))
+		}
+	}))
+	defer upstream.Close()
+
+	client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: upstream.URL})
+	assert.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	RegisterVatServiceServer(server, NewServer(client))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	c := NewVatServiceClient(conn)
+
+	t.Run("check", func(t *testing.T) {
+		result, err := c.Check(context.Background(), &CheckRequest{Vat: "EE123"})
+		assert.NoError(t, err)
+		assert.Equal(t, "EE123", result.GetVat())
+		assert.True(t, result.GetValid())
+		assert.Equal(t, "Acme", result.GetName())
+	})
+
+	t.Run("check invalid input", func(t *testing.T) {
+		_, err := c.Check(context.Background(), &CheckRequest{Vat: "E"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("status", func(t *testing.T) {
+		result, err := c.Status(context.Background(), &StatusRequest{})
+		assert.NoError(t, err)
+		assert.True(t, result.GetVowAvailable())
+		assert.Len(t, result.GetCountries(), 1)
+		assert.Equal(t, "EE", result.GetCountries()[0].GetCountryCode())
+	})
+}
+
+func TestToStatus(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		code codes.Code
+	}{
+		"api invalid input": {&vies.ApiError{Err: "INVALID_INPUT"}, codes.InvalidArgument},
+		"member state down": {&vies.ApiError{Err: "MS_UNAVAILABLE"}, codes.Unavailable},
+		"throttled":         {&vies.ApiError{Err: "MS_MAX_CONCURRENT_REQ"}, codes.ResourceExhausted},
+		"unknown api error": {&vies.ApiError{Err: "BOGUS"}, codes.Internal},
+		"deadline":          {context.DeadlineExceeded, codes.DeadlineExceeded},
+		"not allowed":       {fmt.Errorf("%w %s", vies.ErrCountryNotAllowed, "FR"), codes.FailedPrecondition},
+	}
+
+	for name, tt := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(toStatus(tt.err)))
+		})
+	}
+}
@@ -0,0 +1,395 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: vies.proto
+
+package viesgrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CheckRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// VAT number prefixed with the member state code, e.g. EE100354546.
+	Vat           string `protobuf:"bytes,1,opt,name=vat,proto3" json:"vat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRequest) Reset() {
+	*x = CheckRequest{}
+	mi := &file_vies_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRequest) ProtoMessage() {}
+
+func (x *CheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vies_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRequest.ProtoReflect.Descriptor instead.
+func (*CheckRequest) Descriptor() ([]byte, []int) {
+	return file_vies_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CheckRequest) GetVat() string {
+	if x != nil {
+		return x.Vat
+	}
+	return ""
+}
+
+type CheckResult struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	CountryCode string                 `protobuf:"bytes,1,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	VatNumber   string                 `protobuf:"bytes,2,opt,name=vat_number,json=vatNumber,proto3" json:"vat_number,omitempty"`
+	Vat         string                 `protobuf:"bytes,3,opt,name=vat,proto3" json:"vat,omitempty"`
+	Valid       bool                   `protobuf:"varint,4,opt,name=valid,proto3" json:"valid,omitempty"`
+	Name        string                 `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	Address     string                 `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	RequestDate string                 `protobuf:"bytes,7,opt,name=request_date,json=requestDate,proto3" json:"request_date,omitempty"`
+	// Consultation number, set when the server is configured with a requester.
+	RequestIdentifier string `protobuf:"bytes,8,opt,name=request_identifier,json=requestIdentifier,proto3" json:"request_identifier,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CheckResult) Reset() {
+	*x = CheckResult{}
+	mi := &file_vies_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckResult) ProtoMessage() {}
+
+func (x *CheckResult) ProtoReflect() protoreflect.Message {
+	mi := &file_vies_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckResult.ProtoReflect.Descriptor instead.
+func (*CheckResult) Descriptor() ([]byte, []int) {
+	return file_vies_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CheckResult) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *CheckResult) GetVatNumber() string {
+	if x != nil {
+		return x.VatNumber
+	}
+	return ""
+}
+
+func (x *CheckResult) GetVat() string {
+	if x != nil {
+		return x.Vat
+	}
+	return ""
+}
+
+func (x *CheckResult) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *CheckResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CheckResult) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *CheckResult) GetRequestDate() string {
+	if x != nil {
+		return x.RequestDate
+	}
+	return ""
+}
+
+func (x *CheckResult) GetRequestIdentifier() string {
+	if x != nil {
+		return x.RequestIdentifier
+	}
+	return ""
+}
+
+type StatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	mi := &file_vies_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vies_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_vies_proto_rawDescGZIP(), []int{2}
+}
+
+type StatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	VowAvailable  bool                   `protobuf:"varint,1,opt,name=vow_available,json=vowAvailable,proto3" json:"vow_available,omitempty"`
+	Countries     []*CountryStatus       `protobuf:"bytes,2,rep,name=countries,proto3" json:"countries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_vies_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vies_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_vies_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StatusResponse) GetVowAvailable() bool {
+	if x != nil {
+		return x.VowAvailable
+	}
+	return false
+}
+
+func (x *StatusResponse) GetCountries() []*CountryStatus {
+	if x != nil {
+		return x.Countries
+	}
+	return nil
+}
+
+type CountryStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CountryCode   string                 `protobuf:"bytes,1,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	Availability  string                 `protobuf:"bytes,2,opt,name=availability,proto3" json:"availability,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountryStatus) Reset() {
+	*x = CountryStatus{}
+	mi := &file_vies_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountryStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountryStatus) ProtoMessage() {}
+
+func (x *CountryStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_vies_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountryStatus.ProtoReflect.Descriptor instead.
+func (*CountryStatus) Descriptor() ([]byte, []int) {
+	return file_vies_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CountryStatus) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *CountryStatus) GetAvailability() string {
+	if x != nil {
+		return x.Availability
+	}
+	return ""
+}
+
+var File_vies_proto protoreflect.FileDescriptor
+
+const file_vies_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"vies.proto\x12\avies.v1\" \n" +
+	"\fCheckRequest\x12\x10\n" +
+	"\x03vat\x18\x01 \x01(\tR\x03vat\"\xf7\x01\n" +
+	"\vCheckResult\x12!\n" +
+	"\fcountry_code\x18\x01 \x01(\tR\vcountryCode\x12\x1d\n" +
+	"\n" +
+	"vat_number\x18\x02 \x01(\tR\tvatNumber\x12\x10\n" +
+	"\x03vat\x18\x03 \x01(\tR\x03vat\x12\x14\n" +
+	"\x05valid\x18\x04 \x01(\bR\x05valid\x12\x12\n" +
+	"\x04name\x18\x05 \x01(\tR\x04name\x12\x18\n" +
+	"\aaddress\x18\x06 \x01(\tR\aaddress\x12!\n" +
+	"\frequest_date\x18\a \x01(\tR\vrequestDate\x12-\n" +
+	"\x12request_identifier\x18\b \x01(\tR\x11requestIdentifier\"\x0f\n" +
+	"\rStatusRequest\"k\n" +
+	"\x0eStatusResponse\x12#\n" +
+	"\rvow_available\x18\x01 \x01(\bR\fvowAvailable\x124\n" +
+	"\tcountries\x18\x02 \x03(\v2\x16.vies.v1.CountryStatusR\tcountries\"V\n" +
+	"\rCountryStatus\x12!\n" +
+	"\fcountry_code\x18\x01 \x01(\tR\vcountryCode\x12\"\n" +
+	"\favailability\x18\x02 \x01(\tR\favailability2}\n" +
+	"\n" +
+	"VatService\x124\n" +
+	"\x05Check\x12\x15.vies.v1.CheckRequest\x1a\x14.vies.v1.CheckResult\x129\n" +
+	"\x06Status\x12\x16.vies.v1.StatusRequest\x1a\x17.vies.v1.StatusResponseB%Z#github.com/alytsin/go-vies/viesgrpcb\x06proto3"
+
+var (
+	file_vies_proto_rawDescOnce sync.Once
+	file_vies_proto_rawDescData []byte
+)
+
+func file_vies_proto_rawDescGZIP() []byte {
+	file_vies_proto_rawDescOnce.Do(func() {
+		file_vies_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_vies_proto_rawDesc), len(file_vies_proto_rawDesc)))
+	})
+	return file_vies_proto_rawDescData
+}
+
+var file_vies_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_vies_proto_goTypes = []any{
+	(*CheckRequest)(nil),   // 0: vies.v1.CheckRequest
+	(*CheckResult)(nil),    // 1: vies.v1.CheckResult
+	(*StatusRequest)(nil),  // 2: vies.v1.StatusRequest
+	(*StatusResponse)(nil), // 3: vies.v1.StatusResponse
+	(*CountryStatus)(nil),  // 4: vies.v1.CountryStatus
+}
+var file_vies_proto_depIdxs = []int32{
+	4, // 0: vies.v1.StatusResponse.countries:type_name -> vies.v1.CountryStatus
+	0, // 1: vies.v1.VatService.Check:input_type -> vies.v1.CheckRequest
+	2, // 2: vies.v1.VatService.Status:input_type -> vies.v1.StatusRequest
+	1, // 3: vies.v1.VatService.Check:output_type -> vies.v1.CheckResult
+	3, // 4: vies.v1.VatService.Status:output_type -> vies.v1.StatusResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_vies_proto_init() }
+func file_vies_proto_init() {
+	if File_vies_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_vies_proto_rawDesc), len(file_vies_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vies_proto_goTypes,
+		DependencyIndexes: file_vies_proto_depIdxs,
+		MessageInfos:      file_vies_proto_msgTypes,
+	}.Build()
+	File_vies_proto = out.File
+	file_vies_proto_goTypes = nil
+	file_vies_proto_depIdxs = nil
+}
@@ -0,0 +1,167 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: vies.proto
+
+package viesgrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VatService_Check_FullMethodName  = "/vies.v1.VatService/Check"
+	VatService_Status_FullMethodName = "/vies.v1.VatService/Status"
+)
+
+// VatServiceClient is the client API for VatService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VatService validates VAT numbers against VIES.
+type VatServiceClient interface {
+	// Check returns the VIES response for a single VAT number.
+	Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*CheckResult, error)
+	// Status returns the availability of VIES and of each member state.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+}
+
+type vatServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVatServiceClient(cc grpc.ClientConnInterface) VatServiceClient {
+	return &vatServiceClient{cc}
+}
+
+func (c *vatServiceClient) Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*CheckResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckResult)
+	err := c.cc.Invoke(ctx, VatService_Check_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vatServiceClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, VatService_Status_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VatServiceServer is the server API for VatService service.
+// All implementations must embed UnimplementedVatServiceServer
+// for forward compatibility.
+//
+// VatService validates VAT numbers against VIES.
+type VatServiceServer interface {
+	// Check returns the VIES response for a single VAT number.
+	Check(context.Context, *CheckRequest) (*CheckResult, error)
+	// Status returns the availability of VIES and of each member state.
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	mustEmbedUnimplementedVatServiceServer()
+}
+
+// UnimplementedVatServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVatServiceServer struct{}
+
+func (UnimplementedVatServiceServer) Check(context.Context, *CheckRequest) (*CheckResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Check not implemented")
+}
+func (UnimplementedVatServiceServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedVatServiceServer) mustEmbedUnimplementedVatServiceServer() {}
+func (UnimplementedVatServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeVatServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VatServiceServer will
+// result in compilation errors.
+type UnsafeVatServiceServer interface {
+	mustEmbedUnimplementedVatServiceServer()
+}
+
+func RegisterVatServiceServer(s grpc.ServiceRegistrar, srv VatServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVatServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VatService_ServiceDesc, srv)
+}
+
+func _VatService_Check_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VatServiceServer).Check(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VatService_Check_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VatServiceServer).Check(ctx, req.(*CheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VatService_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VatServiceServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VatService_Status_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VatServiceServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// VatService_ServiceDesc is the grpc.ServiceDesc for VatService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VatService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vies.v1.VatService",
+	HandlerType: (*VatServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Check",
+			Handler:    _VatService_Check_Handler,
+		},
+		{
+			MethodName: "Status",
+			Handler:    _VatService_Status_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "vies.proto",
+}
@@ -0,0 +1,251 @@
+// Package vieskafka consumes VAT check requests from a Kafka topic, checks
+// them with the batch engine of vies.Client and produces the results to
+// another topic:
+//
+//	worker := vieskafka.NewWorker(client, &vieskafka.Config{
+//		Reader: kafka.NewReader(kafka.ReaderConfig{Brokers: brokers, GroupID: "vies", Topic: "vat-checks"}),
+//		Writer: &kafka.Writer{Addr: kafka.TCP(brokers...), Topic: "vat-results"},
+//	})
+//	err := worker.Run(ctx)
+//
+// Offsets are committed only once the results of a batch are written, so
+// every request is answered at least once.
+package vieskafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	defaultBatchSize = 100
+	defaultBatchWait = time.Second
+)
+
+// ReaderInterface is the part of *kafka.Reader used by the worker.
+type ReaderInterface interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// WriterInterface is the part of *kafka.Writer used by the worker.
+type WriterInterface interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Request is the value of a message of the input topic. A value which is
+// not JSON is taken as the VAT number itself.
+type Request struct {
+	ID  string `json:"id,omitempty"`
+	Vat string `json:"vat"`
+}
+
+// Response is the value of a message of the output topic, produced with
+// the key of the request.
+type Response struct {
+	ID     string            `json:"id,omitempty"`
+	Vat    string            `json:"vat"`
+	Result *vies.CheckResult `json:"result,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+type Config struct {
+	Reader ReaderInterface
+	Writer WriterInterface
+	// BatchSize is the maximum number of requests checked together,
+	// defaults to 100.
+	BatchSize int
+	// BatchWait is how long a batch waits for more requests once the first
+	// one arrived, defaults to a second.
+	BatchWait time.Duration
+	// Parallel is the number of checks running concurrently per member
+	// state, defaults to 1.
+	Parallel int
+	// CountryRateLimit is the maximum number of checks per second sent to
+	// each member state, zero means no limit.
+	CountryRateLimit float64
+}
+
+type Worker struct {
+	client    *vies.Client
+	reader    ReaderInterface
+	writer    WriterInterface
+	batchSize int
+	batchWait time.Duration
+	parallel  int
+	rateLimit float64
+
+	mu       sync.Mutex
+	limiters map[string]*vies.RateLimiter
+}
+
+func NewWorker(client *vies.Client, config *Config) *Worker {
+
+	w := &Worker{
+		client:    client,
+		batchSize: defaultBatchSize,
+		batchWait: defaultBatchWait,
+		parallel:  1,
+		limiters:  make(map[string]*vies.RateLimiter),
+	}
+
+	if config != nil {
+		w.reader = config.Reader
+		w.writer = config.Writer
+		if config.BatchSize > 0 {
+			w.batchSize = config.BatchSize
+		}
+		if config.BatchWait > 0 {
+			w.batchWait = config.BatchWait
+		}
+		if config.Parallel > 0 {
+			w.parallel = config.Parallel
+		}
+		w.rateLimit = config.CountryRateLimit
+	}
+
+	return w
+}
+
+// Run processes batches until the context is done or reading, writing or
+// committing fails. Requests of a failed batch are not committed and are
+// delivered again.
+func (w *Worker) Run(ctx context.Context) error {
+	for {
+		if err := w.process(ctx); err != nil {
+			if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (w *Worker) process(ctx context.Context) error {
+
+	batch, err := w.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	requests := make([]Request, len(batch))
+	for i, msg := range batch {
+		requests[i] = decode(msg.Value)
+	}
+
+	responses := w.check(ctx, requests)
+
+	out := make([]kafka.Message, len(batch))
+	for i, rsp := range responses {
+		value, err := json.Marshal(rsp)
+		if err != nil {
+			return err
+		}
+		out[i] = kafka.Message{Key: batch[i].Key, Value: value}
+	}
+
+	if err := w.writer.WriteMessages(ctx, out...); err != nil {
+		return fmt.Errorf("write results: %w", err)
+	}
+	if err := w.reader.CommitMessages(ctx, batch...); err != nil {
+		return fmt.Errorf("commit requests: %w", err)
+	}
+	return nil
+}
+
+// fetch blocks for the first message and then collects more for up to
+// batchWait.
+func (w *Worker) fetch(ctx context.Context) ([]kafka.Message, error) {
+
+	msg, err := w.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch := []kafka.Message{msg}
+
+	wait, cancel := context.WithTimeout(ctx, w.batchWait)
+	defer cancel()
+
+	for len(batch) < w.batchSize {
+		msg, err := w.reader.FetchMessage(wait)
+		if err != nil {
+			if wait.Err() != nil && ctx.Err() == nil {
+				break
+			}
+			return nil, err
+		}
+		batch = append(batch, msg)
+	}
+	return batch, nil
+}
+
+// check runs the requests of each member state through CheckAll, every
+// member state with its own rate limiter.
+func (w *Worker) check(ctx context.Context, requests []Request) []Response {
+
+	byCountry := make(map[string][]int)
+	for i, req := range requests {
+		country := ""
+		if len(req.Vat) >= 2 {
+			country = strings.ToUpper(req.Vat[0:2])
+		}
+		byCountry[country] = append(byCountry[country], i)
+	}
+
+	responses := make([]Response, len(requests))
+	var wg sync.WaitGroup
+	for country, indexes := range byCountry {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vats := make([]string, len(indexes))
+			for j, i := range indexes {
+				vats[j] = requests[i].Vat
+			}
+			results := w.client.CheckAll(ctx, vats, &vies.BulkConfig{Parallel: w.parallel, RateLimiter: w.limiter(country)})
+			for j, i := range indexes {
+				rsp := Response{ID: requests[i].ID, Vat: requests[i].Vat, Result: results[j].Result}
+				if results[j].Err != nil {
+					rsp.Error = results[j].Err.Error()
+				}
+				responses[i] = rsp
+			}
+		}()
+	}
+	wg.Wait()
+
+	return responses
+}
+
+func (w *Worker) limiter(country string) vies.RateLimiterInterface {
+
+	if w.rateLimit <= 0 {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	limiter, ok := w.limiters[country]
+	if !ok {
+		limiter = vies.NewRateLimiter(w.rateLimit, 1)
+		w.limiters[country] = limiter
+	}
+	return limiter
+}
+
+func decode(value []byte) Request {
+	var req Request
+	if err := json.Unmarshal(value, &req); err != nil {
+		return Request{Vat: strings.TrimSpace(string(value))}
+	}
+	return req
+}
@@ -0,0 +1,131 @@
+package vieskafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReader struct {
+	messages  chan kafka.Message
+	mu        sync.Mutex
+	committed []kafka.Message
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case msg := <-r.messages:
+		return msg, nil
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.committed = append(r.committed, msgs...)
+	return nil
+}
+
+type writerFunc func(ctx context.Context, msgs ...kafka.Message) error
+
+func (f writerFunc) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	return f(ctx, msgs...)
+}
+
+func newClient(t *testing.T) *vies.Client {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			CountryCode vies.CountryCode `json:"countryCode"`
+			VatNumber   string           `json:"vatNumber"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		_ = json.NewEncoder(w).Encode(vies.CheckResult{CountryCode: body.CountryCode, VatNumber: body.VatNumber, Valid: body.VatNumber != "000"})
+	}))
+	t.Cleanup(upstream.Close)
+
+	client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: upstream.URL})
+	assert.NoError(t, err)
+	return client
+}
+
+func TestWorker(t *testing.T) {
+
+	reader := &fakeReader{messages: make(chan kafka.Message, 10)}
+	reader.messages <- kafka.Message{Key: []byte("1"), Offset: 1, Value: []byte(`{"id":"a","vat":"EE123"}`)}
+	reader.messages <- kafka.Message{Key: []byte("2"), Offset: 2, Value: []byte(`DE000`)}
+	reader.messages <- kafka.Message{Key: []byte("3"), Offset: 3, Value: []byte(`{"vat":"E"}`)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var written []kafka.Message
+	writer := writerFunc(func(ctx context.Context, msgs ...kafka.Message) error {
+		written = append(written, msgs...)
+		cancel()
+		return nil
+	})
+
+	worker := NewWorker(newClient(t), &Config{Reader: reader, Writer: writer, BatchWait: 50 * time.Millisecond, CountryRateLimit: 10})
+	assert.NoError(t, worker.Run(ctx))
+
+	assert.Len(t, written, 3)
+	responses := make([]Response, len(written))
+	for i, msg := range written {
+		assert.NoError(t, json.Unmarshal(msg.Value, &responses[i]))
+	}
+
+	assert.Equal(t, []byte("1"), written[0].Key)
+	assert.Equal(t, "a", responses[0].ID)
+	assert.True(t, responses[0].Result.Valid)
+	assert.Equal(t, "DE000", responses[1].Vat)
+	assert.False(t, responses[1].Result.Valid)
+	assert.Equal(t, "invalid VAT provided E", responses[2].Error)
+	assert.Nil(t, responses[2].Result)
+
+	assert.Len(t, reader.committed, 3)
+}
+
+func TestWorkerWriteFailure(t *testing.T) {
+
+	reader := &fakeReader{messages: make(chan kafka.Message, 1)}
+	reader.messages <- kafka.Message{Value: []byte(`EE123`)}
+
+	writer := writerFunc(func(ctx context.Context, msgs ...kafka.Message) error {
+		return errors.New("broker down")
+	})
+
+	worker := NewWorker(newClient(t), &Config{Reader: reader, Writer: writer, BatchWait: 10 * time.Millisecond})
+	assert.EqualError(t, worker.Run(context.Background()), "write results: broker down")
+	assert.Empty(t, reader.committed)
+}
+
+func TestWorkerBatchSize(t *testing.T) {
+
+	reader := &fakeReader{messages: make(chan kafka.Message, 3)}
+	for range 3 {
+		reader.messages <- kafka.Message{Value: []byte(`EE123`)}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var batches []int
+	writer := writerFunc(func(ctx context.Context, msgs ...kafka.Message) error {
+		batches = append(batches, len(msgs))
+		if len(batches) == 2 {
+			cancel()
+		}
+		return nil
+	})
+
+	worker := NewWorker(newClient(t), &Config{Reader: reader, Writer: writer, BatchSize: 2, BatchWait: 20 * time.Millisecond})
+	assert.NoError(t, worker.Run(ctx))
+	assert.Equal(t, []int{2, 1}, batches)
+}
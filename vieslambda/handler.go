@@ -0,0 +1,88 @@
+// Package vieslambda runs VAT number checks as an AWS Lambda function
+// behind API Gateway:
+//
+//	var client, _ = vies.NewClient(nil)
+//
+//	func main() {
+//		vieslambda.Start(client)
+//	}
+//
+// The client is created once per execution environment, so warm
+// invocations reuse its connections, cache and rate limiter.
+package vieslambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/alytsin/go-vies"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+// Handler checks the VAT number taken from the "vat" path parameter or,
+// when the route has none, from the "vat" query string parameter, and
+// responds with the vies.CheckResult as JSON.
+func Handler(registry vies.RegistryInterface) func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return func(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+		vat := req.PathParameters["vat"]
+		if vat == "" {
+			vat = req.QueryStringParameters["vat"]
+		}
+		if id := req.Headers["X-Correlation-ID"]; id != "" {
+			ctx = vies.WithCorrelationID(ctx, id)
+		} else if id := req.RequestContext.RequestID; id != "" {
+			ctx = vies.WithCorrelationID(ctx, id)
+		}
+
+		result, err := registry.Check(ctx, vat)
+		if err != nil {
+			return errorResult(err)
+		}
+		return response(http.StatusOK, result)
+	}
+}
+
+// Start runs Handler until the Lambda runtime stops the function.
+func Start(registry vies.RegistryInterface) {
+	lambda.Start(Handler(registry))
+}
+
+func errorResult(err error) (events.APIGatewayProxyResponse, error) {
+
+	var apiErr *vies.ApiError
+
+	switch {
+	case errors.Is(err, vies.ErrInvalidVat):
+		return response(http.StatusBadRequest, errorResponse{Error: "INVALID_INPUT", Message: err.Error()})
+	case errors.Is(err, vies.ErrCountryUnavailable):
+		return response(http.StatusServiceUnavailable, errorResponse{Error: "MS_UNAVAILABLE", Message: err.Error()})
+	case errors.Is(err, vies.ErrCountryNotAllowed):
+		return response(http.StatusForbidden, errorResponse{Error: "COUNTRY_NOT_ALLOWED", Message: err.Error()})
+	case errors.As(err, &apiErr) && apiErr.Err == "INVALID_INPUT":
+		return response(http.StatusBadRequest, errorResponse{Error: apiErr.Err, Message: apiErr.Message})
+	case errors.As(err, &apiErr):
+		return response(http.StatusBadGateway, errorResponse{Error: apiErr.Err, Message: apiErr.Message})
+	}
+	return response(http.StatusBadGateway, errorResponse{Error: "UPSTREAM_ERROR", Message: err.Error()})
+}
+
+func response(code int, body any) (events.APIGatewayProxyResponse, error) {
+	content, err := json.Marshal(body)
+	if err != nil {
+		return events.APIGatewayProxyResponse{}, err
+	}
+	return events.APIGatewayProxyResponse{
+		StatusCode: code,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(content),
+	}, nil
+}
@@ -0,0 +1,78 @@
+package vieslambda
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+type registryFunc func(ctx context.Context, vat string) (*vies.CheckResult, error)
+
+func (f registryFunc) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+	return f(ctx, vat)
+}
+
+func TestHandler(t *testing.T) {
+
+	handler := Handler(registryFunc(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+		switch vat {
+		case "EE100354546":
+			assert.Equal(t, "req-1", vies.CorrelationIDFromContext(ctx))
+			return &vies.CheckResult{CountryCode: "EE", VatNumber: "100354546", Vat: vat, Valid: true}, nil
+		case "DE123456789":
+			return nil, &vies.ApiError{Err: "MS_UNAVAILABLE", Message: "down"}
+		case "FR40303265045":
+			return nil, fmt.Errorf("%w %s", vies.ErrCountryNotAllowed, "FR")
+		}
+		return nil, vies.ErrInvalidVat
+	}))
+
+	cases := []struct {
+		name string
+		req  events.APIGatewayProxyRequest
+		code int
+		body string
+	}{
+		{
+			name: "path parameter",
+			req: events.APIGatewayProxyRequest{
+				PathParameters: map[string]string{"vat": "EE100354546"},
+				RequestContext: events.APIGatewayProxyRequestContext{RequestID: "req-1"},
+			},
+			code: http.StatusOK,
+			body: `{"countryCode":"EE","address":"","vatNumber":"100354546","vat":"EE100354546","valid":true,"name":""}`,
+		},
+		{
+			name: "query parameter",
+			req:  events.APIGatewayProxyRequest{QueryStringParameters: map[string]string{"vat": "DE123456789"}},
+			code: http.StatusBadGateway,
+			body: `{"error":"MS_UNAVAILABLE","message":"down"}`,
+		},
+		{
+			name: "not allowed",
+			req:  events.APIGatewayProxyRequest{PathParameters: map[string]string{"vat": "FR40303265045"}},
+			code: http.StatusForbidden,
+			body: `{"error":"COUNTRY_NOT_ALLOWED","message":"member state not allowed FR"}`,
+		},
+		{
+			name: "missing",
+			code: http.StatusBadRequest,
+			body: `{"error":"INVALID_INPUT","message":"invalid VAT provided"}`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp, err := handler(context.Background(), tt.req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, rsp.StatusCode)
+			assert.Equal(t, tt.body, rsp.Body)
+			assert.Equal(t, "application/json", rsp.Headers["Content-Type"])
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package viesmetrics
+
+import (
+	"github.com/alytsin/go-vies"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Availability is a prometheus.Collector exposing the last status seen by
+// a vies.Watcher.
+type Availability struct {
+	watcher *vies.Watcher
+
+	up                  *prometheus.Desc
+	vowAvailable        *prometheus.Desc
+	countryAvailable    *prometheus.Desc
+	countryAvailability *prometheus.Desc
+}
+
+func NewAvailability(watcher *vies.Watcher) *Availability {
+	return &Availability{
+		watcher: watcher,
+		up: prometheus.NewDesc("vies_status_up",
+			"Whether the last poll of the VIES status succeeded.", nil, nil),
+		vowAvailable: prometheus.NewDesc("vies_vow_available",
+			"Whether VIES reports itself available.", nil, nil),
+		countryAvailable: prometheus.NewDesc("vies_country_available",
+			"Whether the member state is available.", []string{"country"}, nil),
+		countryAvailability: prometheus.NewDesc("vies_country_availability",
+			"Availability reported for the member state, 1 for the current one.", []string{"country", "availability"}, nil),
+	}
+}
+
+func (a *Availability) Describe(ch chan<- *prometheus.Desc) {
+	ch <- a.up
+	ch <- a.vowAvailable
+	ch <- a.countryAvailable
+	ch <- a.countryAvailability
+}
+
+// Collect reports the countries of the last successful poll, even when the
+// latest one failed, vies_status_up tells them apart.
+func (a *Availability) Collect(ch chan<- prometheus.Metric) {
+
+	status := a.watcher.Last()
+	ch <- prometheus.MustNewConstMetric(a.up, prometheus.GaugeValue, boolValue(status != nil && a.watcher.LastError() == nil))
+	if status == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(a.vowAvailable, prometheus.GaugeValue, boolValue(status.Vow.Available))
+	for _, country := range status.Countries {
+		ch <- prometheus.MustNewConstMetric(a.countryAvailable, prometheus.GaugeValue,
+			boolValue(country.Availability == vies.AvailabilityAvailable), string(country.CountryCode))
+		for _, availability := range []vies.Availability{vies.AvailabilityAvailable, vies.AvailabilityUnavailable, vies.AvailabilityMonitoringDisabled} {
+			ch <- prometheus.MustNewConstMetric(a.countryAvailability, prometheus.GaugeValue,
+				boolValue(country.Availability == availability), string(country.CountryCode), string(availability))
+		}
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
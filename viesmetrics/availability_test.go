@@ -0,0 +1,55 @@
+package viesmetrics
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvailability(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Unavailable"}]}`))
+	}))
+	defer upstream.Close()
+
+	client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: upstream.URL})
+	assert.NoError(t, err)
+
+	watcher := vies.NewWatcher(client, &vies.WatcherConfig{Interval: time.Hour})
+	collector := NewAvailability(watcher)
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP vies_status_up Whether the last poll of the VIES status succeeded.
+# TYPE vies_status_up gauge
+vies_status_up 0
+`)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = watcher.Run(ctx) }()
+	assert.Eventually(t, func() bool { return watcher.Last() != nil }, time.Second, time.Millisecond)
+
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(`
+# HELP vies_country_availability Availability reported for the member state, 1 for the current one.
+# TYPE vies_country_availability gauge
+vies_country_availability{availability="Available",country="DE"} 0
+vies_country_availability{availability="Monitoring Disabled",country="DE"} 0
+vies_country_availability{availability="Unavailable",country="DE"} 1
+# HELP vies_country_available Whether the member state is available.
+# TYPE vies_country_available gauge
+vies_country_available{country="DE"} 0
+# HELP vies_status_up Whether the last poll of the VIES status succeeded.
+# TYPE vies_status_up gauge
+vies_status_up 1
+# HELP vies_vow_available Whether VIES reports itself available.
+# TYPE vies_vow_available gauge
+vies_vow_available 1
+`)))
+}
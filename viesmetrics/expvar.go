@@ -0,0 +1,45 @@
+package viesmetrics
+
+import (
+	"expvar"
+
+	"github.com/alytsin/go-vies"
+)
+
+// Expvar publishes basic counters under an expvar map, served at
+// /debug/vars by the expvar package:
+//
+//	requests          requests sent to VIES
+//	errors.<code>     failed requests by error code
+//	cache.hit         cache hits
+//	cache.miss        cache misses
+//	retries           requests sent again by the retry policy
+//
+// Histograms are not published.
+type Expvar struct {
+	vars *expvar.Map
+}
+
+// NewExpvar publishes the counters under name, reusing the map when one
+// with that name is already published.
+func NewExpvar(name string) *Expvar {
+	if m, ok := expvar.Get(name).(*expvar.Map); ok {
+		return &Expvar{vars: m}
+	}
+	return &Expvar{vars: expvar.NewMap(name)}
+}
+
+func (e *Expvar) IncCounter(name string, labels map[string]string) {
+	switch name {
+	case vies.MetricRequests:
+		e.vars.Add("requests", 1)
+	case vies.MetricErrors:
+		e.vars.Add("errors."+labels["code"], 1)
+	case vies.MetricCacheRequests:
+		e.vars.Add("cache."+labels["result"], 1)
+	case vies.MetricRetries:
+		e.vars.Add("retries", 1)
+	}
+}
+
+func (e *Expvar) ObserveHistogram(string, float64, map[string]string) {}
@@ -0,0 +1,25 @@
+package viesmetrics
+
+import (
+	"expvar"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpvar(t *testing.T) {
+	e := NewExpvar("vies_test")
+	m := Multi(e, NewExpvar("vies_test"))
+
+	m.IncCounter(vies.MetricRequests, map[string]string{"operation": "check"})
+	m.IncCounter(vies.MetricErrors, map[string]string{"code": "MS_UNAVAILABLE"})
+	m.IncCounter(vies.MetricCacheRequests, map[string]string{"result": "hit"})
+	m.ObserveHistogram(vies.MetricRequestDuration, 1, nil)
+
+	vars := expvar.Get("vies_test").(*expvar.Map)
+	assert.Equal(t, "2", vars.Get("requests").String())
+	assert.Equal(t, "2", vars.Get("errors.MS_UNAVAILABLE").String())
+	assert.Equal(t, "2", vars.Get("cache.hit").String())
+	assert.Nil(t, vars.Get("cache.miss"))
+}
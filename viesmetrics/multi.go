@@ -0,0 +1,22 @@
+package viesmetrics
+
+import "github.com/alytsin/go-vies"
+
+// Multi reports every metric to all the given implementations.
+func Multi(metrics ...vies.MetricsInterface) vies.MetricsInterface {
+	return multi(metrics)
+}
+
+type multi []vies.MetricsInterface
+
+func (m multi) IncCounter(name string, labels map[string]string) {
+	for _, metrics := range m {
+		metrics.IncCounter(name, labels)
+	}
+}
+
+func (m multi) ObserveHistogram(name string, value float64, labels map[string]string) {
+	for _, metrics := range m {
+		metrics.ObserveHistogram(name, value, labels)
+	}
+}
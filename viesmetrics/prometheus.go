@@ -0,0 +1,96 @@
+// Package viesmetrics implements vies.MetricsInterface for Prometheus,
+// statsd, Datadog and expvar, and exposes the availability seen by a
+// vies.Watcher to Prometheus.
+package viesmetrics
+
+import (
+	"github.com/alytsin/go-vies"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Prometheus is a prometheus.Collector exposing the client metrics in the
+// "vies" namespace. Metrics with unknown names are dropped.
+type Prometheus struct {
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+	labels     map[string][]string
+}
+
+func NewPrometheus() *Prometheus {
+	p := &Prometheus{
+		counters:   make(map[string]*prometheus.CounterVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+		labels:     make(map[string][]string),
+	}
+
+	p.counter(vies.MetricRequests, "Requests sent to VIES by operation, country code, outcome and HTTP status.",
+		"operation", "country", "outcome", "status")
+	p.histogram(vies.MetricRequestDuration, "Latency of requests sent to VIES by operation, country code and outcome.",
+		[]float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		"operation", "country", "outcome")
+	p.counter(vies.MetricCacheRequests, "Cache lookups of check results by result (hit or miss).",
+		"result")
+	p.counter(vies.MetricErrors, "Failed requests by operation and error code.",
+		"operation", "code")
+	p.counter(vies.MetricRetries, "Retried requests by operation and country code.",
+		"operation", "country")
+
+	return p
+}
+
+func (p *Prometheus) counter(name, help string, labels ...string) {
+	p.labels[name] = labels
+	p.counters[name] = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "vies",
+		Name:      name,
+		Help:      help,
+	}, labels)
+}
+
+func (p *Prometheus) histogram(name, help string, buckets []float64, labels ...string) {
+	p.labels[name] = labels
+	p.histograms[name] = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "vies",
+		Name:      name,
+		Help:      help,
+		Buckets:   buckets,
+	}, labels)
+}
+
+func (p *Prometheus) IncCounter(name string, labels map[string]string) {
+	if c, ok := p.counters[name]; ok {
+		c.WithLabelValues(p.values(name, labels)...).Inc()
+	}
+}
+
+func (p *Prometheus) ObserveHistogram(name string, value float64, labels map[string]string) {
+	if h, ok := p.histograms[name]; ok {
+		h.WithLabelValues(p.values(name, labels)...).Observe(value)
+	}
+}
+
+func (p *Prometheus) values(name string, labels map[string]string) []string {
+	values := make([]string, len(p.labels[name]))
+	for i, label := range p.labels[name] {
+		values[i] = labels[label]
+	}
+	return values
+}
+
+func (p *Prometheus) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range p.counters {
+		c.Describe(ch)
+	}
+	for _, h := range p.histograms {
+		h.Describe(ch)
+	}
+}
+
+func (p *Prometheus) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range p.counters {
+		c.Collect(ch)
+	}
+	for _, h := range p.histograms {
+		h.Collect(ch)
+	}
+}
@@ -0,0 +1,29 @@
+package viesmetrics
+
+import (
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheus(t *testing.T) {
+	p := NewPrometheus()
+	registry := prometheus.NewPedanticRegistry()
+	assert.NoError(t, registry.Register(p))
+
+	p.IncCounter(vies.MetricRequests, map[string]string{"operation": "check", "country": "EE", "outcome": "valid", "status": "200"})
+	p.ObserveHistogram(vies.MetricRequestDuration, 0.3, map[string]string{"operation": "check", "country": "EE", "outcome": "valid"})
+	p.IncCounter(vies.MetricCacheRequests, map[string]string{"result": "hit"})
+	p.IncCounter("unknown", nil)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.counters[vies.MetricRequests].WithLabelValues("check", "EE", "valid", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(p.counters[vies.MetricCacheRequests].WithLabelValues("hit")))
+	assert.Equal(t, 1, testutil.CollectAndCount(p, "vies_request_duration_seconds"))
+
+	count, err := testutil.GatherAndCount(registry)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
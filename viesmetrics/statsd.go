@@ -0,0 +1,106 @@
+package viesmetrics
+
+import (
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Statsd sends metrics over UDP using the statsd line protocol. Plain
+// statsd has no tags, so label values are appended to the metric name in
+// the order of the label names; the Datadog flavour sends them as tags.
+type Statsd struct {
+	conn   net.Conn
+	prefix string
+	tags   []string
+	dogs   bool
+}
+
+// NewStatsd sends metrics named prefix.name.labelValues... to a statsd
+// daemon at addr.
+func NewStatsd(addr, prefix string) (*Statsd, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Statsd{conn: conn, prefix: prefix}, nil
+}
+
+// NewDatadog sends metrics to a DogStatsD agent at addr with labels and the
+// given constant tags ("env:prod") as tags.
+func NewDatadog(addr, prefix string, tags ...string) (*Statsd, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Statsd{conn: conn, prefix: prefix, tags: tags, dogs: true}, nil
+}
+
+func (s *Statsd) IncCounter(name string, labels map[string]string) {
+	s.send(name, "1", "c", labels)
+}
+
+func (s *Statsd) ObserveHistogram(name string, value float64, labels map[string]string) {
+	s.send(name, strconv.FormatFloat(value, 'f', -1, 64), "h", labels)
+}
+
+func (s *Statsd) Close() error {
+	return s.conn.Close()
+}
+
+func (s *Statsd) send(name, value, kind string, labels map[string]string) {
+	_, _ = s.conn.Write([]byte(s.line(name, value, kind, labels)))
+}
+
+func (s *Statsd) line(name, value, kind string, labels map[string]string) string {
+
+	keys := make([]string, 0, len(labels))
+	for k, v := range labels {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var b strings.Builder
+	if s.prefix != "" {
+		b.WriteString(s.prefix)
+		b.WriteByte('.')
+	}
+	b.WriteString(name)
+
+	if !s.dogs {
+		for _, k := range keys {
+			b.WriteByte('.')
+			b.WriteString(sanitize(labels[k]))
+		}
+	}
+
+	b.WriteByte(':')
+	b.WriteString(value)
+	b.WriteByte('|')
+	b.WriteString(kind)
+
+	if s.dogs && len(keys)+len(s.tags) > 0 {
+		tags := slices.Clone(s.tags)
+		for _, k := range keys {
+			tags = append(tags, k+":"+sanitize(labels[k]))
+		}
+		b.WriteString("|#")
+		b.WriteString(strings.Join(tags, ","))
+	}
+
+	return b.String()
+}
+
+// sanitize replaces the characters with a meaning in the statsd protocol.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', ',', '.', ' ', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,43 @@
+package viesmetrics
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsdLine(t *testing.T) {
+	labels := map[string]string{"operation": "check", "country": "EE", "outcome": "valid", "status": ""}
+
+	plain := &Statsd{prefix: "vies"}
+	assert.Equal(t, "vies.requests_total.EE.check.valid:1|c", plain.line("requests_total", "1", "c", labels))
+	assert.Equal(t, "requests_total.a_b:1|c", (&Statsd{}).line("requests_total", "1", "c", map[string]string{"x": "a.b"}))
+
+	dog := &Statsd{prefix: "vies", tags: []string{"env:test"}, dogs: true}
+	assert.Equal(t, "vies.request_duration_seconds:0.25|h|#env:test,country:EE,operation:check,outcome:valid",
+		dog.line("request_duration_seconds", "0.25", "h", labels))
+	assert.Equal(t, "vies.cache_requests_total:1|c", (&Statsd{prefix: "vies", dogs: true}).line("cache_requests_total", "1", "c", nil))
+}
+
+func TestStatsdSend(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	s, err := NewDatadog(listener.LocalAddr().String(), "vies")
+	assert.NoError(t, err)
+	defer s.Close()
+
+	s.IncCounter("cache_requests_total", map[string]string{"result": "miss"})
+	s.ObserveHistogram("request_duration_seconds", 1.5, nil)
+
+	buf := make([]byte, 512)
+	n, _, err := listener.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "vies.cache_requests_total:1|c|#result:miss", string(buf[:n]))
+
+	n, _, err = listener.ReadFrom(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "vies.request_duration_seconds:1.5|h", string(buf[:n]))
+}
@@ -0,0 +1,232 @@
+// Package viesmock provides Client, an in-memory vies.ClientInterface for
+// the unit tests of applications checking VAT numbers, without faking HTTP
+// responses.
+package viesmock
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/alytsin/go-vies/viescountries"
+)
+
+// Call is a call made to a Client.
+type Call struct {
+	// Method is "Check", "CheckNumber", "Valid" or "Status".
+	Method string
+	// Vat is the VAT number as given, with the country code for
+	// CheckNumber, empty for Status.
+	Vat     string
+	Options []vies.CheckOption
+	Time    time.Time
+}
+
+// Client answers checks with the results and errors programmed for each
+// VAT number. Numbers without any are invalid, unless SetDefault says
+// otherwise. It is safe for concurrent use.
+type Client struct {
+	mu        sync.Mutex
+	results   map[string]*vies.CheckResult
+	errs      map[string]error
+	failures  []error
+	fallback  func(ctx context.Context, vat string) (*vies.CheckResult, error)
+	status    *vies.Status
+	statusErr error
+	calls     []Call
+}
+
+var _ vies.ClientInterface = (*Client)(nil)
+
+// New returns a Client with no VAT number programmed, and a status where
+// VIES and every member state are available.
+func New() *Client {
+	return &Client{
+		results: make(map[string]*vies.CheckResult),
+		errs:    make(map[string]error),
+	}
+}
+
+// SetResult makes checks of vat return a copy of result, with its
+// CountryCode, VatNumber and Vat set from vat when empty.
+func (m *Client) SetResult(vat string, result vies.CheckResult) *Client {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := normalize(vat)
+	if result.CountryCode == "" && len(key) >= 2 {
+		result.CountryCode = vies.CountryCode(key[:2])
+	}
+	if result.VatNumber == "" && len(key) >= 2 {
+		result.VatNumber = key[2:]
+	}
+	if result.Vat == "" {
+		result.Vat = string(result.CountryCode) + result.VatNumber
+	}
+	m.results[key] = &result
+	delete(m.errs, key)
+	return m
+}
+
+// SetValid makes vat valid, registered to name at address.
+func (m *Client) SetValid(vat, name, address string) *Client {
+	return m.SetResult(vat, vies.CheckResult{Valid: true, Name: name, Address: address})
+}
+
+// SetInvalid makes vat invalid.
+func (m *Client) SetInvalid(vat string) *Client {
+	return m.SetResult(vat, vies.CheckResult{})
+}
+
+// SetError makes checks of vat fail with err, such as a *vies.ApiError
+// with the MS_UNAVAILABLE code.
+func (m *Client) SetError(vat string, err error) *Client {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := normalize(vat)
+	m.errs[key] = err
+	delete(m.results, key)
+	return m
+}
+
+// FailNext makes the next len(errs) checks fail with errs in turn,
+// whatever the VAT number, to inject transient errors.
+func (m *Client) FailNext(errs ...error) *Client {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures = append(m.failures, errs...)
+	return m
+}
+
+// SetDefault answers the checks of VAT numbers neither SetResult nor
+// SetError programmed.
+func (m *Client) SetDefault(fallback func(ctx context.Context, vat string) (*vies.CheckResult, error)) *Client {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fallback = fallback
+	return m
+}
+
+// SetStatus makes Status return status and err.
+func (m *Client) SetStatus(status *vies.Status, err error) *Client {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.status = status
+	m.statusErr = err
+	return m
+}
+
+// Calls returns the calls made so far, in order.
+func (m *Client) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// CallCount returns how many times vat was checked.
+func (m *Client) CallCount(vat string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := normalize(vat)
+	n := 0
+	for _, call := range m.calls {
+		if call.Vat != "" && normalize(call.Vat) == key {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset forgets the calls made so far, the programmed answers are kept.
+func (m *Client) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *Client) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+	return m.check(ctx, "Check", vat, opts)
+}
+
+func (m *Client) CheckNumber(ctx context.Context, countryCode, vatNumber string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+	return m.check(ctx, "CheckNumber", countryCode+vatNumber, opts)
+}
+
+func (m *Client) Valid(ctx context.Context, vat string, opts ...vies.CheckOption) (bool, error) {
+	result, err := m.check(ctx, "Valid", vat, opts)
+	if err != nil {
+		return false, err
+	}
+	return result.Valid, nil
+}
+
+func (m *Client) Status(ctx context.Context) (*vies.Status, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: "Status", Time: time.Now()})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if m.statusErr != nil {
+		return nil, m.statusErr
+	}
+	if m.status != nil {
+		status := *m.status
+		return &status, nil
+	}
+
+	status := &vies.Status{Vow: vies.StatusVow{Available: true}}
+	for _, code := range viescountries.Codes() {
+		status.Countries = append(status.Countries, vies.CountryStatus{
+			CountryCode:  vies.CountryCode(code),
+			Availability: vies.AvailabilityAvailable,
+		})
+	}
+	return status, nil
+}
+
+func (m *Client) check(ctx context.Context, method, vat string, opts []vies.CheckOption) (*vies.CheckResult, error) {
+
+	m.mu.Lock()
+	m.calls = append(m.calls, Call{Method: method, Vat: vat, Options: opts, Time: time.Now()})
+	key := normalize(vat)
+	result, programmed := m.results[key]
+	err, failing := m.errs[key]
+	fallback := m.fallback
+	var failure error
+	if len(m.failures) > 0 {
+		failure, m.failures = m.failures[0], m.failures[1:]
+	}
+	m.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if failure != nil {
+		return nil, failure
+	}
+	if len(key) < 3 {
+		return nil, &vies.ErrInvalidInput{Input: vat, Reason: vies.ReasonTooShort}
+	}
+
+	switch {
+	case failing:
+		return nil, err
+	case programmed:
+		copied := *result
+		return &copied, nil
+	case fallback != nil:
+		return fallback(ctx, vat)
+	}
+	return &vies.CheckResult{CountryCode: vies.CountryCode(key[:2]), VatNumber: key[2:], Vat: key}, nil
+}
+
+// normalize returns vat in upper case without spaces, dots or dashes.
+func normalize(vat string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", ".", "", "-", "").Replace(vies.CleanVat(vat)))
+}
@@ -0,0 +1,102 @@
+package viesmock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+
+	ctx := context.Background()
+	down := &vies.ApiError{Err: "MS_UNAVAILABLE", Message: "member state unavailable"}
+	m := New().
+		SetValid("DE123456789", "ACME GmbH", "Berlin").
+		SetInvalid("FR00000000000").
+		SetError("IT12345678901", down)
+
+	result, err := m.Check(ctx, "de 123 456 789")
+	assert.NoError(t, err)
+	assert.Equal(t, &vies.CheckResult{CountryCode: "DE", VatNumber: "123456789", Vat: "DE123456789", Valid: true, Name: "ACME GmbH", Address: "Berlin"}, result)
+
+	// results are copies
+	result.Name = "changed"
+	result, err = m.CheckNumber(ctx, "DE", "123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, "ACME GmbH", result.Name)
+
+	valid, err := m.Valid(ctx, "FR00000000000")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = m.Check(ctx, "IT12345678901")
+	assert.Equal(t, down, err)
+
+	// numbers not programmed are invalid
+	result, err = m.Check(ctx, "EE100354546")
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, vies.CountryCode("EE"), result.CountryCode)
+
+	_, err = m.Check(ctx, "1")
+	assert.ErrorIs(t, err, vies.ErrInvalidVat)
+
+	assert.Equal(t, 2, m.CallCount("DE123456789"))
+	calls := m.Calls()
+	assert.Len(t, calls, 6)
+	assert.Equal(t, "CheckNumber", calls[1].Method)
+	assert.Equal(t, "DE123456789", calls[1].Vat)
+
+	m.Reset()
+	assert.Empty(t, m.Calls())
+}
+
+func TestClientFailures(t *testing.T) {
+
+	ctx := context.Background()
+	timeout := errors.New("timeout")
+	m := New().SetValid("DE123456789", "ACME", "").FailNext(timeout, timeout)
+
+	for range 2 {
+		_, err := m.Check(ctx, "DE123456789")
+		assert.Equal(t, timeout, err)
+	}
+	valid, err := m.Valid(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	m.SetDefault(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+		return &vies.CheckResult{Valid: true, Vat: vat}, nil
+	})
+	valid, err = m.Valid(ctx, "NL123456789B01")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = m.Check(canceled, "DE123456789")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClientStatus(t *testing.T) {
+
+	ctx := context.Background()
+	m := New()
+
+	status, err := m.Status(ctx)
+	assert.NoError(t, err)
+	assert.True(t, status.Vow.Available)
+	assert.Len(t, status.Countries, 28)
+
+	m.SetStatus(&vies.Status{Countries: []vies.CountryStatus{{CountryCode: "DE", Availability: vies.AvailabilityUnavailable}}}, nil)
+	status, err = m.Status(ctx)
+	assert.NoError(t, err)
+	assert.False(t, status.Vow.Available)
+
+	m.SetStatus(nil, errors.New("down"))
+	_, err = m.Status(ctx)
+	assert.EqualError(t, err, "down")
+}
@@ -0,0 +1,120 @@
+package viesmq
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	defaultNATSBatch   = 10
+	defaultNATSMaxWait = 5 * time.Second
+)
+
+// NATSConsumerInterface is the part of jetstream.Consumer used by NATS.
+type NATSConsumerInterface interface {
+	Fetch(batch int, opts ...jetstream.FetchOpt) (jetstream.MessageBatch, error)
+}
+
+// NATSPublisherInterface is the part of jetstream.JetStream used by NATS.
+type NATSPublisherInterface interface {
+	Publish(ctx context.Context, subject string, payload []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+}
+
+type NATSConfig struct {
+	// Subject the responses are published to.
+	Subject string
+	// Batch is the number of messages fetched at once, defaults to 10.
+	Batch int
+	// MaxWait of a fetch, defaults to 5 seconds.
+	MaxWait time.Duration
+}
+
+// NATS receives jobs from a JetStream pull consumer. Retried jobs are
+// negatively acknowledged with the delay.
+type NATS struct {
+	consumer  NATSConsumerInterface
+	publisher NATSPublisherInterface
+	subject   string
+	batch     int
+	maxWait   time.Duration
+}
+
+type natsJob struct {
+	msg     jetstream.Msg
+	request Request
+	attempt int
+}
+
+func NewNATS(consumer NATSConsumerInterface, publisher NATSPublisherInterface, config *NATSConfig) *NATS {
+
+	q := &NATS{
+		consumer:  consumer,
+		publisher: publisher,
+		batch:     defaultNATSBatch,
+		maxWait:   defaultNATSMaxWait,
+	}
+
+	if config != nil {
+		q.subject = config.Subject
+		if config.Batch > 0 {
+			q.batch = config.Batch
+		}
+		if config.MaxWait > 0 {
+			q.maxWait = config.MaxWait
+		}
+	}
+
+	return q
+}
+
+func (q *NATS) Receive(ctx context.Context) ([]Job, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, q.maxWait)
+	defer cancel()
+
+	batch, err := q.consumer.Fetch(q.batch, jetstream.FetchContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []Job
+	for msg := range batch.Messages() {
+		attempt := 1
+		if meta, err := msg.Metadata(); err == nil && meta.NumDelivered > 0 {
+			attempt = int(meta.NumDelivered)
+		}
+		jobs = append(jobs, &natsJob{msg: msg, request: decode(msg.Data()), attempt: attempt})
+	}
+	if err := batch.Error(); err != nil && ctx.Err() == nil {
+		return jobs, err
+	}
+	return jobs, nil
+}
+
+func (q *NATS) Publish(ctx context.Context, response Response) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	_, err = q.publisher.Publish(ctx, q.subject, body)
+	return err
+}
+
+func (j *natsJob) Request() Request {
+	return j.request
+}
+
+func (j *natsJob) Attempt() int {
+	return j.attempt
+}
+
+func (j *natsJob) Ack(ctx context.Context) error {
+	return j.msg.Ack()
+}
+
+func (j *natsJob) Retry(ctx context.Context, delay time.Duration) error {
+	return j.msg.NakWithDelay(delay)
+}
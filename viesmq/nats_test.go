@@ -0,0 +1,95 @@
+package viesmq
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMsg struct {
+	jetstream.Msg
+	data      []byte
+	delivered uint64
+	acked     bool
+	nakDelay  time.Duration
+}
+
+func (m *fakeMsg) Data() []byte { return m.data }
+
+func (m *fakeMsg) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{NumDelivered: m.delivered}, nil
+}
+
+func (m *fakeMsg) Ack() error {
+	m.acked = true
+	return nil
+}
+
+func (m *fakeMsg) NakWithDelay(delay time.Duration) error {
+	m.nakDelay = delay
+	return nil
+}
+
+type fakeBatch struct {
+	msgs chan jetstream.Msg
+}
+
+func (b *fakeBatch) Messages() <-chan jetstream.Msg { return b.msgs }
+func (b *fakeBatch) Error() error                   { return nil }
+
+type fakeConsumer struct {
+	batch int
+	msgs  []jetstream.Msg
+}
+
+func (c *fakeConsumer) Fetch(batch int, opts ...jetstream.FetchOpt) (jetstream.MessageBatch, error) {
+	c.batch = batch
+	msgs := make(chan jetstream.Msg, len(c.msgs))
+	for _, msg := range c.msgs {
+		msgs <- msg
+	}
+	close(msgs)
+	return &fakeBatch{msgs: msgs}, nil
+}
+
+type publisherFunc func(ctx context.Context, subject string, payload []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error)
+
+func (f publisherFunc) Publish(ctx context.Context, subject string, payload []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+	return f(ctx, subject, payload, opts...)
+}
+
+func TestNATS(t *testing.T) {
+
+	first := &fakeMsg{data: []byte(`{"id":"1","vat":"EE100354546"}`), delivered: 3}
+	second := &fakeMsg{data: []byte(`DE123456789`)}
+	consumer := &fakeConsumer{msgs: []jetstream.Msg{first, second}}
+
+	var subject, payload string
+	publisher := publisherFunc(func(ctx context.Context, s string, p []byte, opts ...jetstream.PublishOpt) (*jetstream.PubAck, error) {
+		subject, payload = s, string(p)
+		return &jetstream.PubAck{}, nil
+	})
+
+	queue := NewNATS(consumer, publisher, &NATSConfig{Subject: "vat.results", Batch: 5})
+	ctx := context.Background()
+
+	jobs, err := queue.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, consumer.batch)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, Request{ID: "1", Vat: "EE100354546"}, jobs[0].Request())
+	assert.Equal(t, 3, jobs[0].Attempt())
+	assert.Equal(t, 1, jobs[1].Attempt())
+
+	assert.NoError(t, jobs[0].Ack(ctx))
+	assert.True(t, first.acked)
+	assert.NoError(t, jobs[1].Retry(ctx, time.Minute))
+	assert.Equal(t, time.Minute, second.nakDelay)
+
+	assert.NoError(t, queue.Publish(ctx, Response{Vat: "DE123456789", Error: "down"}))
+	assert.Equal(t, "vat.results", subject)
+	assert.Equal(t, `{"vat":"DE123456789","error":"down"}`, payload)
+}
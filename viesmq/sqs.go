@@ -0,0 +1,142 @@
+package viesmq
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+const (
+	defaultSQSMaxMessages = 10
+	defaultSQSWaitTime    = 20 * time.Second
+	// sqsMaxVisibility is the longest visibility timeout SQS accepts.
+	sqsMaxVisibility = 12 * time.Hour
+)
+
+// SQSClientInterface is the part of *sqs.Client used by SQS.
+type SQSClientInterface interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+}
+
+type SQSConfig struct {
+	// QueueUrl is the queue the requests are received from.
+	QueueUrl string
+	// ResultQueueUrl is the queue the responses are sent to.
+	ResultQueueUrl string
+	// MaxMessages received at once, defaults to 10.
+	MaxMessages int32
+	// WaitTime of a long poll, defaults to 20 seconds.
+	WaitTime time.Duration
+}
+
+// SQS receives jobs from an Amazon SQS queue. Retried jobs are hidden for
+// the delay by changing their visibility timeout.
+type SQS struct {
+	client         SQSClientInterface
+	queueUrl       string
+	resultQueueUrl string
+	maxMessages    int32
+	waitTime       time.Duration
+}
+
+type sqsJob struct {
+	queue   *SQS
+	request Request
+	attempt int
+	receipt *string
+}
+
+func NewSQS(client SQSClientInterface, config *SQSConfig) *SQS {
+
+	q := &SQS{
+		client:      client,
+		maxMessages: defaultSQSMaxMessages,
+		waitTime:    defaultSQSWaitTime,
+	}
+
+	if config != nil {
+		q.queueUrl = config.QueueUrl
+		q.resultQueueUrl = config.ResultQueueUrl
+		if config.MaxMessages > 0 {
+			q.maxMessages = config.MaxMessages
+		}
+		if config.WaitTime > 0 {
+			q.waitTime = config.WaitTime
+		}
+	}
+
+	return q
+}
+
+func (q *SQS) Receive(ctx context.Context) ([]Job, error) {
+
+	out, err := q.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(q.queueUrl),
+		MaxNumberOfMessages:         q.maxMessages,
+		WaitTimeSeconds:             int32(q.waitTime / time.Second),
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameApproximateReceiveCount},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, len(out.Messages))
+	for i, msg := range out.Messages {
+		attempt, err := strconv.Atoi(msg.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)])
+		if err != nil || attempt < 1 {
+			attempt = 1
+		}
+		jobs[i] = &sqsJob{
+			queue:   q,
+			request: decode([]byte(aws.ToString(msg.Body))),
+			attempt: attempt,
+			receipt: msg.ReceiptHandle,
+		}
+	}
+	return jobs, nil
+}
+
+func (q *SQS) Publish(ctx context.Context, response Response) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:    aws.String(q.resultQueueUrl),
+		MessageBody: aws.String(string(body)),
+	})
+	return err
+}
+
+func (j *sqsJob) Request() Request {
+	return j.request
+}
+
+func (j *sqsJob) Attempt() int {
+	return j.attempt
+}
+
+func (j *sqsJob) Ack(ctx context.Context) error {
+	_, err := j.queue.client.DeleteMessage(ctx, &sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(j.queue.queueUrl),
+		ReceiptHandle: j.receipt,
+	})
+	return err
+}
+
+func (j *sqsJob) Retry(ctx context.Context, delay time.Duration) error {
+	_, err := j.queue.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(j.queue.queueUrl),
+		ReceiptHandle:     j.receipt,
+		VisibilityTimeout: int32(min(delay, sqsMaxVisibility) / time.Second),
+	})
+	return err
+}
@@ -0,0 +1,73 @@
+package viesmq
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSQS struct {
+	receive    *sqs.ReceiveMessageInput
+	deleted    []string
+	visibility map[string]int32
+	sent       []*sqs.SendMessageInput
+}
+
+func (f *fakeSQS) ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error) {
+	f.receive = params
+	return &sqs.ReceiveMessageOutput{Messages: []types.Message{
+		{Body: aws.String(`{"id":"1","vat":"EE100354546"}`), ReceiptHandle: aws.String("r1"), Attributes: map[string]string{"ApproximateReceiveCount": "2"}},
+		{Body: aws.String(`DE123456789`), ReceiptHandle: aws.String("r2")},
+	}}, nil
+}
+
+func (f *fakeSQS) DeleteMessage(ctx context.Context, params *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.deleted = append(f.deleted, aws.ToString(params.ReceiptHandle))
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.visibility[aws.ToString(params.ReceiptHandle)] = params.VisibilityTimeout
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) SendMessage(ctx context.Context, params *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.sent = append(f.sent, params)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func TestSQS(t *testing.T) {
+
+	client := &fakeSQS{visibility: map[string]int32{}}
+	queue := NewSQS(client, &SQSConfig{QueueUrl: "requests", ResultQueueUrl: "results"})
+	ctx := context.Background()
+
+	jobs, err := queue.Receive(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, "requests", aws.ToString(client.receive.QueueUrl))
+	assert.Equal(t, int32(10), client.receive.MaxNumberOfMessages)
+	assert.Equal(t, int32(20), client.receive.WaitTimeSeconds)
+
+	assert.Equal(t, Request{ID: "1", Vat: "EE100354546"}, jobs[0].Request())
+	assert.Equal(t, 2, jobs[0].Attempt())
+	assert.Equal(t, Request{Vat: "DE123456789"}, jobs[1].Request())
+	assert.Equal(t, 1, jobs[1].Attempt())
+
+	assert.NoError(t, jobs[0].Ack(ctx))
+	assert.Equal(t, []string{"r1"}, client.deleted)
+
+	assert.NoError(t, jobs[1].Retry(ctx, 2*time.Minute))
+	assert.Equal(t, int32(120), client.visibility["r2"])
+	assert.NoError(t, jobs[1].Retry(ctx, 24*time.Hour))
+	assert.Equal(t, int32(43200), client.visibility["r2"])
+
+	assert.NoError(t, queue.Publish(ctx, Response{ID: "1", Vat: "EE100354546", Error: "down"}))
+	assert.Equal(t, "results", aws.ToString(client.sent[0].QueueUrl))
+	assert.Equal(t, `{"id":"1","vat":"EE100354546","error":"down"}`, aws.ToString(client.sent[0].MessageBody))
+}
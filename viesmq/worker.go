@@ -0,0 +1,193 @@
+// Package viesmq runs VAT number checks from a message queue. A Worker
+// receives jobs from a QueueInterface, checks them, publishes the results
+// and retries the checks which failed for a temporary reason, such as a
+// member state being down. SQS and NATS JetStream adapters are provided:
+//
+//	queue := viesmq.NewSQS(sqs.NewFromConfig(cfg), &viesmq.SQSConfig{
+//		QueueUrl:       requestsUrl,
+//		ResultQueueUrl: resultsUrl,
+//	})
+//	err := viesmq.NewWorker(client, &viesmq.Config{Queue: queue}).Run(ctx)
+package viesmq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alytsin/go-vies"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultBackoff     = time.Minute
+	defaultParallel    = 1
+)
+
+// Request is the body of a job. A body which is not JSON is taken as the
+// VAT number itself.
+type Request struct {
+	ID  string `json:"id,omitempty"`
+	Vat string `json:"vat"`
+}
+
+// Response is the published result of a job.
+type Response struct {
+	ID     string            `json:"id,omitempty"`
+	Vat    string            `json:"vat"`
+	Result *vies.CheckResult `json:"result,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// Job is a received request.
+type Job interface {
+	Request() Request
+	// Attempt is the number of times the job was delivered, starting at 1.
+	Attempt() int
+	// Ack removes the job from the queue.
+	Ack(ctx context.Context) error
+	// Retry makes the job available again after delay.
+	Retry(ctx context.Context, delay time.Duration) error
+}
+
+// QueueInterface is a source of jobs and a destination of their results.
+type QueueInterface interface {
+	// Receive blocks until jobs are available or the context is done, it
+	// may return no jobs when waiting timed out.
+	Receive(ctx context.Context) ([]Job, error)
+	Publish(ctx context.Context, response Response) error
+}
+
+type Config struct {
+	Queue QueueInterface
+	// MaxAttempts is the number of deliveries after which a temporary
+	// failure is published as the result, defaults to 5.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for each
+	// following one, defaults to a minute.
+	Backoff time.Duration
+	// Parallel is the number of jobs checked concurrently, defaults to 1.
+	Parallel int
+	// OnError receives the errors of acknowledging, retrying and
+	// publishing, after which the job is delivered again.
+	OnError func(error)
+}
+
+type Worker struct {
+	registry    vies.RegistryInterface
+	queue       QueueInterface
+	maxAttempts int
+	backoff     time.Duration
+	parallel    int
+	onError     func(error)
+}
+
+func NewWorker(registry vies.RegistryInterface, config *Config) *Worker {
+
+	w := &Worker{
+		registry:    registry,
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+		parallel:    defaultParallel,
+		onError:     func(error) {},
+	}
+
+	if config != nil {
+		w.queue = config.Queue
+		if config.MaxAttempts > 0 {
+			w.maxAttempts = config.MaxAttempts
+		}
+		if config.Backoff > 0 {
+			w.backoff = config.Backoff
+		}
+		if config.Parallel > 0 {
+			w.parallel = config.Parallel
+		}
+		if config.OnError != nil {
+			w.onError = config.OnError
+		}
+	}
+
+	return w
+}
+
+// Run processes jobs until the context is done or receiving fails.
+func (w *Worker) Run(ctx context.Context) error {
+
+	sem := make(chan struct{}, w.parallel)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		jobs, err := w.queue.Receive(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, job := range jobs {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return nil
+			}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				w.process(ctx, job)
+			}()
+		}
+	}
+}
+
+func (w *Worker) process(ctx context.Context, job Job) {
+
+	req := job.Request()
+	result, err := w.registry.Check(ctx, req.Vat)
+
+	if err != nil && temporary(err) && job.Attempt() < w.maxAttempts {
+		delay := w.backoff << (job.Attempt() - 1)
+		if err := job.Retry(ctx, delay); err != nil {
+			w.onError(err)
+		}
+		return
+	}
+
+	rsp := Response{ID: req.ID, Vat: req.Vat, Result: result}
+	if err != nil {
+		rsp.Error = err.Error()
+	}
+	if err := w.queue.Publish(ctx, rsp); err != nil {
+		w.onError(err)
+		return
+	}
+	if err := job.Ack(ctx); err != nil {
+		w.onError(err)
+	}
+}
+
+// temporary reports whether a check may succeed when retried, malformed
+// numbers and member states the client doesn't allow never do.
+func temporary(err error) bool {
+	var apiErr *vies.ApiError
+	if errors.Is(err, vies.ErrInvalidVat) || errors.Is(err, vies.ErrCountryNotAllowed) || (errors.As(err, &apiErr) && apiErr.Err == "INVALID_INPUT") {
+		return false
+	}
+	return !errors.Is(err, context.Canceled)
+}
+
+func decode(body []byte) Request {
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		return Request{Vat: strings.TrimSpace(string(body))}
+	}
+	return req
+}
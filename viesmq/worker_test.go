@@ -0,0 +1,124 @@
+package viesmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+type registryFunc func(ctx context.Context, vat string) (*vies.CheckResult, error)
+
+func (f registryFunc) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+	return f(ctx, vat)
+}
+
+type fakeJob struct {
+	request Request
+	attempt int
+	acked   bool
+	retried time.Duration
+}
+
+func (j *fakeJob) Request() Request { return j.request }
+func (j *fakeJob) Attempt() int     { return j.attempt }
+
+func (j *fakeJob) Ack(ctx context.Context) error {
+	j.acked = true
+	return nil
+}
+
+func (j *fakeJob) Retry(ctx context.Context, delay time.Duration) error {
+	j.retried = delay
+	return nil
+}
+
+type fakeQueue struct {
+	jobs      []Job
+	cancel    context.CancelFunc
+	mu        sync.Mutex
+	published []Response
+}
+
+func (q *fakeQueue) Receive(ctx context.Context) ([]Job, error) {
+	jobs := q.jobs
+	if jobs == nil {
+		q.cancel()
+		<-ctx.Done()
+	}
+	q.jobs = nil
+	return jobs, nil
+}
+
+func (q *fakeQueue) Publish(ctx context.Context, response Response) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.published = append(q.published, response)
+	return nil
+}
+
+func TestWorker(t *testing.T) {
+
+	registry := registryFunc(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+		switch vat {
+		case "EE100354546":
+			return &vies.CheckResult{Vat: vat, Valid: true}, nil
+		case "E":
+			return nil, vies.ErrInvalidVat
+		case "FR40303265045":
+			return nil, fmt.Errorf("%w %s", vies.ErrCountryNotAllowed, "FR")
+		}
+		return nil, &vies.ApiError{Err: "MS_UNAVAILABLE", Message: "down"}
+	})
+
+	valid := &fakeJob{request: Request{ID: "1", Vat: "EE100354546"}, attempt: 1}
+	malformed := &fakeJob{request: Request{ID: "2", Vat: "E"}, attempt: 1}
+	unavailable := &fakeJob{request: Request{ID: "3", Vat: "DE123456789"}, attempt: 3}
+	exhausted := &fakeJob{request: Request{ID: "4", Vat: "DE123456789"}, attempt: 5}
+	notAllowed := &fakeJob{request: Request{ID: "5", Vat: "FR40303265045"}, attempt: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queue := &fakeQueue{jobs: []Job{valid, malformed, unavailable, exhausted, notAllowed}, cancel: cancel}
+
+	worker := NewWorker(registry, &Config{Queue: queue, Backoff: time.Second, Parallel: 2})
+	assert.NoError(t, worker.Run(ctx))
+
+	assert.True(t, valid.acked)
+	assert.True(t, malformed.acked)
+	assert.False(t, unavailable.acked)
+	assert.Equal(t, 4*time.Second, unavailable.retried)
+	assert.True(t, exhausted.acked)
+	assert.True(t, notAllowed.acked)
+
+	assert.ElementsMatch(t, []Response{
+		{ID: "1", Vat: "EE100354546", Result: &vies.CheckResult{Vat: "EE100354546", Valid: true}},
+		{ID: "2", Vat: "E", Error: "invalid VAT provided"},
+		{ID: "4", Vat: "DE123456789", Error: "MS_UNAVAILABLE: down"},
+		{ID: "5", Vat: "FR40303265045", Error: "member state not allowed FR"},
+	}, queue.published)
+}
+
+type failingQueue struct{}
+
+func (failingQueue) Receive(ctx context.Context) ([]Job, error) {
+	return nil, errors.New("queue down")
+}
+
+func (failingQueue) Publish(ctx context.Context, response Response) error {
+	return nil
+}
+
+func TestWorkerReceiveError(t *testing.T) {
+	worker := NewWorker(nil, &Config{Queue: failingQueue{}})
+	assert.EqualError(t, worker.Run(context.Background()), "queue down")
+}
+
+func TestDecode(t *testing.T) {
+	assert.Equal(t, Request{ID: "a", Vat: "EE100354546"}, decode([]byte(`{"id":"a","vat":"EE100354546"}`)))
+	assert.Equal(t, Request{Vat: "EE100354546"}, decode([]byte(" EE100354546\n")))
+}
@@ -0,0 +1,64 @@
+// Package viesnotify sends the availability changes seen by a vies.Watcher
+// to chat and alerting systems.
+package viesnotify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/alytsin/go-vies"
+)
+
+// Notifier delivers a single availability change.
+type Notifier interface {
+	Notify(ctx context.Context, change vies.CountryChange) error
+}
+
+type Config struct {
+	// Countries limits the notifications to these member states, use
+	// vies.VowCountryCode for VIES itself. Empty means all of them.
+	Countries []string
+	// OnError, when set, receives the errors of failed notifications.
+	OnError func(error)
+}
+
+// Attach notifies every change reported by the watcher.
+func Attach(watcher *vies.Watcher, notifier Notifier, config *Config) {
+
+	var countries []string
+	onError := func(error) {}
+
+	if config != nil {
+		for _, country := range config.Countries {
+			countries = append(countries, strings.ToUpper(country))
+		}
+		if config.OnError != nil {
+			onError = config.OnError
+		}
+	}
+
+	watcher.OnCountryChange(func(change vies.CountryChange) {
+		if len(countries) > 0 && !slices.Contains(countries, strings.ToUpper(change.CountryCode)) {
+			return
+		}
+		if err := notifier.Notify(context.Background(), change); err != nil {
+			onError(err)
+		}
+	})
+}
+
+// Message describes the change in a single line, e.g.
+// "VIES member state DE is Unavailable (was Available)".
+func Message(change vies.CountryChange) string {
+
+	subject := "VIES member state " + change.CountryCode
+	if change.CountryCode == vies.VowCountryCode {
+		subject = "VIES"
+	}
+	if change.Previous == "" {
+		return fmt.Sprintf("%s is %s", subject, change.Current)
+	}
+	return fmt.Sprintf("%s is %s (was %s)", subject, change.Current, change.Previous)
+}
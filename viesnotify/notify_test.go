@@ -0,0 +1,70 @@
+package viesnotify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+type notifierFunc func(ctx context.Context, change vies.CountryChange) error
+
+func (f notifierFunc) Notify(ctx context.Context, change vies.CountryChange) error {
+	return f(ctx, change)
+}
+
+func TestAttach(t *testing.T) {
+	var polls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if polls.Add(1) == 1 {
+			_, _ = w.Write([]byte(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"},{"countryCode":"FR","availability":"Available"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"vow":{"available":false},"countries":[{"countryCode":"DE","availability":"Unavailable"},{"countryCode":"FR","availability":"Unavailable"}]}`))
+	}))
+	defer upstream.Close()
+
+	client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: upstream.URL})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watcher := vies.NewWatcher(client, &vies.WatcherConfig{Interval: time.Millisecond})
+
+	var messages []string
+	var errs []error
+	Attach(watcher, notifierFunc(func(ctx context.Context, change vies.CountryChange) error {
+		messages = append(messages, Message(change))
+		return errors.New("delivery failed")
+	}), &Config{
+		Countries: []string{"vow", "DE"},
+		OnError: func(err error) {
+			errs = append(errs, err)
+		},
+	})
+	watcher.OnChange(func(previous, current *vies.Status) {
+		cancel()
+	})
+
+	assert.ErrorIs(t, watcher.Run(ctx), context.Canceled)
+	assert.Equal(t, []string{
+		"VIES is Unavailable (was Available)",
+		"VIES member state DE is Unavailable (was Available)",
+	}, messages)
+	assert.Len(t, errs, 2)
+}
+
+func TestMessage(t *testing.T) {
+	assert.Equal(t, "VIES member state XI is Available", Message(vies.CountryChange{
+		CountryCode: "XI",
+		Kind:        vies.BecameAvailable,
+		Current:     vies.AvailabilityAvailable,
+	}))
+}
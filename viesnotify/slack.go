@@ -0,0 +1,39 @@
+package viesnotify
+
+import (
+	"context"
+
+	"github.com/alytsin/go-vies"
+)
+
+// Slack posts every change to a Slack incoming webhook.
+type Slack struct {
+	hook *vies.Webhook
+}
+
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// NewSlack posts to the incoming webhook URL of a Slack channel.
+func NewSlack(webhookURL string) (*Slack, error) {
+	hook, err := vies.NewWebhook(&vies.WebhookConfig{URL: webhookURL})
+	if err != nil {
+		return nil, err
+	}
+	return &Slack{hook: hook}, nil
+}
+
+func (s *Slack) Notify(ctx context.Context, change vies.CountryChange) error {
+	return s.hook.Post(ctx, slackMessage{Text: slackEmoji(change.Kind) + " " + Message(change)})
+}
+
+func slackEmoji(kind vies.ChangeKind) string {
+	switch kind {
+	case vies.BecameAvailable:
+		return ":large_green_circle:"
+	case vies.MonitoringDisabled:
+		return ":white_circle:"
+	}
+	return ":red_circle:"
+}
@@ -0,0 +1,45 @@
+package viesnotify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlack(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	slack, err := NewSlack(server.URL)
+	assert.NoError(t, err)
+
+	assert.NoError(t, slack.Notify(context.Background(), vies.CountryChange{
+		CountryCode: "DE",
+		Kind:        vies.BecameUnavailable,
+		Previous:    vies.AvailabilityAvailable,
+		Current:     vies.AvailabilityUnavailable,
+	}))
+	assert.NoError(t, slack.Notify(context.Background(), vies.CountryChange{
+		CountryCode: "DE",
+		Kind:        vies.BecameAvailable,
+		Previous:    vies.AvailabilityUnavailable,
+		Current:     vies.AvailabilityAvailable,
+	}))
+
+	assert.Equal(t, []string{
+		`{"text":":red_circle: VIES member state DE is Unavailable (was Available)"}`,
+		`{"text":":large_green_circle: VIES member state DE is Available (was Unavailable)"}`,
+	}, bodies)
+
+	_, err = NewSlack("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package viesnotify
+
+import (
+	"context"
+
+	"github.com/alytsin/go-vies"
+)
+
+const eventAvailabilityChanged = "vies.availability_changed"
+
+// Webhook posts every change as signed JSON through a vies.Webhook.
+type Webhook struct {
+	hook *vies.Webhook
+}
+
+type webhookPayload struct {
+	Event   string `json:"event"`
+	Message string `json:"message"`
+	vies.CountryChange
+}
+
+func NewWebhook(hook *vies.Webhook) *Webhook {
+	return &Webhook{hook: hook}
+}
+
+func (w *Webhook) Notify(ctx context.Context, change vies.CountryChange) error {
+	return w.hook.Post(ctx, webhookPayload{
+		Event:         eventAvailabilityChanged,
+		Message:       Message(change),
+		CountryChange: change,
+	})
+}
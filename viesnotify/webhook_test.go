@@ -0,0 +1,39 @@
+package viesnotify
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		assert.Equal(t, "sha256="+vies.WebhookSignature([]byte("secret"), body), r.Header.Get(vies.WebhookSignatureHeader))
+	}))
+	defer server.Close()
+
+	hook, err := vies.NewWebhook(&vies.WebhookConfig{URL: server.URL, Secret: []byte("secret")})
+	assert.NoError(t, err)
+
+	assert.NoError(t, NewWebhook(hook).Notify(context.Background(), vies.CountryChange{
+		CountryCode: vies.VowCountryCode,
+		Kind:        vies.BecameUnavailable,
+		Previous:    vies.AvailabilityAvailable,
+		Current:     vies.AvailabilityUnavailable,
+	}))
+	assert.JSONEq(t, `{
+		"event": "vies.availability_changed",
+		"message": "VIES is Unavailable (was Available)",
+		"countryCode": "VoW",
+		"kind": "became_unavailable",
+		"previous": "Available",
+		"current": "Unavailable"
+	}`, string(body))
+}
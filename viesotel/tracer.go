@@ -0,0 +1,79 @@
+// Package viesotel traces the calls of a vies.Client with OpenTelemetry:
+//
+//	client, err := vies.NewClient(&vies.ClientConfig{
+//		Tracer: viesotel.NewTracer(provider, nil),
+//	})
+package viesotel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/alytsin/go-vies"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/alytsin/go-vies"
+
+// Tracer creates a client span for every Check, CheckEori and Status call
+// of a vies.Client and injects its trace context into the requests.
+type Tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+var _ vies.TracerInterface = (*Tracer)(nil)
+
+// NewTracer returns a tracer creating its spans with provider. The trace
+// context is injected with propagator, the global one when nil.
+func NewTracer(provider trace.TracerProvider, propagator propagation.TextMapPropagator) *Tracer {
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	return &Tracer{tracer: provider.Tracer(tracerName), propagator: propagator}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, attributes map[string]any) (context.Context, vies.SpanInterface) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(toAttributes(attributes)...))
+	return ctx, span{s}
+}
+
+func (t *Tracer) Inject(ctx context.Context, header http.Header) {
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+type span struct {
+	trace.Span
+}
+
+func (s span) End(attributes map[string]any, err error) {
+	s.SetAttributes(toAttributes(attributes)...)
+	if err != nil {
+		s.RecordError(err)
+		s.SetStatus(codes.Error, err.Error())
+	}
+	s.Span.End()
+}
+
+func toAttributes(attributes map[string]any) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attributes))
+	for name, value := range attributes {
+		key := attribute.Key(name)
+		switch v := value.(type) {
+		case string:
+			kvs = append(kvs, key.String(v))
+		case bool:
+			kvs = append(kvs, key.Bool(v))
+		case int:
+			kvs = append(kvs, key.Int(v))
+		default:
+			kvs = append(kvs, key.String(fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,76 @@
+package viesotel
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type roundTripFunc func(req *http.Request) *http.Response
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req), nil
+}
+
+func TestTracer(t *testing.T) {
+	var traceparent []string
+	httpClient := &http.Client{Transport: roundTripFunc(func(req *http.Request) *http.Response {
+		traceparent = append(traceparent, req.Header.Get("traceparent"))
+		if req.URL.Path == "/check-status" {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"errorWrappers":[{"error":"SERVICE_UNAVAILABLE","message":"down"}]}`)),
+				Header:     make(http.Header),
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"countryCode":"EE","vatNumber":"123","valid":true}`)),
+			Header:     make(http.Header),
+		}
+	})}
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	v, err := vies.NewClient(&vies.ClientConfig{
+		HttpClient:  httpClient,
+		EndpointUrl: "https://example.com/",
+		Tracer:      NewTracer(provider, propagation.TraceContext{}),
+	})
+	assert.NoError(t, err)
+
+	_, err = v.Check(context.Background(), "EE123")
+	assert.NoError(t, err)
+	_, err = v.Status(context.Background())
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	check := spans[0]
+	assert.Equal(t, "vies.Check", check.Name())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("vies.country_code", "EE"),
+		attribute.Bool("vies.valid", true),
+		attribute.Int("vies.retry_count", 0),
+		attribute.Int("http.response.status_code", 200),
+	}, check.Attributes())
+	assert.Contains(t, traceparent[0], check.SpanContext().TraceID().String())
+
+	status := spans[1]
+	assert.Equal(t, "vies.Status", status.Name())
+	assert.Equal(t, codes.Error, status.Status().Code)
+	assert.Contains(t, status.Attributes(), attribute.String("vies.error_code", "SERVICE_UNAVAILABLE"))
+	assert.Contains(t, traceparent[1], status.SpanContext().TraceID().String())
+}
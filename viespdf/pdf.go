@@ -0,0 +1,196 @@
+// Package viespdf renders a vies.ConsultationProof as a PDF document, for
+// audit files kept as PDF rather than HTML.
+package viespdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/alytsin/go-vies"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/unicode/norm"
+)
+
+// A4 in points, with its margins.
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 56
+	valueX     = 220
+)
+
+// valueWidth is the number of characters of a value per line, Helvetica
+// being about half as wide as high.
+const valueWidth = 2 * (pageWidth - margin - valueX) / fontSize
+
+const (
+	fontSize    = 10
+	lineHeight  = 14
+	titleSize   = 16
+	sectionSize = 12
+)
+
+// Write writes proof as a PDF document to w. Characters outside
+// Windows-1252 lose their diacritics or are replaced with "?", as the
+// document uses the standard Helvetica font.
+func Write(w io.Writer, proof *vies.ConsultationProof) error {
+
+	doc := &document{}
+	doc.newPage()
+	doc.text("F2", titleSize, margin, proof.Title())
+	doc.y -= lineHeight
+
+	for _, section := range proof.Sections() {
+		doc.space(2 * lineHeight)
+		doc.y -= lineHeight / 2
+		doc.text("F2", sectionSize, margin, section.Title)
+		for _, field := range section.Fields {
+			lines := wrap(field.Value, valueWidth)
+			doc.space(len(lines) * lineHeight)
+			doc.line("F1", margin, field.Label)
+			for i, line := range lines {
+				if i > 0 {
+					doc.y -= lineHeight
+				}
+				doc.line("F1", valueX, line)
+			}
+			doc.y -= lineHeight
+		}
+	}
+
+	_, err := w.Write(doc.bytes())
+	return err
+}
+
+// document lays out text on pages from top to bottom.
+type document struct {
+	pages []*bytes.Buffer
+	y     int
+}
+
+func (doc *document) newPage() {
+	doc.pages = append(doc.pages, &bytes.Buffer{})
+	doc.y = pageHeight - margin
+}
+
+// space starts a new page unless height points are left on this one.
+func (doc *document) space(height int) {
+	if doc.y-height < margin {
+		doc.newPage()
+	}
+}
+
+func (doc *document) text(font string, size, x int, s string) {
+	page := doc.pages[len(doc.pages)-1]
+	fmt.Fprintf(page, "BT /%s %d Tf %d %d Td (%s) Tj ET\n", font, size, x, doc.y, escape(s))
+	doc.y -= size + lineHeight - fontSize
+}
+
+// line writes s at x without moving to the next line.
+func (doc *document) line(font string, x int, s string) {
+	page := doc.pages[len(doc.pages)-1]
+	fmt.Fprintf(page, "BT /%s %d Tf %d %d Td (%s) Tj ET\n", font, fontSize, x, doc.y, escape(s))
+}
+
+// bytes returns the PDF file: the catalog, the page tree, the two fonts,
+// then a page and its content for each page.
+func (doc *document) bytes() []byte {
+
+	var objects []string
+	kids := make([]string, len(doc.pages))
+	for i := range doc.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(doc.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range doc.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, 6+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()),
+		)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+// wrap splits s into lines of at most width characters, at spaces when
+// possible.
+func wrap(s string, width int) []string {
+
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for len([]rune(word)) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, string([]rune(word)[:width]))
+				word = string([]rune(word)[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case len([]rune(line))+1+len([]rune(word)) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// escape encodes s in Windows-1252 as a PDF string literal.
+func escape(s string) string {
+
+	var b strings.Builder
+	for _, r := range s {
+		c, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			c = fallback(r)
+		}
+		switch c {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// fallback returns r without its diacritics, as in "Ș" to "S", or "?".
+func fallback(r rune) byte {
+	for _, c := range norm.NFD.String(string(r)) {
+		if !unicode.Is(unicode.Mn, c) {
+			if b, ok := charmap.Windows1252.EncodeRune(c); ok {
+				return b
+			}
+		}
+	}
+	return '?'
+}
@@ -0,0 +1,51 @@
+package viespdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+
+	result := &vies.CheckResult{
+		CountryCode:       "RO",
+		VatNumber:         "123456",
+		Valid:             true,
+		Name:              "ȘTEFAN (ROMÂNIA) S.R.L.",
+		Address:           "STR. LUNGĂ 1\nBUCUREȘTI",
+		RequestIdentifier: "WAPIAAAAYxyz",
+	}
+	proof := vies.NewConsultationProof(result, "DE123456789")
+
+	var b bytes.Buffer
+	assert.NoError(t, Write(&b, proof))
+	pdf := b.String()
+
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.Contains(t, pdf, "(WAPIAAAAYxyz)")
+	// parentheses are escaped, diacritics outside Windows-1252 dropped
+	assert.Contains(t, pdf, "(STEFAN \\(ROM\xc2NIA\\) S.R.L.)")
+	assert.Contains(t, pdf, "(BUCURESTI)")
+
+	// the cross-reference table points at each object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	offset, _ := strconv.Atoi(startxref[1])
+	assert.True(t, strings.HasPrefix(pdf[offset:], "xref\n"))
+	for i, entry := range regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(pdf, -1) {
+		offset, _ := strconv.Atoi(entry[1])
+		assert.True(t, strings.HasPrefix(pdf[offset:], strconv.Itoa(i+1)+" 0 obj\n"))
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"one two", "three"}, wrap("one two three", 8))
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, wrap("abcdefghij", 4))
+	assert.Equal(t, []string{"a", "b"}, wrap("a\nb", 4))
+}
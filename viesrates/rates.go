@@ -0,0 +1,164 @@
+// Package viesrates holds the VAT rates of the VIES member states with the
+// dates they apply from, for invoicing alongside VAT number validation.
+package viesrates
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/alytsin/go-vies/viescountries"
+)
+
+// ErrNoRate is returned when a country has no rate of the requested
+// category on the given date.
+var ErrNoRate = errors.New("no VAT rate")
+
+// Category is a kind of VAT rate.
+type Category string
+
+const (
+	CategoryStandard Category = "standard"
+	// CategoryReduced is the higher of the reduced rates.
+	CategoryReduced Category = "reduced"
+	// CategorySecondReduced is the lower of two reduced rates.
+	CategorySecondReduced Category = "second_reduced"
+	CategorySuperReduced  Category = "super_reduced"
+	CategoryParking       Category = "parking"
+)
+
+// Rates are the VAT rates of a country in percent, applicable from From
+// until the next change. Reduced is ordered from the highest rate, zero
+// means the country has no rate of that category.
+type Rates struct {
+	From         time.Time `json:"from"`
+	Standard     float64   `json:"standard"`
+	Reduced      []float64 `json:"reduced,omitempty"`
+	SuperReduced float64   `json:"superReduced,omitempty"`
+	Parking      float64   `json:"parking,omitempty"`
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// since is the first date covered by the dataset.
+var since = date(2020, time.January, 1)
+
+// rates are keyed by VIES code, each history ordered by date.
+var rates = map[string][]Rates{
+	"AT": {{From: since, Standard: 20, Reduced: []float64{13, 10}, Parking: 13}},
+	"BE": {{From: since, Standard: 21, Reduced: []float64{12, 6}, Parking: 12}},
+	"BG": {{From: since, Standard: 20, Reduced: []float64{9}}},
+	"CY": {{From: since, Standard: 19, Reduced: []float64{9, 5}}},
+	"CZ": {
+		{From: since, Standard: 21, Reduced: []float64{15, 10}},
+		{From: date(2024, time.January, 1), Standard: 21, Reduced: []float64{12}},
+	},
+	"DE": {
+		{From: since, Standard: 19, Reduced: []float64{7}},
+		{From: date(2020, time.July, 1), Standard: 16, Reduced: []float64{5}},
+		{From: date(2021, time.January, 1), Standard: 19, Reduced: []float64{7}},
+	},
+	"DK": {{From: since, Standard: 25}},
+	"EE": {
+		{From: since, Standard: 20, Reduced: []float64{9}},
+		{From: date(2024, time.January, 1), Standard: 22, Reduced: []float64{9, 5}},
+		{From: date(2025, time.January, 1), Standard: 22, Reduced: []float64{13, 9}},
+		{From: date(2025, time.July, 1), Standard: 24, Reduced: []float64{13, 9}},
+	},
+	"EL": {{From: since, Standard: 24, Reduced: []float64{13, 6}}},
+	"ES": {{From: since, Standard: 21, Reduced: []float64{10}, SuperReduced: 4}},
+	"FI": {
+		{From: since, Standard: 24, Reduced: []float64{14, 10}},
+		{From: date(2024, time.September, 1), Standard: 25.5, Reduced: []float64{14, 10}},
+	},
+	"FR": {{From: since, Standard: 20, Reduced: []float64{10, 5.5}, SuperReduced: 2.1}},
+	"HR": {{From: since, Standard: 25, Reduced: []float64{13, 5}}},
+	"HU": {{From: since, Standard: 27, Reduced: []float64{18, 5}}},
+	"IE": {{From: since, Standard: 23, Reduced: []float64{13.5, 9}, SuperReduced: 4.8, Parking: 13.5}},
+	"IT": {{From: since, Standard: 22, Reduced: []float64{10, 5}, SuperReduced: 4}},
+	"LT": {{From: since, Standard: 21, Reduced: []float64{9, 5}}},
+	"LU": {
+		{From: since, Standard: 17, Reduced: []float64{14, 8}, SuperReduced: 3, Parking: 14},
+		{From: date(2023, time.January, 1), Standard: 16, Reduced: []float64{13, 7}, SuperReduced: 3, Parking: 13},
+		{From: date(2024, time.January, 1), Standard: 17, Reduced: []float64{14, 8}, SuperReduced: 3, Parking: 14},
+	},
+	"LV": {{From: since, Standard: 21, Reduced: []float64{12, 5}}},
+	"MT": {{From: since, Standard: 18, Reduced: []float64{7, 5}}},
+	"NL": {{From: since, Standard: 21, Reduced: []float64{9}}},
+	"PL": {{From: since, Standard: 23, Reduced: []float64{8, 5}}},
+	"PT": {{From: since, Standard: 23, Reduced: []float64{13, 6}, Parking: 13}},
+	"RO": {
+		{From: since, Standard: 19, Reduced: []float64{9, 5}},
+		{From: date(2025, time.August, 1), Standard: 21, Reduced: []float64{11}},
+	},
+	"SE": {{From: since, Standard: 25, Reduced: []float64{12, 6}}},
+	"SI": {{From: since, Standard: 22, Reduced: []float64{9.5, 5}}},
+	"SK": {
+		{From: since, Standard: 20, Reduced: []float64{10, 5}},
+		{From: date(2025, time.January, 1), Standard: 23, Reduced: []float64{19, 5}},
+	},
+	"XI": {{From: since, Standard: 20, Reduced: []float64{5}}},
+}
+
+// RatesFor returns the rates of a member state, given by VIES or ISO code,
+// applicable on date.
+func RatesFor(country string, date time.Time) (Rates, error) {
+
+	code := strings.ToUpper(country)
+	if c, ok := viescountries.ByIsoCode(code); ok && !viescountries.IsMember(code) {
+		code = c.Code
+	}
+
+	history, ok := rates[code]
+	if !ok || date.Before(since) {
+		return Rates{}, fmt.Errorf("%w for %s on %s", ErrNoRate, country, date.Format(time.DateOnly))
+	}
+
+	i, _ := slices.BinarySearchFunc(history, date, func(r Rates, t time.Time) int {
+		return r.From.Compare(t)
+	})
+	if i == len(history) || history[i].From.After(date) {
+		i--
+	}
+	r := history[i]
+	r.Reduced = slices.Clone(r.Reduced)
+	return r, nil
+}
+
+// RateFor returns the rate of a category in percent, for example
+//
+//	rate, err := viesrates.RateFor("DE", time.Now(), viesrates.CategoryStandard) // 19
+func RateFor(country string, date time.Time, category Category) (float64, error) {
+
+	r, err := RatesFor(country, date)
+	if err != nil {
+		return 0, err
+	}
+
+	var rate float64
+	switch category {
+	case CategoryStandard:
+		rate = r.Standard
+	case CategoryReduced:
+		if len(r.Reduced) > 0 {
+			rate = r.Reduced[0]
+		}
+	case CategorySecondReduced:
+		if len(r.Reduced) > 1 {
+			rate = r.Reduced[1]
+		}
+	case CategorySuperReduced:
+		rate = r.SuperReduced
+	case CategoryParking:
+		rate = r.Parking
+	}
+
+	if rate == 0 {
+		return 0, fmt.Errorf("%w %s for %s on %s", ErrNoRate, category, country, date.Format(time.DateOnly))
+	}
+	return rate, nil
+}
@@ -0,0 +1,51 @@
+package viesrates
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies/viescountries"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateFor(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		country  string
+		date     time.Time
+		category Category
+		rate     float64
+		err      error
+	}{
+		{name: "standard", country: "DE", date: date(2024, time.March, 1), category: CategoryStandard, rate: 19},
+		{name: "temporary cut", country: "DE", date: date(2020, time.October, 1), category: CategoryReduced, rate: 5},
+		{name: "change day", country: "DE", date: date(2021, time.January, 1), category: CategoryStandard, rate: 19},
+		{name: "iso code", country: "gr", date: date(2024, time.March, 1), category: CategoryStandard, rate: 24},
+		{name: "second reduced", country: "FR", date: date(2024, time.March, 1), category: CategorySecondReduced, rate: 5.5},
+		{name: "super reduced", country: "IE", date: date(2024, time.March, 1), category: CategorySuperReduced, rate: 4.8},
+		{name: "after change", country: "SK", date: date(2025, time.June, 1), category: CategoryStandard, rate: 23},
+		{name: "missing category", country: "DK", date: date(2024, time.March, 1), category: CategoryReduced, err: ErrNoRate},
+		{name: "before dataset", country: "DE", date: date(2019, time.March, 1), category: CategoryStandard, err: ErrNoRate},
+		{name: "outside vies", country: "CH", date: date(2024, time.March, 1), category: CategoryStandard, err: ErrNoRate},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := RateFor(tt.country, tt.date, tt.category)
+			assert.True(t, errors.Is(err, tt.err))
+			assert.Equal(t, tt.rate, rate)
+		})
+	}
+}
+
+func TestRatesCoverMemberStates(t *testing.T) {
+	for _, code := range viescountries.Codes() {
+		history, ok := rates[code]
+		assert.True(t, ok, code)
+		for i := 1; i < len(history); i++ {
+			assert.True(t, history[i-1].From.Before(history[i].From), code)
+		}
+	}
+}
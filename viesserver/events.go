@@ -0,0 +1,153 @@
+package viesserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/alytsin/go-vies"
+)
+
+// AvailabilityChange is pushed when a member state, or VIES itself under
+// vies.VowCountryCode, changes its availability.
+type AvailabilityChange struct {
+	CountryCode  string `json:"countryCode"`
+	Previous     string `json:"previous"`
+	Availability string `json:"availability"`
+}
+
+type event struct {
+	name string
+	data []byte
+}
+
+// StatusEvents polls Status and streams availability changes to connected
+// clients as server-sent events.
+type StatusEvents struct {
+	client   *vies.Client
+	interval time.Duration
+
+	mu          sync.Mutex
+	last        *vies.Status
+	subscribers map[chan event]struct{}
+}
+
+func NewStatusEvents(client *vies.Client, interval time.Duration) *StatusEvents {
+	return &StatusEvents{
+		client:      client,
+		interval:    interval,
+		subscribers: make(map[chan event]struct{}),
+	}
+}
+
+// Run polls Status until the context is done. Failed polls are skipped.
+func (e *StatusEvents) Run(ctx context.Context) {
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		if status, err := e.client.Status(ctx); err == nil {
+			e.update(status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *StatusEvents) update(status *vies.Status) {
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	previous := e.last
+	e.last = status
+	if previous == nil {
+		return
+	}
+
+	for _, change := range availabilityChanges(previous, status) {
+		data, _ := json.Marshal(change)
+		for ch := range e.subscribers {
+			select {
+			case ch <- event{name: "change", data: data}:
+			default:
+				// slow subscriber, drop the event rather than block polling
+			}
+		}
+	}
+}
+
+func (e *StatusEvents) subscribe() (chan event, *vies.Status) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ch := make(chan event, 64)
+	e.subscribers[ch] = struct{}{}
+	return ch, e.last
+}
+
+func (e *StatusEvents) unsubscribe(ch chan event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.subscribers, ch)
+}
+
+// ServeHTTP sends the last known status as a "status" event followed by a
+// "change" event for every availability change.
+func (e *StatusEvents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch, last := e.subscribe()
+	defer e.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if last != nil {
+		data, _ := json.Marshal(last)
+		writeEvent(w, event{name: "status", data: data})
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-ch:
+			writeEvent(w, ev)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, ev event) {
+	_, _ = w.Write([]byte("event: " + ev.name + "\ndata: "))
+	_, _ = w.Write(ev.data)
+	_, _ = w.Write([]byte("\n\n"))
+}
+
+func availabilityChanges(previous, current *vies.Status) []AvailabilityChange {
+
+	var changes []AvailabilityChange
+	for _, change := range current.Diff(previous) {
+		changes = append(changes, AvailabilityChange{
+			CountryCode:  change.CountryCode,
+			Previous:     string(change.Previous),
+			Availability: string(change.Current),
+		})
+	}
+	return changes
+}
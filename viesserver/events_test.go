@@ -0,0 +1,86 @@
+package viesserver
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusEvents(t *testing.T) {
+	var polls atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if polls.Add(1) <= 2 {
+			_, _ = w.Write([]byte(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Unavailable"}]}`))
+	}))
+	defer upstream.Close()
+
+	client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: upstream.URL})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := NewStatusEvents(client, 20*time.Millisecond)
+	events.update(mustStatus(t, client))
+
+	server := httptest.NewServer(events)
+	defer server.Close()
+
+	rsp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	defer rsp.Body.Close()
+	assert.Equal(t, "text/event-stream", rsp.Header.Get("Content-Type"))
+
+	go events.Run(ctx)
+
+	reader := bufio.NewReader(rsp.Body)
+	var lines []string
+	for len(lines) < 6 {
+		line, err := reader.ReadString('\n')
+		assert.NoError(t, err)
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+
+	assert.Equal(t, []string{
+		"event: status",
+		`data: {"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"}]}`,
+		"",
+		"event: change",
+		`data: {"countryCode":"DE","previous":"Available","availability":"Unavailable"}`,
+		"",
+	}, lines)
+}
+
+func TestAvailabilityChanges(t *testing.T) {
+	previous := &vies.Status{
+		Vow:       vies.StatusVow{Available: true},
+		Countries: []vies.CountryStatus{{CountryCode: "DE", Availability: "Available"}, {CountryCode: "EE", Availability: "Available"}},
+	}
+	current := &vies.Status{
+		Vow:       vies.StatusVow{Available: false},
+		Countries: []vies.CountryStatus{{CountryCode: "DE", Availability: "Available"}, {CountryCode: "EE", Availability: "Unavailable"}, {CountryCode: "FR", Availability: "Available"}},
+	}
+
+	assert.Equal(t, []AvailabilityChange{
+		{CountryCode: "VoW", Previous: "Available", Availability: "Unavailable"},
+		{CountryCode: "EE", Previous: "Available", Availability: "Unavailable"},
+		{CountryCode: "FR", Previous: "", Availability: "Available"},
+	}, availabilityChanges(previous, current))
+}
+
+func mustStatus(t *testing.T, client *vies.Client) *vies.Status {
+	status, err := client.Status(context.Background())
+	assert.NoError(t, err)
+	return status
+}
@@ -0,0 +1,66 @@
+package viesserver
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/alytsin/go-vies"
+)
+
+// CheckHandler checks the VAT number taken from the "vat" path value or,
+// when the router does not provide one, from the "vat" query parameter.
+func CheckHandler(client *vies.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vat := r.PathValue("vat")
+		if vat == "" {
+			vat = r.URL.Query().Get("vat")
+		}
+
+		result, err := client.Check(requestContext(r), vat)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+// StatusHandler reports the availability of VIES and the member states.
+func StatusHandler(client *vies.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status, err := client.Status(requestContext(r))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, status)
+	})
+}
+
+// HealthHandler responds 200 when client.Healthy succeeds and 503
+// otherwise, for use as a readiness probe.
+func HealthHandler(client *vies.Client) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := client.Healthy(requestContext(r)); err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: "UNHEALTHY", Message: err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+}
+
+// OpenAPIHandler serves the OpenAPI document of the handlers.
+func OpenAPIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, OpenAPI())
+	})
+}
+
+// requestContext passes the correlation ID of the incoming request on to
+// the calls made to VIES.
+func requestContext(r *http.Request) context.Context {
+	if id := r.Header.Get("X-Correlation-ID"); id != "" {
+		return vies.WithCorrelationID(r.Context(), id)
+	}
+	return r.Context()
+}
@@ -0,0 +1,133 @@
+package viesserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckHandlerQuery(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"123","valid":true}`))
+	}))
+	defer upstream.Close()
+
+	client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: upstream.URL})
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	CheckHandler(client).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/anything?vat=EE123", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var result vies.CheckResult
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, "EE123", result.Vat)
+}
+
+func TestHealthHandler(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		upstream string
+		code     int
+		body     string
+	}{
+		{
+			name:     "healthy",
+			upstream: `{"vow":{"available":true},"countries":[]}`,
+			code:     http.StatusOK,
+			body:     `{"status":"ok"}` + "\n",
+		},
+		{
+			name:     "vow unavailable",
+			upstream: `{"vow":{"available":false},"countries":[]}`,
+			code:     http.StatusServiceUnavailable,
+			body:     `{"error":"UNHEALTHY","message":"VIES reports VoW unavailable"}` + "\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(tt.upstream))
+			}))
+			defer upstream.Close()
+
+			client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: upstream.URL})
+			assert.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			HealthHandler(client).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	rec := httptest.NewRecorder()
+	OpenAPIHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		OpenAPI    string                    `json:"openapi"`
+		Paths      map[string]map[string]any `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any `json:"properties"`
+				Required   []string                  `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Contains(t, doc.Paths, "/check/{vat}")
+	assert.Contains(t, doc.Paths, "/status")
+
+	check := doc.Components.Schemas["CheckResult"]
+	assert.Equal(t, map[string]any{"type": "boolean"}, check.Properties["valid"])
+	assert.Contains(t, check.Required, "vatNumber")
+	assert.NotContains(t, check.Required, "requestIdentifier")
+
+	status := doc.Components.Schemas["Status"]
+	assert.Equal(t, map[string]any{"$ref": "#/components/schemas/StatusVow"}, status.Properties["vow"])
+	assert.Equal(t, map[string]any{
+		"type":  "array",
+		"items": map[string]any{"$ref": "#/components/schemas/CountryStatus"},
+	}, status.Properties["countries"])
+
+	var raw any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+	for _, ref := range refs(raw) {
+		name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+		assert.True(t, ok, ref)
+		assert.Contains(t, doc.Components.Schemas, name, ref)
+	}
+}
+
+// refs returns the $ref values found anywhere in v.
+func refs(v any) []string {
+	var found []string
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				found = append(found, ref)
+			}
+			found = append(found, refs(value)...)
+		}
+	case []any:
+		for _, value := range v {
+			found = append(found, refs(value)...)
+		}
+	}
+	return found
+}
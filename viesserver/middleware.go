@@ -0,0 +1,63 @@
+package viesserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/alytsin/go-vies"
+)
+
+const defaultVatHeader = "X-Vat-Number"
+
+type resultKey struct{}
+
+// RequireValidVat returns a middleware checking the VAT number returned by
+// extract, the X-Vat-Number header when extract is nil. Requests with a
+// missing, malformed or invalid number are rejected with 422, those which
+// could not be checked with the status of the gateway errors. The result is
+// passed on in the request context, see ResultFromContext.
+func RequireValidVat(registry vies.RegistryInterface, extract func(*http.Request) string) func(http.Handler) http.Handler {
+
+	if extract == nil {
+		extract = func(r *http.Request) string {
+			return r.Header.Get(defaultVatHeader)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			vat := extract(r)
+			if vat == "" {
+				writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Error: "MISSING_VAT", Message: "no VAT number provided"})
+				return
+			}
+
+			result, err := registry.Check(requestContext(r), vat)
+			var apiErr *vies.ApiError
+			switch {
+			case errors.Is(err, vies.ErrInvalidVat):
+				writeJSON(w, http.StatusUnprocessableEntity, invalidInput(err))
+				return
+			case errors.As(err, &apiErr) && apiErr.Err == "INVALID_INPUT":
+				writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Error: apiErr.Err, Message: apiErr.Message})
+				return
+			case err != nil:
+				writeError(w, err)
+				return
+			case !result.Valid:
+				writeJSON(w, http.StatusUnprocessableEntity, errorResponse{Error: "INVALID_VAT", Message: "VAT number is not valid"})
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), resultKey{}, result)))
+		})
+	}
+}
+
+// ResultFromContext returns the check result stored by RequireValidVat.
+func ResultFromContext(ctx context.Context) (*vies.CheckResult, bool) {
+	result, ok := ctx.Value(resultKey{}).(*vies.CheckResult)
+	return result, ok
+}
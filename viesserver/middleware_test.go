@@ -0,0 +1,85 @@
+package viesserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+type registryFunc func(ctx context.Context, vat string) (*vies.CheckResult, error)
+
+func (f registryFunc) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+	return f(ctx, vat)
+}
+
+func TestRequireValidVat(t *testing.T) {
+
+	registry := registryFunc(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+		switch vat {
+		case "EE100354546":
+			return &vies.CheckResult{Vat: vat, Valid: true, Name: "ACME"}, nil
+		case "DE123456789":
+			return nil, vies.ErrCountryUnavailable
+		case "E":
+			return nil, vies.ErrInvalidVat
+		}
+		return &vies.CheckResult{Vat: vat}, nil
+	})
+
+	handler := RequireValidVat(registry, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, ok := ResultFromContext(r.Context())
+		assert.True(t, ok)
+		_, _ = w.Write([]byte(result.Name))
+	}))
+
+	cases := []struct {
+		name string
+		vat  string
+		code int
+		body string
+	}{
+		{name: "valid", vat: "EE100354546", code: http.StatusOK, body: "ACME"},
+		{name: "missing", code: http.StatusUnprocessableEntity, body: `{"error":"MISSING_VAT","message":"no VAT number provided"}`},
+		{name: "invalid", vat: "EE100354547", code: http.StatusUnprocessableEntity, body: `{"error":"INVALID_VAT","message":"VAT number is not valid"}`},
+		{name: "malformed", vat: "E", code: http.StatusUnprocessableEntity, body: `{"error":"INVALID_INPUT","message":"invalid VAT provided"}`},
+		{name: "unavailable", vat: "DE123456789", code: http.StatusServiceUnavailable, body: `{"error":"MS_UNAVAILABLE","message":"member state unavailable"}`},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/order", nil)
+			if tt.vat != "" {
+				req.Header.Set("X-Vat-Number", tt.vat)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.body, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
+
+func TestRequireValidVatExtract(t *testing.T) {
+
+	registry := registryFunc(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+		return &vies.CheckResult{Vat: vat, Valid: true}, nil
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /customers/{vat}", RequireValidVat(registry, func(r *http.Request) string {
+		return r.PathValue("vat")
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, _ := ResultFromContext(r.Context())
+		_, _ = w.Write([]byte(result.Vat))
+	})))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/customers/EE100354546", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "EE100354546", rec.Body.String())
+}
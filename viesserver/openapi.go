@@ -0,0 +1,156 @@
+package viesserver
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/alytsin/go-vies"
+)
+
+// OpenAPI returns the OpenAPI 3 document describing /check/{vat} and
+// /status. Schemas are derived from the response types of the package so
+// the document never drifts from what the handlers return.
+func OpenAPI() map[string]any {
+
+	errorResponses := map[string]any{
+		"400": response("Invalid VAT number", "Error"),
+		"403": response("Member state not allowed", "Error"),
+		"502": response("VIES returned an error or could not be reached", "Error"),
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "VIES VAT validation",
+			"version": "1.0.0",
+		},
+		"paths": map[string]any{
+			"/check/{vat}": map[string]any{
+				"get": map[string]any{
+					"operationId": "check",
+					"summary":     "Check a VAT number",
+					"parameters": []any{
+						map[string]any{
+							"name":        "vat",
+							"in":          "path",
+							"required":    true,
+							"description": "VAT number prefixed with the member state code",
+							"schema":      map[string]any{"type": "string"},
+						},
+					},
+					"responses": merge(map[string]any{
+						"200": response("VIES response", "CheckResult"),
+					}, errorResponses),
+				},
+			},
+			"/status": map[string]any{
+				"get": map[string]any{
+					"operationId": "status",
+					"summary":     "Availability of VIES and the member states",
+					"responses": merge(map[string]any{
+						"200": response("Availability", "Status"),
+					}, errorResponses),
+				},
+			},
+		},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"CheckResult":   schemaOf(reflect.TypeOf(vies.CheckResult{})),
+				"Status":        schemaOf(reflect.TypeOf(vies.Status{})),
+				"StatusVow":     schemaOf(reflect.TypeOf(vies.StatusVow{})),
+				"CountryStatus": schemaOf(reflect.TypeOf(vies.CountryStatus{})),
+				"Proof":         schemaOf(reflect.TypeOf(vies.Proof{})),
+				"Error":         schemaOf(reflect.TypeOf(errorResponse{})),
+			},
+		},
+	}
+}
+
+func response(description, schema string) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/" + schema},
+			},
+		},
+	}
+}
+
+func merge(a, b map[string]any) map[string]any {
+	for k, v := range b {
+		a[k] = v
+	}
+	return a
+}
+
+// schemaOf builds the schema of a type from its JSON encoding rules, named
+// structs of the vies package are referenced instead of inlined.
+func schemaOf(t reflect.Type) map[string]any {
+
+	if t == reflect.TypeOf(json.RawMessage(nil)) {
+		return map[string]any{}
+	}
+	if t == reflect.TypeOf([]byte(nil)) {
+		return map[string]any{"type": "string", "format": "byte"}
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": reference(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": reference(t.Elem())}
+	case reflect.Struct:
+		properties := map[string]any{}
+		var required []string
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = reference(field.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+		schema := map[string]any{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	}
+	return map[string]any{}
+}
+
+func reference(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct && t.PkgPath() == reflect.TypeOf(vies.Status{}).PkgPath() {
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	}
+	return schemaOf(t)
+}
@@ -0,0 +1,89 @@
+// Package viesserver exposes a VIES client over HTTP so that a single shared
+// gateway, with its own cache and rate limit, can serve many applications.
+package viesserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/alytsin/go-vies"
+)
+
+type Server struct {
+	client *vies.Client
+	mux    *http.ServeMux
+}
+
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	// Reason is the vies.InputReason of INVALID_INPUT errors detected
+	// before calling VIES.
+	Reason string `json:"reason,omitempty"`
+}
+
+// invalidInput returns the INVALID_INPUT response of err.
+func invalidInput(err error) errorResponse {
+	response := errorResponse{Error: "INVALID_INPUT", Message: err.Error()}
+	var inputErr *vies.ErrInvalidInput
+	if errors.As(err, &inputErr) {
+		response.Reason = string(inputErr.Reason)
+	}
+	return response
+}
+
+// New returns a handler serving GET /check/{vat}, GET /status, the
+// readiness probe GET /healthz and the OpenAPI document at GET /openapi.json.
+func New(client *vies.Client) *Server {
+	s := &Server{
+		client: client,
+		mux:    http.NewServeMux(),
+	}
+	s.mux.Handle("GET /check/{vat}", CheckHandler(client))
+	s.mux.Handle("GET /status", StatusHandler(client))
+	s.mux.Handle("GET /healthz", HealthHandler(client))
+	s.mux.Handle("GET /openapi.json", OpenAPIHandler())
+	return s
+}
+
+// WithEvents polls Status every interval until the context is done and
+// streams availability changes at GET /events.
+func (s *Server) WithEvents(ctx context.Context, interval time.Duration) *Server {
+	events := NewStatusEvents(s.client, interval)
+	go events.Run(ctx)
+	s.mux.Handle("GET /events", events)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+
+	var apiErr *vies.ApiError
+
+	switch {
+	case errors.Is(err, vies.ErrInvalidVat):
+		writeJSON(w, http.StatusBadRequest, invalidInput(err))
+	case errors.Is(err, vies.ErrCountryUnavailable):
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{Error: "MS_UNAVAILABLE", Message: err.Error()})
+	case errors.Is(err, vies.ErrCountryNotAllowed):
+		writeJSON(w, http.StatusForbidden, errorResponse{Error: "COUNTRY_NOT_ALLOWED", Message: err.Error()})
+	case errors.As(err, &apiErr) && apiErr.Err == "INVALID_INPUT":
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: apiErr.Err, Message: apiErr.Message})
+	case errors.As(err, &apiErr):
+		writeJSON(w, http.StatusBadGateway, errorResponse{Error: apiErr.Err, Message: apiErr.Message})
+	default:
+		writeJSON(w, http.StatusBadGateway, errorResponse{Error: "UPSTREAM_ERROR", Message: err.Error()})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
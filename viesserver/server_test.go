@@ -0,0 +1,100 @@
+package viesserver
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServer(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/check-vat-number":
+			_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"123","valid":true}`))
+		case "/check-status":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"errorWrappers":[{"error":"SERVICE_UNAVAILABLE","message":"down"}]}`))
+		}
+	}))
+	defer upstream.Close()
+
+	client, err := vies.NewClient(&vies.ClientConfig{
+		EndpointUrl: upstream.URL,
+		Cache:       vies.NewMemoryCache(time.Minute),
+	})
+	assert.NoError(t, err)
+
+	server := httptest.NewServer(New(client))
+	defer server.Close()
+
+	cases := []struct {
+		name string
+		path string
+		code int
+		body string
+	}{
+		{
+			name: "check",
+			path: "/check/EE123",
+			code: http.StatusOK,
+			body: `{"countryCode":"EE","address":"","vatNumber":"123","vat":"EE123","valid":true,"name":""}` + "\n",
+		},
+		{
+			name: "check cached",
+			path: "/check/ee123",
+			code: http.StatusOK,
+			body: `{"countryCode":"EE","address":"","vatNumber":"123","vat":"EE123","valid":true,"name":""}` + "\n",
+		},
+		{
+			name: "invalid input",
+			path: "/check/E",
+			code: http.StatusBadRequest,
+			body: `{"error":"INVALID_INPUT","message":"invalid VAT provided E","reason":"too_short"}` + "\n",
+		},
+		{
+			name: "upstream error",
+			path: "/status",
+			code: http.StatusBadGateway,
+			body: `{"error":"SERVICE_UNAVAILABLE","message":"down"}` + "\n",
+		},
+		{
+			name: "unknown path",
+			path: "/bogus",
+			code: http.StatusNotFound,
+			body: "404 page not found\n",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp, err := http.Get(server.URL + tt.path)
+			assert.NoError(t, err)
+			defer rsp.Body.Close()
+
+			body, err := io.ReadAll(rsp.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.code, rsp.StatusCode)
+			assert.Equal(t, tt.body, string(body))
+		})
+	}
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestServerCountryNotAllowed(t *testing.T) {
+
+	client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: "http://127.0.0.1:1/", AllowedCountries: []string{"DE"}})
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	New(client).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/check/FR40303265045", nil))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, `{"error":"COUNTRY_NOT_ALLOWED","message":"member state not allowed FR"}`+"\n", rec.Body.String())
+}
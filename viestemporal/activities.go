@@ -0,0 +1,123 @@
+// Package viestemporal provides Temporal activities checking VAT numbers,
+// with errors classified so that the retry policy only retries what can
+// succeed later:
+//
+//	w.RegisterActivity(viestemporal.NewActivities(client))
+//
+//	ctx = workflow.WithActivityOptions(ctx, viestemporal.ActivityOptions())
+//	var result vies.CheckResult
+//	err := workflow.ExecuteActivity(ctx, "CheckActivity", vat).Get(ctx, &result)
+package viestemporal
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Error types of the application errors returned by the activities.
+const (
+	// ErrorTypeInvalidInput is a malformed VAT number, never retried.
+	ErrorTypeInvalidInput = "INVALID_INPUT"
+	// ErrorTypeUnavailable is a member state or VIES being down or
+	// overloaded, retried with a long backoff.
+	ErrorTypeUnavailable = "MS_UNAVAILABLE"
+	// ErrorTypeNotAllowed is a member state the client doesn't allow,
+	// never retried.
+	ErrorTypeNotAllowed = "COUNTRY_NOT_ALLOWED"
+)
+
+// unavailableCodes are the VIES error codes of temporary failures.
+var unavailableCodes = []string{
+	"MS_UNAVAILABLE",
+	"MS_MAX_CONCURRENT_REQ",
+	"GLOBAL_MAX_CONCURRENT_REQ",
+	"SERVICE_UNAVAILABLE",
+	"TIMEOUT",
+}
+
+// invalidCodes are the VIES error codes of requests which cannot succeed.
+var invalidCodes = []string{
+	"INVALID_INPUT",
+	"INVALID_REQUESTER_INFO",
+	"VAT_BLOCKED",
+	"IP_BLOCKED",
+}
+
+type Activities struct {
+	client *vies.Client
+}
+
+func NewActivities(client *vies.Client) *Activities {
+	return &Activities{client: client}
+}
+
+// CheckActivity checks a VAT number with VIES.
+func (a *Activities) CheckActivity(ctx context.Context, vat string) (*vies.CheckResult, error) {
+	result, err := a.client.Check(ctx, vat)
+	if err != nil {
+		return nil, classify(err)
+	}
+	return result, nil
+}
+
+// StatusActivity returns the availability of VIES and the member states.
+func (a *Activities) StatusActivity(ctx context.Context) (*vies.Status, error) {
+	status, err := a.client.Status(ctx)
+	if err != nil {
+		return nil, classify(err)
+	}
+	return status, nil
+}
+
+// RetryPolicy retries temporary failures for as long as the schedule to
+// close timeout allows, backing off up to half an hour since member states
+// can be down for hours.
+func RetryPolicy() *temporal.RetryPolicy {
+	return &temporal.RetryPolicy{
+		InitialInterval:        30 * time.Second,
+		BackoffCoefficient:     2,
+		MaximumInterval:        30 * time.Minute,
+		NonRetryableErrorTypes: []string{ErrorTypeInvalidInput, ErrorTypeNotAllowed},
+	}
+}
+
+// ActivityOptions gives a single attempt a minute and the whole check a
+// day, with RetryPolicy.
+func ActivityOptions() workflow.ActivityOptions {
+	return workflow.ActivityOptions{
+		StartToCloseTimeout:    time.Minute,
+		ScheduleToCloseTimeout: 24 * time.Hour,
+		RetryPolicy:            RetryPolicy(),
+	}
+}
+
+// classify converts the errors of the client to application errors typed
+// after the VIES error code.
+func classify(err error) error {
+
+	var apiErr *vies.ApiError
+
+	switch {
+	case errors.Is(err, vies.ErrInvalidVat):
+		return temporal.NewNonRetryableApplicationError(err.Error(), ErrorTypeInvalidInput, err)
+	case errors.Is(err, vies.ErrCountryNotAllowed):
+		return temporal.NewNonRetryableApplicationError(err.Error(), ErrorTypeNotAllowed, err)
+	case errors.Is(err, vies.ErrCountryUnavailable):
+		return temporal.NewApplicationErrorWithCause(err.Error(), ErrorTypeUnavailable, err)
+	case errors.As(err, &apiErr):
+		if slices.Contains(invalidCodes, apiErr.Err) {
+			return temporal.NewNonRetryableApplicationError(err.Error(), apiErr.Err, err)
+		}
+		if slices.Contains(unavailableCodes, apiErr.Err) {
+			return temporal.NewApplicationErrorWithCause(err.Error(), ErrorTypeUnavailable, err)
+		}
+		return temporal.NewApplicationErrorWithCause(err.Error(), apiErr.Err, err)
+	}
+	return err
+}
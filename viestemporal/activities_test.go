@@ -0,0 +1,90 @@
+package viestemporal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestActivities(t *testing.T) {
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/check-status":
+			_, _ = w.Write([]byte(`{"vow":{"available":true},"countries":[{"countryCode":"EE","availability":"Available"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"countryCode":"EE","vatNumber":"100354546","valid":true,"name":"ACME"}`))
+		}
+	}))
+	defer upstream.Close()
+
+	client, err := vies.NewClient(&vies.ClientConfig{EndpointUrl: upstream.URL})
+	assert.NoError(t, err)
+	activities := NewActivities(client)
+
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestActivityEnvironment()
+	env.RegisterActivity(activities)
+
+	value, err := env.ExecuteActivity(activities.CheckActivity, "EE100354546")
+	assert.NoError(t, err)
+	var result vies.CheckResult
+	assert.NoError(t, value.Get(&result))
+	assert.True(t, result.Valid)
+	assert.Equal(t, "EE100354546", result.Vat)
+
+	value, err = env.ExecuteActivity(activities.StatusActivity)
+	assert.NoError(t, err)
+	var status vies.Status
+	assert.NoError(t, value.Get(&status))
+	assert.True(t, status.Vow.Available)
+
+	_, err = env.ExecuteActivity(activities.CheckActivity, "E")
+	var appErr *temporal.ApplicationError
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, ErrorTypeInvalidInput, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+}
+
+func TestClassify(t *testing.T) {
+
+	cases := []struct {
+		name         string
+		err          error
+		errType      string
+		nonRetryable bool
+	}{
+		{name: "invalid vat", err: vies.ErrInvalidVat, errType: ErrorTypeInvalidInput, nonRetryable: true},
+		{name: "invalid input", err: &vies.ApiError{Err: "INVALID_INPUT"}, errType: "INVALID_INPUT", nonRetryable: true},
+		{name: "preflight", err: vies.ErrCountryUnavailable, errType: ErrorTypeUnavailable},
+		{name: "not allowed", err: vies.ErrCountryNotAllowed, errType: ErrorTypeNotAllowed, nonRetryable: true},
+		{name: "ms unavailable", err: &vies.ApiError{Err: "MS_UNAVAILABLE"}, errType: ErrorTypeUnavailable},
+		{name: "concurrency", err: &vies.ApiError{Err: "MS_MAX_CONCURRENT_REQ"}, errType: ErrorTypeUnavailable},
+		{name: "other code", err: &vies.ApiError{Err: "SOMETHING"}, errType: "SOMETHING"},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			var appErr *temporal.ApplicationError
+			assert.True(t, errors.As(classify(tt.err), &appErr))
+			assert.Equal(t, tt.errType, appErr.Type())
+			assert.Equal(t, tt.nonRetryable, appErr.NonRetryable())
+			assert.ErrorIs(t, appErr, tt.err)
+		})
+	}
+
+	transport := errors.New("connection reset")
+	assert.Equal(t, transport, classify(transport))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	assert.Contains(t, RetryPolicy().NonRetryableErrorTypes, ErrorTypeInvalidInput)
+	assert.Contains(t, RetryPolicy().NonRetryableErrorTypes, ErrorTypeNotAllowed)
+	assert.Equal(t, RetryPolicy(), ActivityOptions().RetryPolicy)
+}
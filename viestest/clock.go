@@ -0,0 +1,103 @@
+package viestest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/alytsin/go-vies"
+)
+
+// Clock is a vies.ClockInterface whose time only moves with Advance and
+// Set, so that tests of TTLs, retries and schedules don't wait:
+//
+//	clock := viestest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	client, err := vies.New(vies.WithClock(clock), vies.WithRetry(3, time.Minute))
+//	go client.Check(ctx, vat)
+//	clock.BlockUntil(1)
+//	clock.Advance(time.Minute)
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []clockWaiter
+}
+
+type clockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+var _ vies.ClockInterface = (*Clock)(nil)
+
+// NewClock returns a Clock at now.
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel receiving the time once the clock has been
+// advanced by d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, clockWaiter{at: c.now.Add(d), ch: ch})
+	c.cond.Broadcast()
+	return ch
+}
+
+// Advance moves the clock forward by d, firing the waits which are due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(c.now.Add(d))
+}
+
+// Set moves the clock to t, firing the waits which are due.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(t)
+}
+
+func (c *Clock) set(t time.Time) {
+	c.now = t
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(t) {
+			pending = append(pending, w)
+		} else {
+			w.ch <- t
+		}
+	}
+	c.waiters = pending
+}
+
+// Waiters returns the number of pending waits.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil returns once at least n waits are pending, e.g. before
+// advancing the clock past a retry backoff started by another goroutine.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
@@ -0,0 +1,104 @@
+package viestest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func TestClockCache(t *testing.T) {
+
+	clock := NewClock(epoch)
+	cache := vies.NewMemoryCache(time.Hour)
+	cache.SetClock(clock)
+
+	cache.Set("DE123456789", &vies.CheckResult{Valid: true})
+	_, ok := cache.Get("DE123456789")
+	assert.True(t, ok)
+
+	clock.Advance(2 * time.Hour)
+	_, ok = cache.Get("DE123456789")
+	assert.False(t, ok)
+
+	files, err := vies.NewFileCache(t.TempDir(), time.Hour)
+	assert.NoError(t, err)
+	files.SetClock(clock)
+	files.Set("DE123456789", &vies.CheckResult{Valid: true})
+	clock.Advance(30 * time.Minute)
+	_, ok = files.Get("DE123456789")
+	assert.True(t, ok)
+	clock.Advance(time.Hour)
+	_, ok = files.Get("DE123456789")
+	assert.False(t, ok)
+}
+
+func TestClockClient(t *testing.T) {
+
+	s := NewServer()
+	defer s.Close()
+	s.SetValid("DE123456789", "ACME GmbH", "Berlin")
+	s.SetError("FR12345678901", "MS_UNAVAILABLE")
+
+	clock := NewClock(epoch)
+	client, err := s.Client(
+		vies.WithClock(clock),
+		vies.WithRevalidation(24*time.Hour, time.Hour),
+		vies.WithRetry(3, time.Minute),
+	)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	result, err := client.Check(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.Equal(t, epoch, result.CheckedAt.UTC())
+
+	// the backoff waits for the clock
+	done := make(chan error)
+	go func() {
+		_, err := client.Check(ctx, "FR12345678901")
+		done <- err
+	}()
+	clock.BlockUntil(1)
+	assert.Len(t, s.Requests(), 2)
+	clock.Advance(time.Minute)
+	clock.BlockUntil(1)
+	assert.Len(t, s.Requests(), 3)
+	clock.Advance(2 * time.Minute)
+	assert.Error(t, <-done)
+	assert.Len(t, s.Requests(), 4)
+}
+
+func TestClockScheduler(t *testing.T) {
+
+	s := NewServer()
+	defer s.Close()
+	clock := NewClock(epoch)
+	client, err := s.Client(vies.WithClock(clock))
+	assert.NoError(t, err)
+
+	results := make(chan vies.BulkResult, 10)
+	scheduler := vies.NewScheduler(client, vies.VatList{"DE123456789", "FR12345678901"},
+		vies.ResultSinkFunc(func(_ context.Context, result vies.BulkResult) { results <- result }),
+		&vies.SchedulerConfig{Interval: time.Hour},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = scheduler.Run(ctx) }()
+
+	assert.Equal(t, "DE123456789", (<-results).Vat)
+	// the second check waits half the interval
+	clock.BlockUntil(1)
+	assert.Len(t, results, 0)
+	clock.Advance(30 * time.Minute)
+	assert.Equal(t, "FR12345678901", (<-results).Vat)
+
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Minute)
+	assert.Equal(t, "DE123456789", (<-results).Vat)
+}
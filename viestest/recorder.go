@@ -0,0 +1,355 @@
+package viestest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ErrNoInteraction is returned by a replaying Recorder for requests its
+// fixture has no interaction for.
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+// RecorderMode tells whether a Recorder records or replays.
+type RecorderMode int
+
+const (
+	// ModeAuto replays the fixture when it exists and records it
+	// otherwise.
+	ModeAuto RecorderMode = iota
+	// ModeReplay only replays, requests without interaction failing with
+	// ErrNoInteraction.
+	ModeReplay
+	// ModeRecord sends every request and records a new fixture.
+	ModeRecord
+)
+
+// vatFields are the fields of the requests and responses of VIES holding VAT
+// numbers, scrubbed from fixtures.
+var vatFields = regexp.MustCompile(`("(?:vatNumber|requesterNumber)"\s*:\s*")([^"]*)(")`)
+
+// Interaction is a request and its response, as kept in a fixture.
+type Interaction struct {
+	Request struct {
+		Method string `json:"method"`
+		Path   string `json:"path"`
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"statusCode"`
+		Header     http.Header `json:"header,omitempty"`
+		Body       string      `json:"body"`
+	} `json:"response"`
+}
+
+type fixture struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+type RecorderConfig struct {
+	Mode RecorderMode
+	// Transport sends the requests while recording, defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+	// KeepVatNumbers keeps the VAT numbers in the fixture as they are,
+	// they are replaced with placeholders by default.
+	KeepVatNumbers bool
+}
+
+// Recorder is an http.RoundTripper which records the exchanges with VIES to
+// a fixture file, then replays them, for tests which don't depend on the
+// availability of VIES. VAT numbers are replaced in the fixture with
+// sequential placeholders, SCRUBBED-1, SCRUBBED-2 and so on, and put back in
+// the replayed responses.
+//
+//	recorder, err := viestest.NewRecorder("testdata/check.json", nil)
+//	client, err := vies.New(vies.WithHttpClient(recorder.Client()))
+//
+// Requests are matched on their method, path and body, each interaction
+// being replayed once, the last matching one again when all were. A
+// placeholder matches any VAT number the first time, then only that number,
+// so requests for different numbers are replayed in the order they were
+// recorded.
+type Recorder struct {
+	path      string
+	recording bool
+	transport http.RoundTripper
+	scrub     bool
+
+	mu     sync.Mutex
+	stored fixture
+	used   []bool
+	// placeholders and values map the VAT numbers and their placeholders
+	// both ways.
+	placeholders map[string]string
+	values       map[string]string
+}
+
+// NewRecorder returns a Recorder of the fixture at path.
+func NewRecorder(path string, config *RecorderConfig) (*Recorder, error) {
+
+	mode := ModeAuto
+	transport := http.DefaultTransport
+	scrub := true
+
+	if config != nil {
+		mode = config.Mode
+		if config.Transport != nil {
+			transport = config.Transport
+		}
+		scrub = !config.KeepVatNumbers
+	}
+
+	r := &Recorder{
+		path:         path,
+		transport:    transport,
+		scrub:        scrub,
+		placeholders: make(map[string]string),
+		values:       make(map[string]string),
+	}
+	content, err := os.ReadFile(path)
+	switch {
+	case mode == ModeRecord || mode == ModeAuto && errors.Is(err, os.ErrNotExist):
+		r.recording = true
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(content, &r.stored); err != nil {
+			return nil, fmt.Errorf("corrupted fixture %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.stored.Interactions))
+	}
+	return r, nil
+}
+
+// Recording reports whether the recorder records rather than replays.
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Client returns an HTTP client sending its requests through the recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+
+	if r.recording {
+		return r.record(req, body)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+	var bound map[string]string
+	for i, interaction := range r.stored.Interactions {
+		if interaction.Request.Method != req.Method || interaction.Request.Path != req.URL.Path {
+			continue
+		}
+		if b, ok := r.bind(interaction.Request.Body, string(body)); ok {
+			found, bound = i, b
+			if !r.used[i] {
+				break
+			}
+		}
+	}
+	if found < 0 {
+		return nil, fmt.Errorf("%w for %s %s", ErrNoInteraction, req.Method, req.URL.Path)
+	}
+	r.used[found] = true
+	for placeholder, value := range bound {
+		r.placeholders[value] = placeholder
+		r.values[placeholder] = value
+	}
+	interaction := r.stored.Interactions[found]
+	return r.response(req, interaction, r.restore(interaction.Response.Body)), nil
+}
+
+// record sends req and appends the exchange to the fixture.
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	// plain responses are stored, the transport decompresses them itself
+	out.Header.Del("Accept-Encoding")
+
+	rsp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	content, err := io.ReadAll(rsp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := &Interaction{}
+	interaction.Request.Method = req.Method
+	interaction.Request.Path = req.URL.Path
+	interaction.Response.StatusCode = rsp.StatusCode
+	interaction.Response.Header = make(http.Header)
+	for _, name := range []string{"Content-Type"} {
+		if value := rsp.Header.Get(name); value != "" {
+			interaction.Response.Header.Set(name, value)
+		}
+	}
+
+	r.mu.Lock()
+	interaction.Request.Body = r.scrubVats(string(body))
+	interaction.Response.Body = r.scrubVats(string(content))
+	r.stored.Interactions = append(r.stored.Interactions, interaction)
+	err = r.save()
+	r.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return r.response(req, interaction, string(content)), nil
+}
+
+// response returns the response of interaction to req with body.
+func (r *Recorder) response(req *http.Request, interaction *Interaction, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Header.Clone(),
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// save writes the fixture, r.mu being held.
+func (r *Recorder) save() error {
+
+	content, err := json.MarshalIndent(&r.stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(content, '\n'), 0o644)
+}
+
+// scrubVats replaces the VAT numbers of body with their placeholders, new
+// numbers getting the next one, r.mu being held.
+func (r *Recorder) scrubVats(body string) string {
+
+	if !r.scrub {
+		return body
+	}
+	return vatFields.ReplaceAllStringFunc(body, func(m string) string {
+		parts := vatFields.FindStringSubmatch(m)
+		if parts[2] == "" {
+			return m
+		}
+		placeholder, ok := r.placeholders[parts[2]]
+		if !ok {
+			placeholder = fmt.Sprintf("SCRUBBED-%d", len(r.placeholders)+1)
+			r.placeholders[parts[2]] = placeholder
+			r.values[placeholder] = parts[2]
+		}
+		return parts[1] + placeholder + parts[3]
+	})
+}
+
+// restore puts the VAT numbers of body back in place of their placeholders,
+// r.mu being held.
+func (r *Recorder) restore(body string) string {
+	return vatFields.ReplaceAllStringFunc(body, func(m string) string {
+		parts := vatFields.FindStringSubmatch(m)
+		if value, ok := r.values[parts[2]]; ok {
+			return parts[1] + value + parts[3]
+		}
+		return m
+	})
+}
+
+// bind matches body against the recorded one, returning the placeholders of
+// recorded with the VAT numbers of body they stand for. A placeholder
+// already bound only matches its own number, r.mu being held.
+func (r *Recorder) bind(recorded, body string) (map[string]string, bool) {
+
+	if !r.scrub {
+		return nil, sameJSON(recorded, body)
+	}
+	placeholders, values := vatValues(recorded), vatValues(body)
+	if len(placeholders) != len(values) {
+		return nil, false
+	}
+
+	bound := make(map[string]string)
+	boundTo := make(map[string]string)
+	for i, placeholder := range placeholders {
+		value := values[i]
+		if known, ok := r.values[placeholder]; ok && known != value {
+			return nil, false
+		}
+		if known, ok := r.placeholders[value]; ok && known != placeholder {
+			return nil, false
+		}
+		if known, ok := bound[placeholder]; ok && known != value {
+			return nil, false
+		}
+		if known, ok := boundTo[value]; ok && known != placeholder {
+			return nil, false
+		}
+		bound[placeholder] = value
+		boundTo[value] = placeholder
+	}
+
+	i := 0
+	scrubbed := vatFields.ReplaceAllStringFunc(body, func(m string) string {
+		parts := vatFields.FindStringSubmatch(m)
+		if parts[2] == "" {
+			return m
+		}
+		i++
+		return parts[1] + placeholders[i-1] + parts[3]
+	})
+	return bound, sameJSON(recorded, scrubbed)
+}
+
+// vatValues returns the VAT numbers of body, in order.
+func vatValues(body string) []string {
+	var values []string
+	for _, parts := range vatFields.FindAllStringSubmatch(body, -1) {
+		if parts[2] != "" {
+			values = append(values, parts[2])
+		}
+	}
+	return values
+}
+
+// sameJSON compares two bodies as JSON documents, or as text when they
+// are not JSON.
+func sameJSON(a, b string) bool {
+	var va, vb any
+	if json.Unmarshal([]byte(a), &va) != nil || json.Unmarshal([]byte(b), &vb) != nil {
+		return a == b
+	}
+	ca, _ := json.Marshal(va)
+	cb, _ := json.Marshal(vb)
+	return bytes.Equal(ca, cb)
+}
@@ -0,0 +1,134 @@
+package viestest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+
+	s := NewServer()
+	defer s.Close()
+	s.SetValid("DE123456789", "ACME GmbH", "Berlin")
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "testdata", "check.json")
+
+	recorder, err := NewRecorder(path, nil)
+	assert.NoError(t, err)
+	assert.True(t, recorder.Recording())
+	client, err := vies.New(vies.WithEndpoint(s.EndpointUrl()), vies.WithHttpClient(recorder.Client()))
+	assert.NoError(t, err)
+
+	result, err := client.Check(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "123456789", result.VatNumber)
+	valid, err := client.Valid(ctx, "IT"+TestInvalidNumber)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	// VAT numbers are scrubbed from the fixture
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "123456789")
+	assert.Contains(t, string(content), "SCRUBBED-")
+	assert.Contains(t, string(content), "ACME GmbH")
+
+	// replayed without the server, numbers restored
+	s.Close()
+	recorder, err = NewRecorder(path, &RecorderConfig{Mode: ModeReplay})
+	assert.NoError(t, err)
+	assert.False(t, recorder.Recording())
+	client, err = vies.New(vies.WithEndpoint(s.EndpointUrl()), vies.WithHttpClient(recorder.Client()))
+	assert.NoError(t, err)
+
+	for range 2 {
+		result, err = client.Check(ctx, "DE123456789")
+		assert.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, "123456789", result.VatNumber)
+		assert.Equal(t, "ACME GmbH", result.Name)
+	}
+	valid, err = client.Valid(ctx, "IT"+TestInvalidNumber)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = client.Check(ctx, "FR12345678901")
+	assert.True(t, errors.Is(err, ErrNoInteraction))
+
+	_, err = NewRecorder(filepath.Join(t.TempDir(), "missing.json"), &RecorderConfig{Mode: ModeReplay})
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
+
+func TestRecorderPlaceholders(t *testing.T) {
+
+	s := NewServer()
+	defer s.Close()
+	s.SetValid("DE123456789", "ACME GmbH", "Berlin")
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "check.json")
+
+	recorder, err := NewRecorder(path, nil)
+	assert.NoError(t, err)
+	client, err := vies.New(vies.WithEndpoint(s.EndpointUrl()), vies.WithHttpClient(recorder.Client()))
+	assert.NoError(t, err)
+	valid, err := client.Valid(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = client.Valid(ctx, "DE"+TestInvalidNumber)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	// no digest of the numbers is kept, only their order
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "123456789")
+	assert.Contains(t, string(content), "SCRUBBED-1")
+	assert.Contains(t, string(content), "SCRUBBED-2")
+
+	s.Close()
+	recorder, err = NewRecorder(path, &RecorderConfig{Mode: ModeReplay})
+	assert.NoError(t, err)
+	client, err = vies.New(vies.WithEndpoint(s.EndpointUrl()), vies.WithHttpClient(recorder.Client()))
+	assert.NoError(t, err)
+
+	for range 2 {
+		valid, err = client.Valid(ctx, "DE123456789")
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		valid, err = client.Valid(ctx, "DE"+TestInvalidNumber)
+		assert.NoError(t, err)
+		assert.False(t, valid)
+	}
+
+	// both placeholders are bound
+	_, err = client.Check(ctx, "DE555555555")
+	assert.True(t, errors.Is(err, ErrNoInteraction))
+}
+
+func TestRecorderKeepVatNumbers(t *testing.T) {
+
+	s := NewServer()
+	defer s.Close()
+	s.SetValid("DE123456789", "ACME GmbH", "Berlin")
+
+	path := filepath.Join(t.TempDir(), "check.json")
+	recorder, err := NewRecorder(path, &RecorderConfig{KeepVatNumbers: true})
+	assert.NoError(t, err)
+	client, err := vies.New(vies.WithEndpoint(s.EndpointUrl()), vies.WithHttpClient(recorder.Client()))
+	assert.NoError(t, err)
+	_, err = client.Check(context.Background(), "DE123456789")
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "123456789")
+}
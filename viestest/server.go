@@ -0,0 +1,313 @@
+// Package viestest provides Server, a fake VIES REST API for integration
+// tests, which answers check-vat-number and check-status requests in the
+// shapes of VIES, failures included, Recorder, which records and replays
+// the exchanges with VIES, and Clock, a clock moved by tests.
+package viestest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/alytsin/go-vies/viescountries"
+)
+
+// testNumbers are the numbers of the VIES test service, valid, invalid or
+// failing with an error code whatever the member state.
+var testNumbers = map[string]string{
+	"201": "INVALID_INPUT",
+	"202": "INVALID_REQUESTER_INFO",
+	"300": "SERVICE_UNAVAILABLE",
+	"301": "MS_UNAVAILABLE",
+	"302": "TIMEOUT",
+	"400": "VAT_BLOCKED",
+	"401": "IP_BLOCKED",
+	"500": "GLOBAL_MAX_CONCURRENT_REQ",
+	"501": "GLOBAL_MAX_CONCURRENT_REQ_TIME",
+	"600": "MS_MAX_CONCURRENT_REQ",
+	"601": "MS_MAX_CONCURRENT_REQ_TIME",
+}
+
+const (
+	// TestValidNumber and TestInvalidNumber are valid and invalid in every
+	// member state, as in the VIES test service. The other numbers of the
+	// test service fail with their error code.
+	TestValidNumber   = "100"
+	TestInvalidNumber = "200"
+)
+
+// CheckRequest is a request received by the Server.
+type CheckRequest struct {
+	CountryCode              string `json:"countryCode"`
+	VatNumber                string `json:"vatNumber"`
+	RequesterMemberStateCode string `json:"requesterMemberStateCode,omitempty"`
+	RequesterNumber          string `json:"requesterNumber,omitempty"`
+}
+
+type errorWrapper struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+type errorResponse struct {
+	ActionSucceed bool           `json:"actionSucceed"`
+	ErrorWrappers []errorWrapper `json:"errorWrappers"`
+}
+
+type checkResponse struct {
+	CountryCode       string `json:"countryCode"`
+	VatNumber         string `json:"vatNumber"`
+	RequestDate       string `json:"requestDate"`
+	Valid             bool   `json:"valid"`
+	RequestIdentifier string `json:"requestIdentifier"`
+	Name              string `json:"name"`
+	Address           string `json:"address"`
+}
+
+// Server is a fake VIES REST API. Numbers are invalid unless made valid
+// with SetValid, but for the numbers of the VIES test service, and every
+// member state is available unless scripted otherwise. It is safe for
+// concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	results        map[string]vies.CheckResult
+	errs           map[string]string
+	availability   map[string][]vies.Availability
+	vowUnavailable bool
+	latency        time.Duration
+	countryLatency map[string]time.Duration
+	requests       []CheckRequest
+	consultations  int
+}
+
+// NewServer starts a Server, to be closed with Close.
+func NewServer() *Server {
+	s := &Server{
+		results:        make(map[string]vies.CheckResult),
+		errs:           make(map[string]string),
+		availability:   make(map[string][]vies.Availability),
+		countryLatency: make(map[string]time.Duration),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /check-vat-number", s.checkVat)
+	mux.HandleFunc("GET /check-status", s.checkStatus)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// EndpointUrl is the URL to give as vies.ClientConfig.EndpointUrl.
+func (s *Server) EndpointUrl() string {
+	return s.URL + "/"
+}
+
+// Client returns a vies.Client of the server, configured by opts too.
+func (s *Server) Client(opts ...vies.Option) (*vies.Client, error) {
+	return vies.New(append([]vies.Option{vies.WithEndpoint(s.EndpointUrl())}, opts...)...)
+}
+
+// SetValid makes vat valid, registered to name at address.
+func (s *Server) SetValid(vat, name, address string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := normalize(vat)
+	s.results[key] = vies.CheckResult{Valid: true, Name: name, Address: address}
+	delete(s.errs, key)
+}
+
+// SetInvalid makes vat invalid.
+func (s *Server) SetInvalid(vat string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := normalize(vat)
+	s.results[key] = vies.CheckResult{}
+	delete(s.errs, key)
+}
+
+// SetError makes the checks of vat fail with a VIES error code, such as
+// MS_UNAVAILABLE or INVALID_INPUT.
+func (s *Server) SetError(vat, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := normalize(vat)
+	s.errs[key] = code
+	delete(s.results, key)
+}
+
+// SetAvailability scripts the availability of a member state: each check
+// of its numbers, and each status, takes the next availability, the last
+// one holding from then on. Checks fail with MS_UNAVAILABLE while it is not
+// available.
+//
+//	// down for two checks, then back
+//	s.SetAvailability("DE", vies.AvailabilityUnavailable, vies.AvailabilityUnavailable, vies.AvailabilityAvailable)
+func (s *Server) SetAvailability(countryCode string, steps ...vies.Availability) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.availability[strings.ToUpper(countryCode)] = steps
+}
+
+// SetVowAvailable makes VIES as a whole available or not, checks failing
+// with SERVICE_UNAVAILABLE while it is not.
+func (s *Server) SetVowAvailable(available bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vowUnavailable = !available
+}
+
+// SetLatency delays every response by d, or only those of the checks of a
+// member state when countryCode is given.
+func (s *Server) SetLatency(d time.Duration, countryCode ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(countryCode) == 0 {
+		s.latency = d
+		return
+	}
+	for _, code := range countryCode {
+		s.countryLatency[strings.ToUpper(code)] = d
+	}
+}
+
+// Requests returns the check requests received so far, in order.
+func (s *Server) Requests() []CheckRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]CheckRequest(nil), s.requests...)
+}
+
+func (s *Server) checkVat(w http.ResponseWriter, r *http.Request) {
+
+	var req CheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, "INVALID_INPUT", err.Error())
+		return
+	}
+
+	countryCode := strings.ToUpper(req.CountryCode)
+	key := countryCode + normalize(req.VatNumber)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	delay := s.latency + s.countryLatency[countryCode]
+	availability := s.nextAvailability(countryCode)
+	vowUnavailable := s.vowUnavailable
+	result, programmed := s.results[key]
+	code, failing := s.errs[key]
+	consultation := ""
+	if req.RequesterNumber != "" {
+		s.consultations++
+		consultation = fmt.Sprintf("WAPIAAAA%08d", s.consultations)
+	}
+	s.mu.Unlock()
+
+	if !sleep(r.Context(), delay) {
+		return
+	}
+
+	if !programmed && !failing {
+		switch number := normalize(req.VatNumber); {
+		case number == TestValidNumber:
+			result, programmed = vies.CheckResult{Valid: true, Name: "TEST TRADER", Address: "TEST ADDRESS"}, true
+		case testNumbers[number] != "":
+			code, failing = testNumbers[number], true
+		}
+	}
+
+	switch {
+	case !viescountries.IsMember(countryCode):
+		writeError(w, "INVALID_INPUT", "unknown member state "+countryCode)
+	case vowUnavailable:
+		writeError(w, "SERVICE_UNAVAILABLE", "VIES is unavailable")
+	case availability != vies.AvailabilityAvailable:
+		writeError(w, "MS_UNAVAILABLE", "member state "+countryCode+" is unavailable")
+	case failing:
+		writeError(w, code, strings.ToLower(strings.ReplaceAll(code, "_", " ")))
+	default:
+		rsp := checkResponse{
+			CountryCode:       countryCode,
+			VatNumber:         req.VatNumber,
+			RequestDate:       time.Now().Format("2006-01-02-07:00"),
+			Valid:             result.Valid,
+			RequestIdentifier: consultation,
+			Name:              "---",
+			Address:           "---",
+		}
+		if result.Valid {
+			rsp.Name, rsp.Address = result.Name, result.Address
+		}
+		writeJSON(w, http.StatusOK, rsp)
+	}
+}
+
+func (s *Server) checkStatus(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	status := vies.Status{Vow: vies.StatusVow{Available: !s.vowUnavailable}}
+	for _, code := range viescountries.Codes() {
+		status.Countries = append(status.Countries, vies.CountryStatus{
+			CountryCode:  vies.CountryCode(code),
+			Availability: s.nextAvailability(code),
+		})
+	}
+	delay := s.latency
+	s.mu.Unlock()
+
+	if sleep(r.Context(), delay) {
+		writeJSON(w, http.StatusOK, status)
+	}
+}
+
+// nextAvailability takes the next scripted availability of countryCode,
+// s.mu being held.
+func (s *Server) nextAvailability(countryCode string) vies.Availability {
+	steps := s.availability[countryCode]
+	if len(steps) == 0 {
+		return vies.AvailabilityAvailable
+	}
+	if len(steps) > 1 {
+		s.availability[countryCode] = steps[1:]
+	}
+	return steps[0]
+}
+
+// sleep waits d unless the request is canceled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func writeError(w http.ResponseWriter, code, message string) {
+	status := http.StatusInternalServerError
+	if code == "INVALID_INPUT" || code == "INVALID_REQUESTER_INFO" {
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, errorResponse{ErrorWrappers: []errorWrapper{{Error: code, Message: message}}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// normalize returns vat in upper case without spaces, dots or dashes.
+func normalize(vat string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", ".", "", "-", "").Replace(vat))
+}
@@ -0,0 +1,114 @@
+package viestest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alytsin/go-vies"
+	"github.com/stretchr/testify/assert"
+)
+
+func apiErrorCode(err error) string {
+	var apiErr *vies.ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.Err
+	}
+	return ""
+}
+
+func TestServer(t *testing.T) {
+
+	s := NewServer()
+	defer s.Close()
+	s.SetValid("DE123456789", "ACME GmbH", "Berlin")
+	s.SetError("FR12345678901", "VAT_BLOCKED")
+
+	ctx := context.Background()
+	client, err := s.Client(vies.WithRequester("EE100354546"))
+	assert.NoError(t, err)
+
+	result, err := client.Check(ctx, "DE123456789")
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, "ACME GmbH", result.Name)
+	assert.Equal(t, "WAPIAAAA00000001", result.RequestIdentifier)
+	assert.Regexp(t, `^\d{4}-\d{2}-\d{2}[+-]\d{2}:\d{2}$`, result.RequestDate)
+
+	result, err = client.Check(ctx, "NL123456789B01")
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "---", result.Name)
+
+	_, err = client.Check(ctx, "FR12345678901")
+	assert.Equal(t, "VAT_BLOCKED", apiErrorCode(err))
+
+	// the numbers of the VIES test service
+	valid, err := client.Valid(ctx, "IT"+TestValidNumber)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = client.Valid(ctx, "IT"+TestInvalidNumber)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	_, err = client.Check(ctx, "IT201")
+	assert.Equal(t, "INVALID_INPUT", apiErrorCode(err))
+	_, err = client.Check(ctx, "IT600")
+	assert.Equal(t, "MS_MAX_CONCURRENT_REQ", apiErrorCode(err))
+
+	requests := s.Requests()
+	assert.Len(t, requests, 7)
+	assert.Equal(t, CheckRequest{CountryCode: "DE", VatNumber: "123456789", RequesterMemberStateCode: "EE", RequesterNumber: "100354546"}, requests[0])
+}
+
+func TestServerAvailability(t *testing.T) {
+
+	s := NewServer()
+	defer s.Close()
+	s.SetValid("DE123456789", "ACME GmbH", "Berlin")
+	s.SetAvailability("DE", vies.AvailabilityUnavailable, vies.AvailabilityAvailable)
+
+	ctx := context.Background()
+	client, err := s.Client()
+	assert.NoError(t, err)
+
+	_, err = client.Check(ctx, "DE123456789")
+	assert.Equal(t, "MS_UNAVAILABLE", apiErrorCode(err))
+	_, err = client.Check(ctx, "DE123456789")
+	assert.NoError(t, err)
+
+	s.SetAvailability("AT", vies.AvailabilityUnavailable)
+	status, err := client.Status(ctx)
+	assert.NoError(t, err)
+	assert.True(t, status.Vow.Available)
+	assert.Len(t, status.Countries, 28)
+	for _, country := range status.Countries {
+		if country.CountryCode == "AT" {
+			assert.Equal(t, vies.AvailabilityUnavailable, country.Availability)
+		}
+	}
+
+	s.SetVowAvailable(false)
+	_, err = client.Check(ctx, "DE123456789")
+	assert.Equal(t, "SERVICE_UNAVAILABLE", apiErrorCode(err))
+}
+
+func TestServerLatency(t *testing.T) {
+
+	s := NewServer()
+	defer s.Close()
+	s.SetLatency(50*time.Millisecond, "DE")
+
+	client, err := s.Client()
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Check(ctx, "DE123456789")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	start := time.Now()
+	_, err = client.Check(context.Background(), "FR12345678901")
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
@@ -0,0 +1,66 @@
+// Package viesvalidator registers VAT number tags with
+// go-playground/validator:
+//
+//	type Customer struct {
+//		Vat string `validate:"required,vies_format"`
+//	}
+//
+// vies_format checks the format offline, vies checks the number with a
+// registry such as vies.Client or vies.Router.
+package viesvalidator
+
+import (
+	"context"
+
+	"github.com/alytsin/go-vies"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	FormatTag = "vies_format"
+	OnlineTag = "vies"
+)
+
+type Config struct {
+	// Registry checks the numbers of the vies tag, which is not registered
+	// when Registry is nil.
+	Registry vies.RegistryInterface
+	// AllowUnavailable accepts numbers which could not be checked, for
+	// example while their member state is down, instead of rejecting them.
+	AllowUnavailable bool
+}
+
+// Register adds the vies_format tag, and the vies tag when a registry is
+// configured, to v. Use StructCtx to pass a context to the registry.
+func Register(v *validator.Validate, config *Config) error {
+
+	var registry vies.RegistryInterface
+	var allowUnavailable bool
+
+	if config != nil {
+		registry = config.Registry
+		allowUnavailable = config.AllowUnavailable
+	}
+
+	if err := v.RegisterValidation(FormatTag, func(fl validator.FieldLevel) bool {
+		return vies.ValidFormat(fl.Field().String())
+	}); err != nil {
+		return err
+	}
+
+	if registry == nil {
+		return nil
+	}
+
+	return v.RegisterValidationCtx(OnlineTag, func(ctx context.Context, fl validator.FieldLevel) bool {
+		vat := fl.Field().String()
+		if !vies.ValidFormat(vat) {
+			return false
+		}
+		result, err := registry.Check(ctx, vat)
+		if err != nil {
+			return allowUnavailable
+		}
+		return result.Valid
+	})
+}
@@ -0,0 +1,74 @@
+package viesvalidator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/alytsin/go-vies"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+type registryFunc func(ctx context.Context, vat string) (*vies.CheckResult, error)
+
+func (f registryFunc) Check(ctx context.Context, vat string, opts ...vies.CheckOption) (*vies.CheckResult, error) {
+	return f(ctx, vat)
+}
+
+type customer struct {
+	Vat   string         `validate:"vies_format"`
+	Typed vies.VatNumber `validate:"omitempty,vies_format"`
+}
+
+type order struct {
+	Vat string `validate:"vies"`
+}
+
+func TestFormatTag(t *testing.T) {
+
+	v := validator.New()
+	assert.NoError(t, Register(v, nil))
+
+	assert.NoError(t, v.Struct(customer{Vat: "EE100354546", Typed: "ATU12345678"}))
+
+	var errs validator.ValidationErrors
+	assert.True(t, errors.As(v.Struct(customer{Vat: "EE1003", Typed: "AT12345678"}), &errs))
+	assert.Len(t, errs, 2)
+	assert.Equal(t, FormatTag, errs[0].Tag())
+}
+
+func TestOnlineTag(t *testing.T) {
+
+	registry := registryFunc(func(ctx context.Context, vat string) (*vies.CheckResult, error) {
+		switch vat {
+		case "EE100354546":
+			return &vies.CheckResult{Valid: true}, nil
+		case "DE123456789":
+			return nil, &vies.ApiError{Err: "MS_UNAVAILABLE"}
+		}
+		return &vies.CheckResult{Valid: false}, nil
+	})
+
+	cases := []struct {
+		name             string
+		vat              string
+		allowUnavailable bool
+		valid            bool
+	}{
+		{name: "valid", vat: "EE100354546", valid: true},
+		{name: "not registered", vat: "EE100354547"},
+		{name: "bad format", vat: "EE1"},
+		{name: "unavailable", vat: "DE123456789"},
+		{name: "unavailable allowed", vat: "DE123456789", allowUnavailable: true, valid: true},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validator.New()
+			assert.NoError(t, Register(v, &Config{Registry: registry, AllowUnavailable: tt.allowUnavailable}))
+			err := v.StructCtx(context.Background(), order{Vat: tt.vat})
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
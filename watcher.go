@@ -0,0 +1,180 @@
+package vies
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	defaultWatchInterval = time.Minute
+	maxBackoffFactor     = 16
+)
+
+type WatcherConfig struct {
+	// Interval between two polls of Status, defaults to one minute.
+	Interval time.Duration
+	// MaxBackoff caps the delay after consecutive failed polls, which
+	// doubles with every failure. Defaults to 16 intervals.
+	MaxBackoff time.Duration
+}
+
+// StatusChangeFunc is called with the previous and the current status when
+// the availability of VIES or of a member state changes.
+type StatusChangeFunc func(previous, current *Status)
+
+// CountryChangeFunc is called for every change reported by Status.Diff.
+type CountryChangeFunc func(change CountryChange)
+
+// Watcher polls Status and notifies callbacks about availability changes.
+type Watcher struct {
+	client     *Client
+	interval   time.Duration
+	maxBackoff time.Duration
+
+	mu        sync.Mutex
+	last      *Status
+	onChange  []StatusChangeFunc
+	onPoll    []StatusChangeFunc
+	onCountry []CountryChangeFunc
+	onError   []func(error)
+	failures  int
+	lastErr   error
+}
+
+func NewWatcher(client *Client, config *WatcherConfig) *Watcher {
+
+	interval := defaultWatchInterval
+	var maxBackoff time.Duration
+
+	if config != nil {
+		if config.Interval > 0 {
+			interval = config.Interval
+		}
+		maxBackoff = config.MaxBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = interval * maxBackoffFactor
+	}
+
+	return &Watcher{
+		client:     client,
+		interval:   interval,
+		maxBackoff: maxBackoff,
+	}
+}
+
+// OnChange registers a callback invoked after a poll that found a change.
+func (w *Watcher) OnChange(fn StatusChangeFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onChange = append(w.onChange, fn)
+}
+
+// OnPoll registers a callback invoked after every successful poll, with
+// the status of the previous one, nil after the first poll.
+func (w *Watcher) OnPoll(fn StatusChangeFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onPoll = append(w.onPoll, fn)
+}
+
+// OnCountryChange registers a callback invoked once for every country
+// whose availability changed.
+func (w *Watcher) OnCountryChange(fn CountryChangeFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onCountry = append(w.onCountry, fn)
+}
+
+// OnError registers a callback invoked after every failed poll.
+func (w *Watcher) OnError(fn func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = append(w.onError, fn)
+}
+
+// Last returns the status of the last successful poll, nil before the
+// first one.
+func (w *Watcher) Last() *Status {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.last
+}
+
+// LastError returns the error of the last poll, nil when it succeeded.
+func (w *Watcher) LastError() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
+
+// Run polls until the context is done and returns its error.
+func (w *Watcher) Run(ctx context.Context) error {
+
+	var delay time.Duration
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.client.clock.After(delay):
+		}
+
+		delay = w.poll(ctx)
+	}
+}
+
+// poll fetches the status once and returns the delay until the next poll.
+func (w *Watcher) poll(ctx context.Context) time.Duration {
+
+	status, err := w.client.Status(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return 0
+		}
+		w.mu.Lock()
+		w.failures++
+		w.lastErr = err
+		callbacks := w.onError
+		delay := w.backoff()
+		w.mu.Unlock()
+
+		for _, fn := range callbacks {
+			fn(err)
+		}
+		return delay
+	}
+
+	w.mu.Lock()
+	previous := w.last
+	w.last = status
+	w.failures = 0
+	w.lastErr = nil
+	callbacks, countryCallbacks, pollCallbacks := w.onChange, w.onCountry, w.onPoll
+	w.mu.Unlock()
+
+	for _, fn := range pollCallbacks {
+		fn(previous, status)
+	}
+	changes := status.Diff(previous)
+	if len(changes) == 0 {
+		return w.interval
+	}
+	for _, fn := range callbacks {
+		fn(previous, status)
+	}
+	for _, change := range changes {
+		for _, fn := range countryCallbacks {
+			fn(change)
+		}
+	}
+	return w.interval
+}
+
+func (w *Watcher) backoff() time.Duration {
+	delay := w.interval
+	for i := 0; i < w.failures && delay < w.maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, w.maxBackoff)
+}
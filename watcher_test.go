@@ -0,0 +1,87 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher(t *testing.T) {
+	responses := []string{
+		`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"}]}`,
+		`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Available"}]}`,
+		`!`,
+		`{"vow":{"available":true},"countries":[{"countryCode":"DE","availability":"Unavailable"}]}`,
+	}
+
+	var mu sync.Mutex
+	calls := 0
+	client := NewTestClient(func(req *http.Request) *http.Response {
+		mu.Lock()
+		defer mu.Unlock()
+		body := responses[min(calls, len(responses)-1)]
+		calls++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(bytes.NewBufferString(body)),
+			Header:     make(http.Header),
+		}
+	})
+
+	v, err := NewClient(&ClientConfig{HttpClient: client, EndpointUrl: "https://example.com/"})
+	assert.NoError(t, err)
+
+	w := NewWatcher(v, &WatcherConfig{Interval: 5 * time.Millisecond})
+	assert.Nil(t, w.Last())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan [2]*Status, 1)
+	var pollErrors []error
+	var countryChanges []CountryChange
+	w.OnCountryChange(func(change CountryChange) {
+		countryChanges = append(countryChanges, change)
+	})
+	w.OnChange(func(previous, current *Status) {
+		changes <- [2]*Status{previous, current}
+		cancel()
+	})
+	w.OnError(func(err error) {
+		pollErrors = append(pollErrors, err)
+	})
+
+	assert.ErrorIs(t, w.Run(ctx), context.Canceled)
+
+	change := <-changes
+	assert.Equal(t, AvailabilityAvailable, change[0].Countries[0].Availability)
+	assert.Equal(t, AvailabilityUnavailable, change[1].Countries[0].Availability)
+	assert.Equal(t, change[1], w.Last())
+	assert.Len(t, pollErrors, 1)
+	assert.NoError(t, w.LastError())
+	assert.Equal(t, []CountryChange{{
+		CountryCode: "DE",
+		Kind:        BecameUnavailable,
+		Previous:    AvailabilityAvailable,
+		Current:     AvailabilityUnavailable,
+	}}, countryChanges)
+}
+
+func TestWatcherBackoff(t *testing.T) {
+	w := NewWatcher(nil, &WatcherConfig{Interval: time.Second, MaxBackoff: 5 * time.Second})
+
+	var delays []time.Duration
+	for range 4 {
+		w.failures++
+		delays = append(delays, w.backoff())
+	}
+	assert.Equal(t, []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, delays)
+
+	assert.Equal(t, 16*time.Minute, NewWatcher(nil, nil).maxBackoff)
+}
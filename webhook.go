@@ -0,0 +1,147 @@
+package vies
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader carries the hex encoded HMAC-SHA256 of the
+	// payload as "sha256=<signature>".
+	WebhookSignatureHeader = "X-Vies-Signature"
+
+	webhookEventValidityChange = "vat.validity_changed"
+
+	defaultWebhookAttempts = 5
+	defaultWebhookBackoff  = time.Second
+)
+
+type WebhookConfig struct {
+	URL string
+	// Secret signs every payload, see WebhookSignatureHeader.
+	Secret     []byte
+	HttpClient HttpClientInterface
+	// MaxAttempts is the number of deliveries tried before giving up,
+	// defaults to 5. Only transport errors, 429 and 5xx responses are
+	// retried.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled for every
+	// following one. Defaults to one second.
+	Backoff time.Duration
+	// Clock waits for the retries, the system clock when nil.
+	Clock ClockInterface
+}
+
+// Webhook posts signed JSON payloads to an HTTP endpoint.
+type Webhook struct {
+	url         string
+	secret      []byte
+	httpClient  HttpClientInterface
+	maxAttempts int
+	backoff     time.Duration
+	clock       ClockInterface
+}
+
+type webhookPayload struct {
+	Event string `json:"event"`
+	ValidityChange
+}
+
+func NewWebhook(config *WebhookConfig) (*Webhook, error) {
+
+	if config == nil || config.URL == "" {
+		return nil, errors.New("empty webhook URL provided")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, err
+	}
+
+	w := &Webhook{
+		url:         config.URL,
+		secret:      config.Secret,
+		httpClient:  config.HttpClient,
+		maxAttempts: config.MaxAttempts,
+		backoff:     config.Backoff,
+		clock:       clockOrSystem(config.Clock),
+	}
+	if w.httpClient == nil {
+		w.httpClient = defaultHttpClient(0)
+	}
+	if w.maxAttempts <= 0 {
+		w.maxAttempts = defaultWebhookAttempts
+	}
+	if w.backoff <= 0 {
+		w.backoff = defaultWebhookBackoff
+	}
+	return w, nil
+}
+
+// Notify delivers the change, it can be used as SchedulerConfig.OnChange
+// through a closure handling the error.
+func (w *Webhook) Notify(ctx context.Context, change ValidityChange) error {
+	return w.Post(ctx, webhookPayload{Event: webhookEventValidityChange, ValidityChange: change})
+}
+
+// Post delivers any JSON payload, retrying failed deliveries.
+func (w *Webhook) Post(ctx context.Context, payload any) error {
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	delay := w.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(ctx, body)
+		if err == nil || !retry || attempt == w.maxAttempts {
+			return err
+		}
+		if err := sleepUntil(ctx, w.clock, w.clock.Now().Add(delay)); err != nil {
+			return err
+		}
+		delay *= 2
+	}
+}
+
+func (w *Webhook) post(ctx context.Context, body []byte) (bool, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+WebhookSignature(w.secret, body))
+	}
+
+	rsp, err := w.httpClient.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer rsp.Body.Close()
+	_, _ = io.Copy(io.Discard, rsp.Body)
+
+	if rsp.StatusCode >= 200 && rsp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := rsp.StatusCode == http.StatusTooManyRequests || rsp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook responded with status %d", rsp.StatusCode)
+}
+
+// WebhookSignature returns the hex encoded HMAC-SHA256 of the payload,
+// receivers compare it with WebhookSignatureHeader using hmac.Equal.
+func WebhookSignature(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,102 @@
+package vies
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhook(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		codes    []int
+		err      string
+		attempts int
+	}{
+		{
+			name:     "delivered",
+			codes:    []int{http.StatusOK},
+			attempts: 1,
+		},
+		{
+			name:     "retried",
+			codes:    []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusNoContent},
+			attempts: 3,
+		},
+		{
+			name:     "rejected",
+			codes:    []int{http.StatusBadRequest},
+			err:      "webhook responded with status 400",
+			attempts: 1,
+		},
+		{
+			name:     "exhausted",
+			codes:    []int{http.StatusBadGateway},
+			err:      "webhook responded with status 502",
+			attempts: 4,
+		},
+	}
+
+	change := ValidityChange{
+		Vat:      "EE123",
+		Previous: &CheckResult{Vat: "EE123", Valid: true, Name: "ACME"},
+		Current:  &CheckResult{Vat: "EE123", Valid: false},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, "sha256="+WebhookSignature([]byte("secret"), body), r.Header.Get(WebhookSignatureHeader))
+
+				var payload map[string]any
+				assert.NoError(t, json.Unmarshal(body, &payload))
+				assert.Equal(t, "vat.validity_changed", payload["event"])
+				assert.Equal(t, "EE123", payload["vat"])
+
+				w.WriteHeader(tt.codes[min(attempts, len(tt.codes)-1)])
+				attempts++
+			}))
+			defer server.Close()
+
+			clock := newTestClock()
+			hook, err := NewWebhook(&WebhookConfig{
+				URL:         server.URL,
+				Secret:      []byte("secret"),
+				MaxAttempts: 4,
+				Backoff:     time.Second,
+				Clock:       clock,
+			})
+			assert.NoError(t, err)
+
+			err = hook.Notify(context.Background(), change)
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+			assert.Equal(t, tt.attempts, attempts)
+
+			var waits []time.Duration
+			for i := range tt.attempts - 1 {
+				waits = append(waits, time.Second<<i)
+			}
+			assert.Equal(t, waits, clock.Waits())
+		})
+	}
+
+	_, err := NewWebhook(nil)
+	assert.EqualError(t, err, "empty webhook URL provided")
+
+	hook, err := NewWebhook(&WebhookConfig{URL: "https://example.com/hook"})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultHttpTimeout, hook.httpClient.(*http.Client).Timeout)
+}